@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/taigrr/trophy/pkg/models"
+	"github.com/taigrr/trophy/pkg/render"
+)
+
+// Size of the single frame printed when stdout isn't a terminal
+const (
+	headlessCols = 80
+	headlessRows = 24
+)
+
+// renderHeadless draws one frame of the model in its default pose and
+// writes it to w as plain ASCII, so piping trophy never emits escape codes.
+func renderHeadless(mesh *models.Mesh, texture *render.Texture, w io.Writer) error {
+	fb := render.NewFramebuffer(headlessCols, headlessRows*2)
+	scene := render.NewScene(mesh)
+	scene.Camera = newViewCamera(fb.Width, fb.Height, render.NewAABB(mesh.BoundsMin, mesh.BoundsMax))
+	scene.Texture = texture
+	scene.Occlusion, scene.AOStrength = occlusionTexture(mesh)
+	scene.LightDir = NewViewState().LightDir
+	scene.Background = render.ColorBlack // Empty space prints as blanks
+	scene.Deterministic = true           // The same model always prints the same frame
+	scene.CellAspect = cellAspect
+	scene.RenderTo(fb)
+
+	if _, err := io.WriteString(w, fb.ASCII()); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+	return nil
+}
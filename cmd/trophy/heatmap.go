@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/taigrr/trophy/pkg/models"
+	"github.com/taigrr/trophy/pkg/render"
+)
+
+// heatmapScalars are the per-vertex measures the heatmap key cycles
+// through.
+var heatmapScalars = []struct {
+	Name    string
+	Compute func(*models.Mesh) []float64
+}{
+	{"curvature", (*models.Mesh).VertexCurvature},
+	{"face area", (*models.Mesh).VertexFaceArea},
+}
+
+// heatmapColors colors each of mesh's vertices by heatmap scalar s. The
+// ramp spans the lowest value to the 98th percentile, so a few outliers
+// don't wash out the rest.
+func heatmapColors(mesh *models.Mesh, s int) []render.Color {
+	values := heatmapScalars[s].Compute(mesh)
+	colors := make([]render.Color, len(values))
+	if len(values) == 0 {
+		return colors
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	lo, hi := sorted[0], sorted[(len(sorted)-1)*98/100]
+	for i, v := range values {
+		t := 0.0
+		if hi > lo {
+			t = (v - lo) / (hi - lo)
+		}
+		colors[i] = render.HeatColor(t)
+	}
+	return colors
+}
+
+// heatmapStatus describes the heatmap for the HUD.
+func heatmapStatus(mode RenderMode, s int) string {
+	if mode != RenderModeHeatmap {
+		return "Heatmap off"
+	}
+	return fmt.Sprintf("Heatmap: %s (blue low, red high)", heatmapScalars[s].Name)
+}
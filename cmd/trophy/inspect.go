@@ -0,0 +1,23 @@
+package main
+
+import "github.com/taigrr/trophy/pkg/render"
+
+// inspectTextures are the UV inspection textures the inspect key swaps in
+// for the model's own, in order. Even squares show where UVs stretch, and
+// the numbers show seams and mirrored or rotated islands.
+var inspectTextures = []struct {
+	Name    string
+	Texture *render.Texture
+}{
+	{"checker", render.NewCheckerTexture(256, 256, 16, render.RGB(235, 235, 235), render.RGB(45, 45, 45))},
+	{"numbered grid", render.NewGridTexture(256, 8)},
+}
+
+// inspectStatus describes the texture shown, with i indexing
+// inspectTextures from 1 and 0 for the model's own, for the HUD.
+func inspectStatus(i int) string {
+	if i == 0 {
+		return "Model texture"
+	}
+	return "UV " + inspectTextures[i-1].Name + " texture"
+}
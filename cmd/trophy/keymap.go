@@ -0,0 +1,402 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Keymap maps viewer actions to key strings as understood by
+// uv.KeyPressEvent.MatchString (e.g. "w", "up", "shift+r", "ctrl+h").
+// Fields can be overridden from keys.toml; omitted ones keep their defaults.
+// Bind shifted punctuation by the character it types, ">" rather than
+// "shift+.": MatchString reads "shift+." as the text ".", so it would also
+// match the unshifted key.
+type Keymap struct {
+	Quit        []string `toml:"quit"`
+	PitchUp     []string `toml:"pitch_up"`
+	PitchDown   []string `toml:"pitch_down"`
+	YawLeft     []string `toml:"yaw_left"`
+	YawRight    []string `toml:"yaw_right"`
+	RollLeft    []string `toml:"roll_left"`
+	RollRight   []string `toml:"roll_right"`
+	Reset       []string `toml:"reset"`
+	ResetRotate []string `toml:"reset_rotation"`
+	Spin        []string `toml:"spin"`
+	SpinFaster  []string `toml:"spin_faster"`
+	SpinSlower  []string `toml:"spin_slower"`
+	SpinAxis    []string `toml:"spin_axis"`
+	Pause       []string `toml:"pause"`
+	Step        []string `toml:"step"`
+	ZoomIn      []string `toml:"zoom_in"`
+	ZoomOut     []string `toml:"zoom_out"`
+	FOVWider    []string `toml:"fov_wider"`
+	FOVNarrower []string `toml:"fov_narrower"`
+	Brighter    []string `toml:"brighter"`
+	Dimmer      []string `toml:"dimmer"`
+	GammaUp     []string `toml:"gamma_up"`
+	GammaDown   []string `toml:"gamma_down"`
+	Texture     []string `toml:"texture"`
+	Inspect     []string `toml:"inspect_texture"`
+	Filter      []string `toml:"texture_filter"`
+	Wireframe   []string `toml:"wireframe"`
+	WireColor   []string `toml:"wire_color"`
+	FlatShading []string `toml:"flat_shading"`
+	SmoothAngle []string `toml:"smooth_angle"`
+	Heatmap     []string `toml:"heatmap"`
+	UVView      []string `toml:"uv_view"`
+	Light       []string `toml:"light"`
+	Headlamp    []string `toml:"headlamp"`
+	Backface    []string `toml:"backface"`
+	HUD         []string `toml:"hud"`
+	Help        []string `toml:"help"`
+	Stats       []string `toml:"stats"`
+	Measure     []string `toml:"measure"`
+	Screenshot  []string `toml:"screenshot"`
+	Record      []string `toml:"record"`
+	SubdivMore  []string `toml:"subdivide_more"`
+	SubdivLess  []string `toml:"subdivide_less"`
+	Smooth      []string `toml:"smooth"`
+	Unsmooth    []string `toml:"unsmooth"`
+	NextModel   []string `toml:"next_model"`
+	PrevModel   []string `toml:"prev_model"`
+	Parts       []string `toml:"parts"`
+	NextPart    []string `toml:"next_part"`
+	PrevPart    []string `toml:"prev_part"`
+	HidePart    []string `toml:"hide_part"`
+	IsolatePart []string `toml:"isolate_part"`
+	ExplodeMore []string `toml:"explode_more"`
+	ExplodeLess []string `toml:"explode_less"`
+	Section     []string `toml:"section"`
+	SectionBack []string `toml:"section_back"`
+	SectionFwd  []string `toml:"section_forward"`
+
+	// Views maps a ViewAngles name (e.g. "front") to its keys
+	Views map[string][]string `toml:"views"`
+}
+
+// DefaultKeymap returns the built-in bindings.
+func DefaultKeymap() *Keymap {
+	views := make(map[string][]string, len(ViewAngles))
+	for i, v := range ViewAngles {
+		views[v.Name] = []string{fmt.Sprint(i + 1)}
+	}
+
+	return &Keymap{
+		Quit:        []string{"escape"},
+		PitchUp:     []string{"w", "up"},
+		PitchDown:   []string{"s", "down"},
+		YawLeft:     []string{"a", "left"},
+		YawRight:    []string{"d", "right"},
+		RollLeft:    []string{"q"},
+		RollRight:   []string{"e"},
+		Reset:       []string{"r"},
+		ResetRotate: []string{"R", "shift+r"},
+		Spin:        []string{"space"},
+		SpinFaster:  []string{"]"},
+		SpinSlower:  []string{"["},
+		SpinAxis:    []string{"y"},
+		Pause:       []string{"P", "shift+p"},
+		Step:        []string{"N", "shift+n"},
+		ZoomIn:      []string{"+", "="},
+		ZoomOut:     []string{"-", "_"},
+		FOVWider:    []string{")"},
+		FOVNarrower: []string{"("},
+		Brighter:    []string{"}"},
+		Dimmer:      []string{"{"},
+		GammaUp:     []string{">"},
+		GammaDown:   []string{"<"},
+		Texture:     []string{"t"},
+		Inspect:     []string{"T", "shift+t"},
+		Filter:      []string{"B", "shift+b"},
+		Wireframe:   []string{"x"},
+		WireColor:   []string{"c"},
+		FlatShading: []string{"S", "shift+s"},
+		SmoothAngle: []string{"A", "shift+a"},
+		Heatmap:     []string{"H", "shift+h"},
+		UVView:      []string{"U", "shift+u"},
+		Light:       []string{"l"},
+		Headlamp:    []string{"g"},
+		Backface:    []string{"b"},
+		HUD:         []string{"?"},
+		Help:        []string{"h"},
+		Stats:       []string{"i"},
+		Measure:     []string{"m"},
+		Screenshot:  []string{"p"},
+		Record:      []string{"v"},
+		SubdivMore:  []string{"."},
+		SubdivLess:  []string{","},
+		Smooth:      []string{"n"},
+		Unsmooth:    []string{"u"},
+		NextModel:   []string{"tab", "pgdown"},
+		PrevModel:   []string{"shift+tab", "pgup"},
+		Parts:       []string{"o"},
+		NextPart:    []string{"j"},
+		PrevPart:    []string{"k"},
+		HidePart:    []string{"z"},
+		IsolatePart: []string{"Z", "shift+z"},
+		ExplodeMore: []string{"f"},
+		ExplodeLess: []string{"F", "shift+f"},
+		Section:     []string{"\\"},
+		SectionBack: []string{";"},
+		SectionFwd:  []string{"'"},
+		Views:       views,
+	}
+}
+
+// KeymapPath returns the keymap config location,
+// $XDG_CONFIG_HOME/trophy/keys.toml or ~/.config/trophy/keys.toml.
+func KeymapPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "trophy", "keys.toml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "trophy", "keys.toml"), nil
+}
+
+// LoadKeymap returns the default keymap with any overrides from path applied.
+// A missing file is not an error; a key left bound to two actions is.
+func LoadKeymap(path string) (*Keymap, error) {
+	km := DefaultKeymap()
+
+	md, err := toml.DecodeFile(path, km)
+	if errors.Is(err, fs.ErrNotExist) {
+		return km, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load keymap %s: %w", path, err)
+	}
+
+	// Catch typos in action names rather than silently ignoring them
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("load keymap %s: unknown action %q", path, undecoded[0].String())
+	}
+	for name := range km.Views {
+		if _, ok := viewAngleByName(name); !ok {
+			return nil, fmt.Errorf("load keymap %s: unknown view %q", path, name)
+		}
+	}
+	if err := km.conflict(); err != nil {
+		return nil, fmt.Errorf("load keymap %s: %w", path, err)
+	}
+	return km, nil
+}
+
+// keyPress returns what a key string matches as MatchString reads it: the
+// text of a printable key, uppercased when shifted, or else the keystroke
+// with its modifiers sorted. Strings with the same keyPress match the same
+// presses.
+func keyPress(s string) string {
+	parts := strings.Split(s, "+")
+	if strings.HasSuffix(s, "+") && len(parts) > 1 {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	key, mods := parts[len(parts)-1], parts[:len(parts)-1]
+
+	r, size := utf8.DecodeRuneInString(key)
+	if size == len(key) && unicode.IsPrint(r) {
+		switch {
+		case len(mods) == 0:
+			return key
+		case len(mods) == 1 && mods[0] == "shift":
+			return string(unicode.ToUpper(r))
+		}
+	}
+	mods = slices.Sorted(slices.Values(mods))
+	return strings.Join(append(mods, key), "+")
+}
+
+// conflict returns an error naming the first key bound to two actions, or
+// nil if there's none. The viewer checks actions in a fixed order, so such
+// a key only ever does one of them.
+func (k *Keymap) conflict() error {
+	bound := make(map[string]string)
+	for _, b := range k.Bindings() {
+		for _, key := range b.Keys {
+			press := keyPress(key)
+			if action, ok := bound[press]; ok && action != b.Action {
+				return fmt.Errorf("key %q is bound to both %q and %q", key, action, b.Action)
+			}
+			bound[press] = b.Action
+		}
+	}
+	return nil
+}
+
+// Binding is one row of the help overlay.
+type Binding struct {
+	Action string
+	Keys   []string
+}
+
+// Bindings lists every action with its current keys, in display order.
+func (k *Keymap) Bindings() []Binding {
+	bindings := []Binding{
+		{"Pitch up", k.PitchUp},
+		{"Pitch down", k.PitchDown},
+		{"Yaw left", k.YawLeft},
+		{"Yaw right", k.YawRight},
+		{"Roll left", k.RollLeft},
+		{"Roll right", k.RollRight},
+		{"Zoom in", k.ZoomIn},
+		{"Zoom out", k.ZoomOut},
+		{"Widen field of view", k.FOVWider},
+		{"Narrow field of view", k.FOVNarrower},
+		{"Brighter display", k.Brighter},
+		{"Dimmer display", k.Dimmer},
+		{"Raise display gamma", k.GammaUp},
+		{"Lower display gamma", k.GammaDown},
+		{"Toggle spin", k.Spin},
+		{"Spin faster", k.SpinFaster},
+		{"Spin slower", k.SpinSlower},
+		{"Cycle spin axis", k.SpinAxis},
+		{"Pause/resume motion", k.Pause},
+		{"Step one frame", k.Step},
+		{"Reset view, light, and modes", k.Reset},
+		{"Reset rotation and zoom", k.ResetRotate},
+		{"Toggle texture", k.Texture},
+		{"Cycle UV inspection textures", k.Inspect},
+		{"Toggle bilinear filtering", k.Filter},
+		{"Toggle wireframe", k.Wireframe},
+		{"Cycle wireframe colors", k.WireColor},
+		{"Toggle flat shading", k.FlatShading},
+		{"Cycle smoothing angle", k.SmoothAngle},
+		{"Cycle heatmaps", k.Heatmap},
+		{"Toggle UV layout", k.UVView},
+		{"Toggle backface cull", k.Backface},
+		{"Position light", k.Light},
+		{"Toggle headlamp", k.Headlamp},
+		{"Toggle HUD", k.HUD},
+		{"Toggle render stats", k.Stats},
+		{"Measure distance", k.Measure},
+		{"Save screenshot", k.Screenshot},
+		{"Start/stop GIF recording", k.Record},
+		{"Subdivide more", k.SubdivMore},
+		{"Subdivide less", k.SubdivLess},
+		{"Smooth surface", k.Smooth},
+		{"Undo smoothing", k.Unsmooth},
+		{"Next model", k.NextModel},
+		{"Previous model", k.PrevModel},
+		{"Show model parts", k.Parts},
+		{"Select next part", k.NextPart},
+		{"Select previous part", k.PrevPart},
+		{"Hide/show part", k.HidePart},
+		{"Isolate part", k.IsolatePart},
+		{"Explode parts", k.ExplodeMore},
+		{"Unexplode parts", k.ExplodeLess},
+		{"Cycle section plane", k.Section},
+		{"Move section back", k.SectionBack},
+		{"Move section forward", k.SectionFwd},
+		{"Show more help, then close", k.Help},
+	}
+	for _, v := range ViewAngles {
+		bindings = append(bindings, Binding{"View " + v.Name, k.Views[v.Name]})
+	}
+	return append(bindings, Binding{"Quit", k.Quit})
+}
+
+// ViewFor returns the canonical view bound to the pressed key.
+func (k *Keymap) ViewFor(ev uv.KeyPressEvent) (ViewAngle, bool) {
+	for _, v := range ViewAngles {
+		if ev.MatchString(k.Views[v.Name]...) {
+			return v, true
+		}
+	}
+	return ViewAngle{}, false
+}
+
+// RenderHelp draws a centered box listing the current bindings, in as many
+// columns as fit across the screen and pages of as many rows as fit down it.
+// Returns the first and last rows it drew on and how many pages the
+// bindings take; page is clamped to the last.
+func (k *Keymap) RenderHelp(scr uv.Screen, width, height, page int) (top, bottom, pages int) {
+	const (
+		reset   = "\x1b[0m"
+		bold    = "\x1b[1m"
+		bgBlack = "\x1b[40m"
+		fgWhite = "\x1b[97m"
+		fgCyan  = "\x1b[96m"
+	)
+	const maxKeyWidth = 14
+
+	// Every cell is " <keys> <action> ", padded to the longest of each so
+	// the columns line up and the box has straight edges
+	bindings := k.Bindings()
+	keys := make([]string, len(bindings))
+	keyWidth, actionWidth := 0, 0
+	for i, b := range bindings {
+		keys[i] = strings.Join(b.Keys, ", ")
+		if keys[i] == "" {
+			keys[i] = "(unbound)"
+		}
+		keys[i] = ansi.Truncate(keys[i], maxKeyWidth, "…")
+		keyWidth = max(keyWidth, ansi.StringWidth(keys[i]))
+		actionWidth = max(actionWidth, ansi.StringWidth(b.Action))
+	}
+	actionWidth = max(min(actionWidth, width-keyWidth-3), 1) // Too narrow for one column
+	cellWidth := keyWidth + actionWidth + 3
+
+	// Fill columns top to bottom below the header, using only the rows the
+	// page needs
+	rows := max(height-1, 1)
+	cols := max(width/cellWidth, 1)
+	perPage := rows * cols
+	pages = (len(bindings) + perPage - 1) / perPage
+	page = max(min(page, pages-1), 0)
+	first := page * perPage
+	n := min(perPage, len(bindings)-first)
+	rows = min(rows, n)
+	cols = (n + rows - 1) / rows
+	boxWidth := cols * cellWidth
+
+	header := "Keys"
+	if pages > 1 && len(k.Help) > 0 {
+		header = fmt.Sprintf("Keys (page %d of %d, %s for more)", page+1, pages, k.Help[0])
+	}
+	header = ansi.Truncate(header, max(boxWidth-2, 1), "…")
+	lines := make([]string, 0, rows+1)
+	lines = append(lines, fmt.Sprintf("%s%s%s %s%s %s", bgBlack, bold, fgWhite,
+		header, strings.Repeat(" ", max(boxWidth-2-ansi.StringWidth(header), 0)), reset))
+	for r := range rows {
+		var line strings.Builder
+		line.WriteString(bgBlack)
+		for c := range cols {
+			i := first + c*rows + r
+			if i >= first+n {
+				line.WriteString(strings.Repeat(" ", cellWidth))
+				continue
+			}
+			action := ansi.Truncate(bindings[i].Action, actionWidth, "…")
+			fmt.Fprintf(&line, "%s %s%s %s%s%s ",
+				fgCyan, keys[i], strings.Repeat(" ", keyWidth-ansi.StringWidth(keys[i])),
+				fgWhite, action, strings.Repeat(" ", actionWidth-ansi.StringWidth(action)))
+		}
+		line.WriteString(reset)
+		lines = append(lines, line.String())
+	}
+
+	top = max((height-len(lines))/2, 0)
+	col := max((width-boxWidth)/2, 0)
+	for i, line := range lines {
+		row := top + i
+		if row >= height {
+			break
+		}
+		drawText(scr, col, row, line)
+		bottom = row
+	}
+	return top, bottom, pages
+}
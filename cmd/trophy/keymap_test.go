@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+func TestKeyPress(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"shift+.", ".", true},
+		{">", ".", false},
+		{"shift+r", "R", true},
+		{"r", "R", false},
+		{"shift+/", "/", true},
+		{"ctrl+shift+a", "shift+ctrl+a", true},
+		{"ctrl+a", "a", false},
+		{"+", "ctrl++", false},
+		{"shift+tab", "tab", false},
+	}
+	for _, tt := range tests {
+		if same := keyPress(tt.a) == keyPress(tt.b); same != tt.same {
+			t.Errorf("%q and %q match the same press = %v, want %v", tt.a, tt.b, same, tt.same)
+		}
+	}
+}
+
+func TestDefaultKeymapConflicts(t *testing.T) {
+	if err := DefaultKeymap().conflict(); err != nil {
+		t.Fatal(err)
+	}
+
+	km := DefaultKeymap()
+	km.GammaUp = append(km.GammaUp, "shift+.")
+	if err := km.conflict(); err == nil {
+		t.Error("shift+. for gamma didn't conflict with . for subdivision")
+	}
+}
+
+func TestLoadKeymap(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string // Contents of keys.toml; empty for no file
+		check   func(*Keymap) bool
+		wantErr string
+	}{
+		{"missing file", "", func(km *Keymap) bool { return slices.Equal(km.Quit, []string{"escape"}) }, ""},
+		{"override", `quit = ["ctrl+q"]`, func(km *Keymap) bool { return slices.Equal(km.Quit, []string{"ctrl+q"}) }, ""},
+		{"view", "[views]\nfront = [\"0\"]", func(km *Keymap) bool { return slices.Equal(km.Views["front"], []string{"0"}) }, ""},
+		{"moved off a key", "wireframe = [\"W\"]\nscreenshot = [\"x\"]", func(km *Keymap) bool { return slices.Equal(km.Screenshot, []string{"x"}) }, ""},
+		{"vim style", "pitch_up = [\"k\"]\npitch_down = [\"j\"]\nyaw_left = [\"h\"]\nyaw_right = [\"l\"]\n" +
+			"next_part = [\"ctrl+j\"]\nprev_part = [\"ctrl+k\"]\nlight = [\"ctrl+l\"]\nhelp = [\"f1\"]",
+			func(km *Keymap) bool { return slices.Equal(km.YawLeft, []string{"h"}) }, ""},
+		{"unknown action", `explode = ["e"]`, nil, "unknown action"},
+		{"unknown view", "[views]\nside = [\"0\"]", nil, "unknown view"},
+		{"conflict", `screenshot = ["x"]`, nil, "bound to both"},
+		{"shifted conflict", `gamma_up = ["shift+."]`, nil, "bound to both"},
+		{"bad toml", `quit = `, nil, "load keymap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.toml")
+			if tt.config != "" {
+				if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			km, err := LoadKeymap(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(km) {
+				t.Error("keymap doesn't have the configured keys")
+			}
+		})
+	}
+}
+
+func TestRenderHelp(t *testing.T) {
+	km := DefaultKeymap()
+	bindings := km.Bindings()
+	for _, size := range []struct{ width, height int }{{200, 60}, {80, 24}, {40, 10}} {
+		shown := make(map[string]bool)
+		pages := 1
+		for page := 0; page < pages; page++ {
+			scr := uv.NewScreenBuffer(size.width, size.height)
+			blank := &uv.Cell{Content: "·", Width: 1}
+			for y := range size.height {
+				for x := range size.width {
+					scr.SetCell(x, y, blank)
+				}
+			}
+			var top, bottom int
+			top, bottom, pages = km.RenderHelp(scr, size.width, size.height, page)
+
+			// Every row of the box spans the same columns
+			left, right := -1, -1
+			for y := top; y <= bottom; y++ {
+				var row strings.Builder
+				l, r := -1, -1
+				for x := range size.width {
+					c := scr.CellAt(x, y).Content
+					if c != "·" {
+						if l < 0 {
+							l = x
+						}
+						r = x
+					}
+					row.WriteString(c)
+				}
+				if y == top {
+					left, right = l, r
+				} else if l != left || r != right {
+					t.Fatalf("%dx%d page %d: row %d spans %d-%d, want %d-%d", size.width, size.height, page, y, l, r, left, right)
+				}
+				for _, b := range bindings {
+					if strings.Contains(row.String(), " "+b.Action+" ") {
+						shown[b.Action] = true
+					}
+				}
+			}
+		}
+		if size.width < 80 {
+			continue // Actions are shortened to fit
+		}
+		for _, b := range bindings {
+			if !shown[b.Action] {
+				t.Errorf("%dx%d: %q not shown on any of %d pages", size.width, size.height, b.Action, pages)
+			}
+		}
+	}
+}
@@ -1,38 +1,33 @@
 // trophy - Terminal 3D Model Viewer
 // View OBJ and GLB files in your terminal with full 3D rendering.
 //
-// Controls:
-//
-//	Mouse drag  - Rotate model (yaw/pitch)
-//	Scroll      - Zoom in/out
-//	W/S         - Pitch up/down
-//	A/D         - Yaw left/right
-//	Q/E         - Roll left/right (Q rolls left, E rolls right)
-//	Space       - Apply random impulse
-//	R           - Reset rotation
-//	T           - Toggle texture on/off
-//	X           - Toggle wireframe mode (x-ray)
-//	L           - Light positioning mode (move mouse, click to set, Esc to cancel)
-//	?           - Toggle HUD overlay (FPS, filename, poly count, mode status)
-//	+/-         - Adjust zoom
-//	Esc         - Quit (or cancel light mode)
+// Run trophy --help for the controls, or press H in the viewer for the key
+// bindings in effect, which can be remapped in ~/.config/trophy/keys.toml.
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"image"
+	"io"
+	"io/fs"
 	"math"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/charmbracelet/fang"
 	"github.com/charmbracelet/harmonica"
 	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+	xterm "github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
 	"github.com/taigrr/trophy/pkg/math3d"
 	"github.com/taigrr/trophy/pkg/models"
@@ -43,37 +38,115 @@ var (
 	texturePath string
 	targetFPS   int
 	bgColor     string
+	rotateMode  string
+	maxTexSize  int
+	filterName  string
+	idleFPS     int
+	spinAxis    string
+	spinSpeed   float64
+	headlamp    bool
+	decimate    float64
+	anchorName  string
+	unitName    string
+	antialias   bool
+	wireWidth   int
+	fovDegrees  float64
+	paletteName string
+	glyphName   string
+	cellAspect  float64
+	gamma       float64
+	brightness  float64
+	recurse     bool
+	smoothAngle float64
 )
 
 func main() {
 	cmd := &cobra.Command{
-		Use:   "trophy <model.obj|model.glb|model.stl>",
+		Use:   "trophy <model.obj|model.glb|model.stl|directory|url|->...",
 		Short: "Terminal 3D Model Viewer",
 		Long: `trophy - Terminal 3D Model Viewer
 
-View OBJ and GLB files in your terminal with full 3D rendering.
+View OBJ and GLB files in your terminal with full 3D rendering. Give
+several models, or a directory of them, to switch between them in one
+session. A path of - reads a model from stdin; http(s) URLs are downloaded.
 
 Controls:
   Mouse drag  - Rotate model
-  Scroll      - Zoom in/out
+  Middle drag - Pan view (or Shift+drag)
+  Click       - Identify the face under the cursor
+  M           - Measure mode (click two points on the surface)
+  P           - Save a screenshot PNG to the current directory
+  V           - Start/stop recording a GIF to the current directory
+  . / ,       - Subdivide more/less
+  N / U       - Smooth surface / undo smoothing
+  Scroll      - Zoom toward the cursor
+  ( / )       - Narrow/widen the field of view
+  { / }       - Dim/brighten the display
+  < / >       - Lower/raise the display gamma
   W/S/A/D     - Pitch and yaw
   Q/E         - Roll left/right
-  Space       - Random spin
-  R           - Reset view
+  Space       - Toggle auto-spin
+  Shift+P     - Pause/resume motion (Shift+N steps one frame while paused)
+  [ / ]       - Spin slower/faster
+  Y           - Cycle spin axis
+  R           - Reset the view, light, and render modes (Shift+R: rotation only)
+  O           - Show the model's parts (J/K select, Z hides, Shift+Z isolates)
+  F / Shift+F - Explode the parts apart / bring them back together
+  \           - Cycle the section plane (off, X, Y, Z)
+  ; / '       - Move the section plane back/forward
+  Tab/PgDn    - Next model (Shift+Tab/PgUp for the previous one)
+  1-7         - Front/back/left/right/top/bottom/isometric
   T           - Toggle texture
+  Shift+B     - Toggle bilinear texture filtering
+  Shift+T     - Cycle UV inspection textures (checker, numbered grid, off)
   X           - Toggle wireframe
+  B           - Toggle backface culling
+  C           - Cycle wireframe colors (solid, material, normal)
+  Shift+S     - Toggle flat shading (by face normal) and smooth shading
+  Shift+A     - Cycle the normal smoothing angle (30°-180°, or the model's normals)
+  Shift+H     - Cycle heatmaps (curvature, face area, off)
+  Shift+U     - Toggle the UV layout view
   L           - Position light (mouse to aim, click to set)
+  G           - Toggle headlamp (light follows the camera)
   ?           - Toggle HUD overlay
-  Esc         - Quit`,
-		Args: cobra.ExactArgs(1),
+  I           - Toggle render stats
+  H           - Show key bindings (again for the next page)
+  Esc         - Quit
+
+Keys can be remapped in ~/.config/trophy/keys.toml.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run(args[0])
+			paths, err := expandModelPaths(args, recurse)
+			if err != nil {
+				return err
+			}
+			return run(paths)
 		},
 	}
 
 	cmd.Flags().StringVar(&texturePath, "texture", "", "Path to texture image (PNG/JPG)")
 	cmd.Flags().IntVar(&targetFPS, "fps", 60, "Target FPS")
-	cmd.Flags().StringVar(&bgColor, "bg", "30,30,40", "Background color (R,G,B)")
+	cmd.Flags().IntVar(&idleFPS, "idle-fps", 5, "Frame rate when nothing is moving (0 = always use --fps)")
+	cmd.Flags().StringVar(&bgColor, "bg", "30,30,40", "Background color (R,G,B), or transparent to show the terminal's own")
+	cmd.Flags().StringVar(&rotateMode, "rotate", "euler", "Rotation mode: euler or trackball")
+	cmd.Flags().StringVar(&spinAxis, "spin", "yaw", "Auto-spin axis: yaw, pitch, roll, or x,y,z")
+	cmd.Flags().Float64Var(&spinSpeed, "spin-speed", defaultSpinSpeed, "Auto-spin speed in radians per second")
+	cmd.Flags().Float64Var(&fovDegrees, "fov", defaultFOV, fmt.Sprintf("Vertical field of view in degrees (%g-%g)", minFOV, maxFOV))
+	cmd.Flags().Float64Var(&gamma, "gamma", 1, fmt.Sprintf("Display gamma; above 1 lifts dark tones (%g-%g)", minGamma, maxGamma))
+	cmd.Flags().Float64Var(&brightness, "brightness", 1, fmt.Sprintf("Display brightness multiplier (%g-%g)", minBrightness, maxBrightness))
+	cmd.Flags().BoolVar(&headlamp, "headlamp", false, "Light the model from the camera")
+	cmd.Flags().StringVar(&paletteName, "palette", "default", "Overlay colors: default, or colorblind for a color-blind-safe set")
+	cmd.Flags().StringVar(&glyphName, "glyph", "upper", "Cell character: upper (▀), lower (▄) for fonts with a seam below ▀, or full (█, half the vertical detail)")
+	cmd.Flags().Float64Var(&cellAspect, "cell-aspect", render.DefaultCellAspect, fmt.Sprintf("Height of a terminal cell over its width, to keep shapes true in fonts that aren't 2:1 (%g-%g)", minCellAspect, maxCellAspect))
+	cmd.Flags().BoolVar(&antialias, "aa", false, "Anti-alias wireframe and line edges")
+	cmd.Flags().IntVar(&wireWidth, "wire-width", 1, "Wireframe and line width in pixels (2-3 reads better on large terminals)")
+	cmd.Flags().IntVar(&maxTexSize, "max-texture-size", 512, "Downsample textures larger than this on either side (0 = no limit)")
+	cmd.Flags().StringVar(&filterName, "filter", "bilinear", "Texture filtering: bilinear (smooth), or nearest for crisp texels")
+	cmd.Flags().Float64Var(&decimate, "decimate", 1, "Simplify the model to this fraction of its triangles (e.g. 0.25)")
+	cmd.Flags().StringVar(&unitName, "unit", "", "Unit the model is authored in, for displayed sizes (default: m for glTF, none otherwise)")
+	cmd.Flags().BoolVar(&recurse, "recurse", false, "Include models in subdirectories of a directory argument")
+	cmd.Flags().Float64Var(&smoothAngle, "smooth-angle", 0, "Recompute normals, smoothing between faces less than this many degrees apart (0 = keep the model's)")
+	cmd.Flags().StringVar(&anchorName, "anchor", "center", "Point the model pivots about: center, bottom (e.g. a character's feet), or origin")
 
 	// Add info subcommand
 	infoCmd := &cobra.Command{
@@ -85,8 +158,23 @@ Controls:
 			return runInfo(args[0])
 		},
 	}
+	infoCmd.Flags().StringVar(&unitName, "unit", "", "Unit the model is authored in (default: m for glTF, none otherwise)")
 	cmd.AddCommand(infoCmd)
 
+	// Add convert subcommand
+	convertCmd := &cobra.Command{
+		Use:   "convert <model.obj|model.glb|model.stl> <output.obj>",
+		Short: "Convert a model to OBJ",
+		Long:  "Convert a model to Wavefront OBJ, optionally simplifying it with --decimate. Geometry, texture coordinates, and normals are kept; materials are not.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConvert(args[0], args[1])
+		},
+	}
+	convertCmd.Flags().Float64Var(&decimate, "decimate", 1, "Simplify the model to this fraction of its triangles (e.g. 0.25)")
+	convertCmd.Flags().Float64Var(&smoothAngle, "smooth-angle", 0, "Recompute normals, smoothing between faces less than this many degrees apart (0 = keep the model's)")
+	cmd.AddCommand(convertCmd)
+
 	if err := fang.Execute(context.Background(), cmd); err != nil {
 		os.Exit(1)
 	}
@@ -141,12 +229,29 @@ func runInfo(modelPath string) error {
 	fmt.Printf("Size:       %.2f KB\n", float64(info.Size())/1024)
 	fmt.Println()
 	fmt.Printf("Vertices:   %d\n", mesh.VertexCount())
+	if dup := mesh.Clone().Reindex(); dup > 0 {
+		fmt.Printf("Duplicates: %d vertices identical to another (unshared)\n", dup)
+	}
 	fmt.Printf("Triangles:  %d\n", mesh.TriangleCount())
+	if mesh.LineCount() > 0 {
+		fmt.Printf("Lines:      %d\n", mesh.LineCount())
+	}
+	if mesh.PointCount() > 0 {
+		fmt.Printf("Points:     %d\n", mesh.PointCount())
+	}
 	fmt.Println()
-	fmt.Printf("Bounds Min: (%.3f, %.3f, %.3f)\n", mesh.BoundsMin.X, mesh.BoundsMin.Y, mesh.BoundsMin.Z)
-	fmt.Printf("Bounds Max: (%.3f, %.3f, %.3f)\n", mesh.BoundsMax.X, mesh.BoundsMax.Y, mesh.BoundsMax.Z)
-	fmt.Printf("Dimensions: %.3f x %.3f x %.3f\n", size.X, size.Y, size.Z)
-	fmt.Printf("Center:     (%.3f, %.3f, %.3f)\n", center.X, center.Y, center.Z)
+	if mesh.VertexCount() == 0 {
+		fmt.Println("Bounds:     (empty)")
+	} else {
+		fmt.Printf("Bounds Min: (%.3f, %.3f, %.3f)\n", mesh.BoundsMin.X, mesh.BoundsMin.Y, mesh.BoundsMin.Z)
+		fmt.Printf("Bounds Max: (%.3f, %.3f, %.3f)\n", mesh.BoundsMax.X, mesh.BoundsMax.Y, mesh.BoundsMax.Z)
+		fmt.Printf("Dimensions: %s\n", formatSize(size, lengthUnit(modelPath)))
+		fmt.Printf("Center:     (%.3f, %.3f, %.3f)\n", center.X, center.Y, center.Z)
+	}
+	if mesh.IsEmpty() {
+		fmt.Println()
+		fmt.Println("Warning:    no triangles, lines, or points; nothing to render")
+	}
 
 	if hasEmbeddedTexture {
 		fmt.Println()
@@ -156,33 +261,56 @@ func runInfo(modelPath string) error {
 	return nil
 }
 
-// RotationAxis tracks position and velocity for one rotation axis with spring decay
+// Spring constants for velocity decay: frequency 4.0 = moderate speed,
+// damping 1.0 = critically damped (no overshoot)
+const (
+	decayFrequency = 4.0
+	decayDamping   = 1.0
+)
+
+// RotationAxis tracks position and velocity for one rotation axis with spring decay.
+// Velocity is in radians per frame at the nominal fps; Update scales it by the
+// real frame time so motion speed doesn't depend on the achieved frame rate.
 type RotationAxis struct {
-	Position  float64
-	Velocity  float64
-	velSpring harmonica.Spring
-	velAccel  float64 // internal spring velocity (for animating Velocity toward 0)
+	Position float64
+	Velocity float64
+	velAccel float64 // internal spring velocity (for animating Velocity toward 0)
+	fps      int
 }
 
-// NewRotationAxis creates an axis with harmonica spring for smooth velocity decay
+// NewRotationAxis creates an axis whose velocity is measured in frames at fps
 func NewRotationAxis(fps int) RotationAxis {
-	return RotationAxis{
-		// Frequency 4.0 = moderate speed, damping 1.0 = critically damped (no overshoot)
-		velSpring: harmonica.NewSpring(harmonica.FPS(fps), 4.0, 1.0),
-	}
+	return RotationAxis{fps: fps}
 }
 
-// Update applies velocity to position and decays velocity toward 0 using spring
-func (a *RotationAxis) Update(damping bool) {
+// Update advances the axis by dt seconds, applying velocity to position and
+// decaying velocity toward 0 using a spring
+func (a *RotationAxis) Update(dt float64, damping bool) {
 	// Apply velocity to position
-	a.Position += a.Velocity
+	a.Position += a.Velocity * a.frames(dt)
 
-	// Use spring to animate velocity toward 0 (smooth deceleration)
+	// Use spring to animate velocity toward 0 (smooth deceleration). The
+	// spring is built for this frame's dt so decay takes the same wall time
+	// at any frame rate.
 	if damping {
-		a.Velocity, a.velAccel = a.velSpring.Update(a.Velocity, a.velAccel, 0)
+		spring := harmonica.NewSpring(dt, decayFrequency, decayDamping)
+		a.Velocity, a.velAccel = spring.Update(a.Velocity, a.velAccel, 0)
 	}
 }
 
+// frames converts dt seconds to a number of nominal frames
+func (a *RotationAxis) frames(dt float64) float64 {
+	return dt * float64(a.fps)
+}
+
+// restVelocity is the speed (radians per frame) below which an axis counts as stopped
+const restVelocity = 1e-4
+
+// Moving reports whether the axis is still turning visibly
+func (a *RotationAxis) Moving() bool {
+	return math.Abs(a.Velocity) > restVelocity
+}
+
 // RotationState holds rotation with harmonica spring physics
 type RotationState struct {
 	Pitch, Yaw, Roll RotationAxis
@@ -198,10 +326,10 @@ func NewRotationState(fps int) *RotationState {
 	}
 }
 
-func (r *RotationState) Update(damping bool) {
-	r.Pitch.Update(damping)
-	r.Yaw.Update(damping)
-	r.Roll.Update(damping)
+func (r *RotationState) Update(dt float64, damping bool) {
+	r.Pitch.Update(dt, damping)
+	r.Yaw.Update(dt, damping)
+	r.Roll.Update(dt, damping)
 }
 
 func (r *RotationState) ApplyImpulse(pitch, yaw, roll float64) {
@@ -210,12 +338,179 @@ func (r *RotationState) ApplyImpulse(pitch, yaw, roll float64) {
 	r.Roll.Velocity += roll
 }
 
+// Turn rotates by the given angles at once, leaving velocity alone
+func (r *RotationState) Turn(pitch, yaw, roll float64) {
+	r.Pitch.Position += pitch
+	r.Yaw.Position += yaw
+	r.Roll.Position += roll
+}
+
 func (r *RotationState) Reset() {
 	r.Pitch = NewRotationAxis(r.fps)
 	r.Yaw = NewRotationAxis(r.fps)
 	r.Roll = NewRotationAxis(r.fps)
 }
 
+// SetSpin sets a constant turn about axis at speed radians per second
+func (r *RotationState) SetSpin(axis math3d.Vec3, speed float64) {
+	v := axis.Normalize().Scale(speed / float64(r.fps))
+	r.Pitch.Velocity, r.Yaw.Velocity, r.Roll.Velocity = v.X, v.Y, v.Z
+}
+
+// SnapTo jumps to exact angles and stops all motion
+func (r *RotationState) SnapTo(pitch, yaw, roll float64) {
+	r.Reset()
+	r.Pitch.Position = pitch
+	r.Yaw.Position = yaw
+	r.Roll.Position = roll
+}
+
+// Moving reports whether any axis still has noticeable velocity
+func (r *RotationState) Moving() bool {
+	return r.Pitch.Moving() || r.Yaw.Moving() || r.Roll.Moving()
+}
+
+// Matrix builds the model rotation from the accumulated Euler angles
+func (r *RotationState) Matrix() math3d.Mat4 {
+	return math3d.RotateX(r.Pitch.Position).
+		Mul(math3d.RotateY(r.Yaw.Position)).
+		Mul(math3d.RotateZ(r.Roll.Position))
+}
+
+// Rotator drives the model orientation from user input
+type Rotator interface {
+	ApplyImpulse(pitch, yaw, roll float64)
+	Update(dt float64, damping bool) // Advance by dt seconds
+	Turn(pitch, yaw, roll float64)   // Rotate by radians at once, without physics
+	Reset()
+	SetSpin(axis math3d.Vec3, speed float64) // speed in radians per second
+	SnapTo(pitch, yaw, roll float64)
+	Matrix() math3d.Mat4
+	Moving() bool // Whether the orientation is still changing
+}
+
+// ViewAngle is a named canonical orientation
+type ViewAngle struct {
+	Name             string
+	Pitch, Yaw, Roll float64 // Radians, composed like RotationState.Matrix
+}
+
+// ViewAngles lists the canonical views. The model's +Z faces the camera at
+// identity, so e.g. the top view pitches +Y toward the viewer.
+var ViewAngles = []ViewAngle{
+	{Name: "front"},
+	{Name: "back", Yaw: math.Pi},
+	{Name: "left", Yaw: math.Pi / 2},
+	{Name: "right", Yaw: -math.Pi / 2},
+	{Name: "top", Pitch: math.Pi / 2},
+	{Name: "bottom", Pitch: -math.Pi / 2},
+	{Name: "isometric", Pitch: math.Atan(1 / math.Sqrt2), Yaw: -math.Pi / 4},
+}
+
+// viewAngleByName looks up a canonical view
+func viewAngleByName(name string) (ViewAngle, bool) {
+	for _, v := range ViewAngles {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return ViewAngle{}, false
+}
+
+// trackballFollow is how far the displayed orientation moves toward the target each nominal frame
+const trackballFollow = 0.35
+
+// TrackballState rotates the model about the camera's view axes using quaternions.
+// Impulses are applied to a target orientation and the displayed orientation
+// slerps toward it, so there is no gimbal flip or roll drift when dragging in circles.
+type TrackballState struct {
+	Pitch, Yaw, Roll RotationAxis // Angular velocity about camera X, Y, Z (Position unused)
+	Orientation      math3d.Quat  // Displayed orientation
+	Target           math3d.Quat  // Orientation being eased toward
+	fps              int
+}
+
+func NewTrackballState(fps int) *TrackballState {
+	return &TrackballState{
+		Pitch:       NewRotationAxis(fps),
+		Yaw:         NewRotationAxis(fps),
+		Roll:        NewRotationAxis(fps),
+		Orientation: math3d.QuatIdentity(),
+		Target:      math3d.QuatIdentity(),
+		fps:         fps,
+	}
+}
+
+func (t *TrackballState) ApplyImpulse(pitch, yaw, roll float64) {
+	t.Pitch.Velocity += pitch
+	t.Yaw.Velocity += yaw
+	t.Roll.Velocity += roll
+}
+
+func (t *TrackballState) Update(dt float64, damping bool) {
+	frames := t.Pitch.frames(dt)
+
+	// Camera looks down -Z, so its view axes coincide with world X/Y/Z and
+	// pre-multiplying rotates about the screen rather than the model's own axes
+	step := math3d.QuatFromAxisAngle(math3d.Right(), t.Pitch.Velocity*frames).
+		Mul(math3d.QuatFromAxisAngle(math3d.Up(), t.Yaw.Velocity*frames)).
+		Mul(math3d.QuatFromAxisAngle(math3d.V3(0, 0, 1), t.Roll.Velocity*frames))
+	t.Target = step.Mul(t.Target).Normalize()
+
+	// Follow the same fraction per nominal frame whatever the real frame time
+	follow := 1 - math.Pow(1-trackballFollow, frames)
+	t.Orientation = t.Orientation.Slerp(t.Target, follow)
+
+	t.Pitch.Update(dt, damping)
+	t.Yaw.Update(dt, damping)
+	t.Roll.Update(dt, damping)
+}
+
+func (t *TrackballState) Turn(pitch, yaw, roll float64) {
+	step := math3d.QuatFromAxisAngle(math3d.Right(), pitch).
+		Mul(math3d.QuatFromAxisAngle(math3d.Up(), yaw)).
+		Mul(math3d.QuatFromAxisAngle(math3d.V3(0, 0, 1), roll))
+	t.Target = step.Mul(t.Target).Normalize()
+	t.Orientation = step.Mul(t.Orientation).Normalize()
+}
+
+func (t *TrackballState) Reset() {
+	*t = *NewTrackballState(t.fps)
+}
+
+func (t *TrackballState) SetSpin(axis math3d.Vec3, speed float64) {
+	v := axis.Normalize().Scale(speed / float64(t.fps))
+	t.Pitch.Velocity, t.Yaw.Velocity, t.Roll.Velocity = v.X, v.Y, v.Z
+}
+
+func (t *TrackballState) SnapTo(pitch, yaw, roll float64) {
+	t.Reset()
+	t.Target = math3d.QuatFromEuler(pitch, yaw, roll)
+	t.Orientation = t.Target
+}
+
+func (t *TrackballState) Moving() bool {
+	// Still easing toward the target counts as moving
+	return t.Pitch.Moving() || t.Yaw.Moving() || t.Roll.Moving() ||
+		math.Abs(t.Orientation.Dot(t.Target)) < 1-1e-9
+}
+
+func (t *TrackballState) Matrix() math3d.Mat4 {
+	return t.Orientation.ToMat4()
+}
+
+// NewRotator creates the rotation controller for the given --rotate mode
+func NewRotator(mode string, fps int) (Rotator, error) {
+	switch mode {
+	case "euler":
+		return NewRotationState(fps), nil
+	case "trackball":
+		return NewTrackballState(fps), nil
+	default:
+		return nil, fmt.Errorf("unknown rotate mode: %s (use euler or trackball)", mode)
+	}
+}
+
 // RenderMode controls how the mesh is drawn
 type RenderMode int
 
@@ -223,18 +518,41 @@ const (
 	RenderModeTextured  RenderMode = iota // Textured with Gouraud shading
 	RenderModeFlat                        // Flat shading (no texture)
 	RenderModeWireframe                   // Wireframe only
+	RenderModeHeatmap                     // Vertices colored by a heatmap scalar
 )
 
 // ViewState holds all view-related settings (UI state, not library code)
 type ViewState struct {
-	TextureEnabled bool        // Whether to show textures
-	RenderMode     RenderMode  // Current render mode
-	LightMode      bool        // Whether in light positioning mode
-	LightDir       math3d.Vec3 // Current light direction
-	PendingLight   math3d.Vec3 // Light direction while positioning
-	ShowHUD        bool        // Whether to show the HUD overlay
-	SpinMode       bool        // Whether auto-spin is enabled
-	BackfaceCull   bool        // Whether to cull backfaces (true = cull, false = show both sides)
+	TextureEnabled bool                      // Whether to show textures
+	InspectTexture int                       // UV inspection texture shown, 1-based into inspectTextures (0 = the model's)
+	Filter         render.FilterMode         // How the shown texture is sampled
+	RenderMode     RenderMode                // Current render mode
+	HeatmapScalar  int                       // Index into heatmapScalars shown in heatmap mode
+	FlatShading    bool                      // Whether to light each triangle by its face normal instead of the vertex normals
+	SmoothAngle    float64                   // Crease angle normals are recomputed for, in degrees (0 = the model's normals)
+	WireColor      render.WireframeColorMode // How wireframe edges are colored
+	LightMode      bool                      // Whether in light positioning mode
+	LightDir       math3d.Vec3               // Current light direction
+	PendingLight   math3d.Vec3               // Light direction while positioning
+	ShowHUD        bool                      // Whether to show the HUD overlay
+	ShowHelp       bool                      // Whether to show the key bindings overlay
+	HelpPage       int                       // Page of the key bindings overlay shown
+	ShowStats      bool                      // Whether to show the render stats overlay
+	ShowParts      bool                      // Whether to show the model's part list
+	UVView         bool                      // Whether to draw the model's UV layout instead of the model
+	Explode        float64                   // How far parts are pushed apart, relative to their offset from the center
+	Section        int                       // Section plane axis, 1-based into sectionAxes (0 = off)
+	SectionAt      float64                   // Section plane position, as a fraction across the model's bounds
+	Measure        bool                      // Whether clicks pick points to measure between
+	Subdivide      int                       // Loop subdivision levels applied to the model
+	Smooth         int                       // Laplacian smoothing passes applied to the model
+	Headlamp       bool                      // Whether the light follows the camera
+	SpinMode       bool                      // Whether auto-spin is enabled
+	SpinAxis       math3d.Vec3               // Axis auto-spin turns about (X = pitch, Y = yaw, Z = roll)
+	SpinSpeed      float64                   // Auto-spin speed in radians per second
+	BackfaceCull   bool                      // Whether to cull backfaces (true = cull, false = show both sides)
+	Gamma          float64                   // Display gamma applied to terminal colors
+	Brightness     float64                   // Display brightness applied to terminal colors
 }
 
 // NewViewState creates default view state
@@ -244,24 +562,42 @@ func NewViewState() *ViewState {
 		RenderMode:     RenderModeTextured,
 		LightMode:      false,
 		LightDir:       math3d.V3(0.5, 1, 0.3).Normalize(),
+		SpinAxis:       spinAxes[0].Axis,
+		SpinSpeed:      defaultSpinSpeed,
 		BackfaceCull:   false, // Default OFF - most STL files are single-sided shells
+		SectionAt:      0.5,
+		Gamma:          1,
+		Brightness:     1,
 	}
 }
 
 // HUD renders an overlay with model info and controls
 type HUD struct {
 	filename  string
+	size      string // Real-world dimensions, shown beside the filename
 	polyCount int
+	keymap    *Keymap
 	fps       float64
 	fpsFrames int
 	fpsTime   time.Time
+
+	// Time spent rasterizing the last frame, shown with the render stats
+	renderTime time.Duration
+
+	// Description of the last clicked face, shown above the bottom row
+	pick string
+
+	// Load messages shown above the pick row until toastUntil
+	toast      []string
+	toastUntil time.Time
 }
 
 // NewHUD creates a new HUD
-func NewHUD(filename string, polyCount int) *HUD {
+func NewHUD(filename string, polyCount int, keymap *Keymap) *HUD {
 	return &HUD{
 		filename:  filename,
 		polyCount: polyCount,
+		keymap:    keymap,
 		fpsTime:   time.Now(),
 	}
 }
@@ -277,77 +613,188 @@ func (h *HUD) UpdateFPS() {
 	}
 }
 
-// Render draws the HUD overlay directly to the terminal
-func (h *HUD) Render(width, height int, viewState *ViewState) {
-	// ANSI escape codes for positioning and styling
+// Render draws the HUD overlay into the screen's cells.
+// Rows it leaves alone keep whatever the framebuffer put there.
+func (h *HUD) Render(scr uv.Screen, width, height int, viewState *ViewState) {
+	// SGR escape codes for styling (parsed into cell styles by drawText)
 	const (
-		reset     = "\x1b[0m"
-		bold      = "\x1b[1m"
-		dim       = "\x1b[2m"
-		bgBlack   = "\x1b[40m"
-		fgWhite   = "\x1b[97m"
-		fgGreen   = "\x1b[92m"
-		fgYellow  = "\x1b[93m"
-		fgCyan    = "\x1b[96m"
-		clearLine = "\x1b[2K"
+		reset    = "\x1b[0m"
+		bold     = "\x1b[1m"
+		dim      = "\x1b[2m"
+		bgBlack  = "\x1b[40m"
+		fgWhite  = "\x1b[97m"
+		fgGreen  = "\x1b[92m"
+		fgYellow = "\x1b[93m"
+		fgCyan   = "\x1b[96m"
 	)
 
-	// Helper to position cursor
-	moveTo := func(row, col int) string {
-		return fmt.Sprintf("\x1b[%d;%dH", row, col)
+	if !viewState.LightMode && !viewState.ShowHUD {
+		return
 	}
 
-	// Always clear the HUD rows (so toggling off works)
-	fmt.Print(moveTo(1, 1) + clearLine)
-	fmt.Print(moveTo(height, 1) + clearLine)
+	// Blank the HUD rows so text sits on a clean background
+	for col := range width {
+		scr.SetCell(col, 0, nil)
+		scr.SetCell(col, height-1, nil)
+	}
 
 	// Light mode always shows its indicator
 	if viewState.LightMode {
 		lightMsg := fmt.Sprintf("%s%s%s ◉ LIGHT MODE - Move mouse to position, click to set, Esc to cancel %s",
 			bgBlack, bold, fgYellow, reset)
-		lightCol := max((width-60)/2, 1)
-		fmt.Print(moveTo(height, lightCol) + lightMsg)
-		return
-	}
-
-	// If HUD is disabled, we're done (lines already cleared)
-	if !viewState.ShowHUD {
+		lightCol := max((width-ansi.StringWidth(lightMsg))/2, 0)
+		drawText(scr, lightCol, height-1, lightMsg)
 		return
 	}
 
 	// Top left: FPS
-	fpsStr := fmt.Sprintf("%s%s%s %.0f FPS %s", moveTo(1, 1), bgBlack, fgGreen, h.fps, reset)
-	fmt.Print(fpsStr)
-
-	// Top middle: filename
-	titleStr := fmt.Sprintf("%s%s%s %s %s", bold, bgBlack, fgWhite, h.filename, reset)
-	titleCol := max((width-len(h.filename)-2)/2, 1)
-	fmt.Print(moveTo(1, titleCol) + titleStr)
+	fpsStr := fmt.Sprintf("%s%s %.0f FPS %s", bgBlack, fgGreen, h.fps, reset)
+	drawText(scr, 0, 0, fpsStr)
+
+	// Top middle: filename, measured in cells so wide runes center correctly.
+	// Truncated so it stays clear of the poly count on the right.
+	title := h.filename
+	if h.size != "" {
+		title += " · " + h.size
+	}
+	title = ansi.Truncate(title, max(width-30, 1), "…")
+	titleStr := fmt.Sprintf("%s%s%s %s %s", bold, bgBlack, fgWhite, title, reset)
+	titleCol := max((width-ansi.StringWidth(title)-2)/2, 0)
+	drawText(scr, titleCol, 0, titleStr)
 
 	// Top right: polygon count
 	polyStr := fmt.Sprintf("%s%s%s %d polys %s", bgBlack, fgCyan, bold, h.polyCount, reset)
-	polyCol := max(width-12, 1)
-	fmt.Print(moveTo(1, polyCol) + polyStr)
+	polyCol := max(width-13, 0)
+	drawText(scr, polyCol, 0, polyStr)
 
 	// Bottom: mode checkboxes and hint
 	checkTex := "[ ]"
-	if viewState.TextureEnabled && viewState.RenderMode != RenderModeWireframe {
+	if viewState.TextureEnabled && viewState.RenderMode != RenderModeWireframe && viewState.RenderMode != RenderModeHeatmap {
 		checkTex = "[✓]"
 	}
 	checkWire := "[ ]"
 	if viewState.RenderMode == RenderModeWireframe {
 		checkWire = "[✓]"
 	}
+	checkHead := "[ ]"
+	if viewState.Headlamp {
+		checkHead = "[✓]"
+	}
+
+	wireLabel := "wireframe"
+	switch viewState.WireColor {
+	case render.WireframeByMaterial:
+		wireLabel = "by material"
+	case render.WireframeByNormal:
+		wireLabel = "by normal"
+	}
 
 	// Bottom: Mode checkboxes and hint
-	modeStr := fmt.Sprintf("%s%s %s Texture  %s X-Ray (wireframe) %s",
-		bgBlack, fgWhite, checkTex, checkWire, reset)
-	fmt.Print(moveTo(height, 1) + modeStr)
+	modeStr := fmt.Sprintf("%s%s %s Texture  %s X-Ray (%s)  %s Headlamp %s",
+		bgBlack, fgWhite, checkTex, checkWire, wireLabel, checkHead, reset)
+	drawText(scr, 0, height-1, modeStr)
+
+	// Help hint (right side of bottom), showing the current binding
+	if len(h.keymap.Help) > 0 {
+		hint := fmt.Sprintf("%s%s%s %s: keys %s", bgBlack, dim, fgYellow, h.keymap.Help[0], reset)
+		hintCol := max(width-ansi.StringWidth(hint), 0)
+		drawText(scr, hintCol, height-1, hint)
+	}
+}
+
+// RenderStats draws the frame's render time and the rasterizer's culling
+// and texture statistics below the HUD's top row. Returns the first and last rows it drew on.
+func (h *HUD) RenderStats(scr uv.Screen, height int, r *render.Rasterizer) (top, bottom int) {
+	const (
+		reset   = "\x1b[0m"
+		bgBlack = "\x1b[40m"
+		fgWhite = "\x1b[97m"
+	)
+
+	cull := r.CullingStats
+	lines := []string{
+		fmt.Sprintf("Render: %.1f ms", float64(h.renderTime.Microseconds())/1000),
+		fmt.Sprintf("Meshes: %d tested, %d culled, %d drawn", cull.MeshesTested, cull.MeshesCulled, cull.MeshesDrawn),
+		fmt.Sprintf("Tris: %d tested, %d culled, %d backface, %d drawn",
+			cull.TrianglesTested, cull.TrianglesCulled, cull.TrianglesBackface, cull.TrianglesDrawn()),
+	}
+
+	tex := r.TextureStats
+	if tex.Samples == 0 {
+		lines = append(lines, "Texture: no samples")
+	} else {
+		filter := "nearest"
+		if tex.Filter == render.FilterBilinear {
+			filter = "bilinear"
+		}
+		lines = append(lines,
+			fmt.Sprintf("Texture: %dx%d %s", tex.Width, tex.Height, filter),
+			fmt.Sprintf("Samples: %d (%d nearest, %d bilinear)", tex.Samples, tex.Nearest, tex.Bilinear),
+			fmt.Sprintf("Row jumps: %d (%.0f%%)", tex.RowJumps, 100*float64(tex.RowJumps)/float64(tex.Samples)),
+		)
+	}
 
-	// Light hint (right side of bottom)
-	hint := fmt.Sprintf("%s%s%s L: position light %s", bgBlack, dim, fgYellow, reset)
-	hintCol := max(width-18, 1)
-	fmt.Print(moveTo(height, hintCol) + hint)
+	top = 1
+	bottom = top
+	for i, line := range lines {
+		row := top + i
+		if row >= height-1 {
+			break
+		}
+		drawText(scr, 0, row, fmt.Sprintf("%s%s %s %s", bgBlack, fgWhite, line, reset))
+		bottom = row
+	}
+	return top, bottom
+}
+
+// RenderPick draws the last clicked face above the HUD's bottom row.
+// Returns the row it drew on, or -1 if there was nothing to draw.
+func (h *HUD) RenderPick(scr uv.Screen, height int, viewState *ViewState) int {
+	const (
+		reset   = "\x1b[0m"
+		bgBlack = "\x1b[40m"
+		fgCyan  = "\x1b[96m"
+	)
+
+	if h.pick == "" || !viewState.ShowHUD || viewState.LightMode || height < 3 {
+		return -1
+	}
+	row := height - 2
+	drawText(scr, 0, row, fmt.Sprintf("%s%s %s %s", bgBlack, fgCyan, h.pick, reset))
+	return row
+}
+
+// describeFace names face i of mesh, its part, and its material for the
+// pick readout. Once the viewer has renumbered the model's faces, by
+// decimating, subdividing, hiding parts, or sectioning, the index is the
+// displayed mesh's rather than the file's; the part and material still
+// match the file.
+func describeFace(mesh *models.Mesh, i int, renumbered bool) string {
+	if i < 0 || i >= mesh.TriangleCount() {
+		return "No face under cursor"
+	}
+	desc := fmt.Sprintf("Face %d", i)
+	if renumbered {
+		desc = fmt.Sprintf("Displayed face %d", i)
+	}
+	if g := mesh.Faces[i].Group; len(mesh.Groups) > 1 && g >= 0 && g < len(mesh.Groups) {
+		desc += fmt.Sprintf(" of %q", mesh.Groups[g])
+	}
+	mat := mesh.GetMaterial(mesh.GetFaceMaterial(i))
+	switch {
+	case mat == nil:
+		return desc + " (no material)"
+	case mat.Name == "":
+		return fmt.Sprintf("%s (material %d)", desc, mesh.GetFaceMaterial(i))
+	default:
+		return fmt.Sprintf("%s (material %q)", desc, mat.Name)
+	}
+}
+
+// drawText draws an SGR-styled string into the screen starting at (col, row).
+func drawText(scr uv.Screen, col, row int, text string) {
+	ss := uv.NewStyledString(text)
+	ss.Draw(scr, uv.Rect(col, row, ss.UnicodeWidth(), 1))
 }
 
 // ScreenToLightDir converts a screen position to a light direction.
@@ -373,109 +820,454 @@ func (v *ViewState) ScreenToLightDir(screenX, screenY, width, height int) math3d
 	return math3d.V3(nx, -ny, nz).Normalize()
 }
 
-func run(modelPath string) error {
-	// Parse background color
-	var bgR, bgG, bgB uint8 = 30, 30, 40
-	fmt.Sscanf(bgColor, "%d,%d,%d", &bgR, &bgG, &bgB)
+// Limits for the viewer's live subdivide and smooth keys
+const (
+	maxSubdivide          = 3
+	maxSubdivideTriangles = 2_000_000
+	maxSmooth             = 20  // Passes kept for undo
+	smoothLambda          = 0.5 // How far each pass moves vertices toward their neighbors
+)
 
-	// Create terminal
-	term := uv.DefaultTerminal()
+// GIF recordings capture at a lower rate than the display and stop on
+// their own after a minute, bounding memory
+const (
+	recordFPS       = 10
+	maxRecordFrames = 60 * recordFPS
+)
 
-	width, height, err := term.GetSize()
+// loadModel loads the mesh and its texture, with the --anchor point at
+// the origin and scaled to fit a 2-unit cube. Progress messages are
+// written to log. Cancelling ctx stops a download or glTF load.
+func loadModel(ctx context.Context, modelPath string, log io.Writer) (*models.Mesh, *render.Texture, error) {
+	anchor, err := models.ParseAnchor(anchorName)
 	if err != nil {
-		return fmt.Errorf("get terminal size: %w", err)
+		return nil, nil, err
+	}
+	// Load texture if specified
+	var texture *render.Texture
+	if texturePath != "" {
+		texture, err = render.LoadTexture(texturePath)
+		if err != nil {
+			fmt.Fprintf(log, "Warning: could not load texture: %v\n", err)
+		}
 	}
 
-	if err := term.Start(); err != nil {
-		return fmt.Errorf("start terminal: %w", err)
+	mesh, embeddedImg, err := loadMesh(ctx, modelPath, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Use embedded texture if no explicit texture and one exists
+	if texture == nil && embeddedImg != nil {
+		texture = render.TextureFromImage(embeddedImg)
+		fmt.Fprintf(log, "Using embedded texture: %dx%d\n", embeddedImg.Bounds().Dx(), embeddedImg.Bounds().Dy())
 	}
 
-	term.EnterAltScreen()
-	term.HideCursor()
-	term.Resize(width, height)
+	// A terminal can't resolve more than a few hundred texels across, so
+	// big textures only cost memory and cache misses
+	if texture != nil {
+		if resized := texture.Resize(maxTexSize); resized != texture {
+			fmt.Fprintf(log, "Downsampled texture %dx%d to %dx%d\n", texture.Width, texture.Height, resized.Width, resized.Height)
+			texture = resized
+		}
+		texture.IsSRGB = true // Color images are sRGB encoded
+		texture.WrapU, texture.WrapV = textureWrap(mesh.TextureWrap[0]), textureWrap(mesh.TextureWrap[1])
+	}
 
-	// Enable mouse mode
-	fmt.Fprint(os.Stdout, "\x1b[?1003h") // Enable any-event mouse tracking
-	fmt.Fprint(os.Stdout, "\x1b[?1006h") // Enable SGR extended mouse mode
+	// Generate fallback texture if none
+	if texture == nil {
+		texture = render.NewCheckerTexture(64, 64, 8, render.RGB(200, 200, 200), render.RGB(100, 100, 100))
+	}
 
-	// Create renderer
-	termRenderer := render.NewTerminalRenderer(term, width, height)
-	fbWidth, fbHeight := termRenderer.FramebufferSize()
-	fb := render.NewFramebuffer(fbWidth, fbHeight)
+	fmt.Fprintf(log, "Loaded: %s (%d vertices, %d triangles)\n", modelName(modelPath), mesh.VertexCount(), mesh.TriangleCount())
 
-	// Create camera
-	camera := render.NewCamera()
-	camera.SetAspectRatio(float64(fbWidth) / float64(fbHeight))
-	camera.SetFOV(math.Pi / 3)
-	camera.SetClipPlanes(0.1, 100)
-	camera.SetPosition(math3d.V3(0, 0, 5))
-	camera.LookAt(math3d.V3(0, 0, 0))
+	// Move the anchor to the origin, where the model pivots, and scale to fit
+	mesh.CalculateBounds()
+	mesh.RecenterTo(anchor)
+	mesh.FitToSize(2)
 
-	rasterizer := render.NewRasterizer(camera, fb)
+	return mesh, texture, nil
+}
 
-	// Load texture if specified
-	var texture *render.Texture
-	if texturePath != "" {
-		texture, err = render.LoadTexture(texturePath)
-		if err != nil {
-			fmt.Printf("Warning: could not load texture: %v\n", err)
-		}
+// textureWrap converts a model's texture wrap mode to the renderer's.
+func textureWrap(w models.TextureWrap) render.WrapMode {
+	switch w {
+	case models.WrapClamp:
+		return render.WrapClamp
+	case models.WrapMirror:
+		return render.WrapMirror
+	default:
+		return render.WrapRepeat
+	}
+}
+
+// occlusionTexture returns the mesh's ambient occlusion map, downsampled like
+// the color texture, and its strength, or nil if the model has none.
+func occlusionTexture(mesh *models.Mesh) (*render.Texture, float64) {
+	img, strength := mesh.OcclusionMap()
+	if img == nil {
+		return nil, 0
 	}
+	return render.TextureFromImage(img).Resize(maxTexSize), strength
+}
 
-	// Load model
-	ext := strings.ToLower(filepath.Ext(modelPath))
-	var mesh *models.Mesh
+// loadMesh loads the model at its original size, simplified when
+// --decimate asks for it and with normals recomputed for --smooth-angle.
+// GLTF models also return their embedded texture.
+func loadMesh(ctx context.Context, modelPath string, log io.Writer) (*models.Mesh, image.Image, error) {
+	if decimate <= 0 || decimate > 1 {
+		return nil, nil, fmt.Errorf("invalid --decimate %g: want a fraction in (0, 1]", decimate)
+	}
+	if smoothAngle < 0 || smoothAngle > 180 {
+		return nil, nil, fmt.Errorf("invalid --smooth-angle %g: want 0-180 degrees", smoothAngle)
+	}
 
-	switch ext {
-	case ".glb", ".gltf":
-		var embeddedImg image.Image
-		mesh, embeddedImg, err = models.LoadGLBWithTexture(modelPath)
-		if err != nil {
-			return fmt.Errorf("load model: %w", err)
+	name := modelName(modelPath)
+	ext := strings.ToLower(filepath.Ext(name))
+	var data []byte // The model's contents, when it isn't read from a file
+	var fsys fs.FS  // Where a glTF read from data finds its external files
+	var err error
+	switch {
+	case modelPath == stdinPath:
+		if data, err = io.ReadAll(os.Stdin); err != nil {
+			return nil, nil, fmt.Errorf("read stdin: %w", err)
 		}
-		// Use embedded texture if no explicit texture and one exists
-		if texture == nil && embeddedImg != nil {
-			texture = render.TextureFromImage(embeddedImg)
-			fmt.Printf("Using embedded texture: %dx%d\n", embeddedImg.Bounds().Dx(), embeddedImg.Bounds().Dy())
+		ext = models.DetectFormat(data)
+	case isURL(modelPath):
+		fmt.Fprintf(log, "Downloading %s\n", modelPath)
+		if data, err = download(ctx, modelPath); err != nil {
+			return nil, nil, err
 		}
-	case ".obj":
-		mesh, err = models.LoadOBJ(modelPath)
-		if err != nil {
-			return fmt.Errorf("load model: %w", err)
+		base, _ := url.Parse(modelPath) // Already fetched, so it parses
+		fsys = urlFS{ctx: ctx, base: base}
+		// URLs don't always end in an extension, e.g. "...?download=1"
+		if !isModelFile(name) {
+			ext = models.DetectFormat(data)
 		}
-	case ".stl":
+	}
+
+	var mesh *models.Mesh
+	var embeddedImg image.Image
+
+	switch {
+	case ext == ".glb" || ext == ".gltf":
+		mesh, embeddedImg, err = loadGLTF(ctx, modelPath, data, fsys, log)
+	case ext == ".obj" && data != nil:
+		mesh, err = models.NewOBJLoader().Load(bytes.NewReader(data), name)
+	case ext == ".obj":
+		mesh, err = models.LoadOBJ(modelPath)
+	case ext == ".stl" && data != nil:
+		mesh, err = models.NewSTLLoader().LoadBytes(data, name)
+	case ext == ".stl":
 		mesh, err = models.LoadSTL(modelPath)
+	case ext == "" && data != nil:
+		return nil, nil, fmt.Errorf("unrecognized model format in %s (use glTF, OBJ, or STL)", name)
+	default:
+		return nil, nil, fmt.Errorf("unsupported format: %s (use .obj, .glb, or .stl)", ext)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("load model: %w", err)
+	}
+
+	if mesh.IsEmpty() {
+		return nil, nil, fmt.Errorf("load model: %s: %w", name, models.ErrEmptyMesh)
+	}
+
+	if decimate < 1 {
+		before := mesh.TriangleCount()
+		mesh.Decimate(decimate)
+		fmt.Fprintf(log, "Decimated %d triangles to %d\n", before, mesh.TriangleCount())
+	}
+	if smoothAngle > 0 {
+		mesh.CalculateNormalsWithAngle(smoothAngle)
+	}
+
+	return mesh, embeddedImg, nil
+}
+
+// runConvert writes the model at inPath to outPath as OBJ.
+func runConvert(inPath, outPath string) error {
+	if ext := strings.ToLower(filepath.Ext(outPath)); ext != ".obj" {
+		return fmt.Errorf("unsupported output format: %s (use .obj)", ext)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	mesh, _, err := loadMesh(ctx, inPath, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if err := models.SaveOBJ(outPath, mesh); err != nil {
+		return fmt.Errorf("convert: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s (%d vertices, %d triangles)\n", outPath, mesh.VertexCount(), mesh.TriangleCount())
+	return nil
+}
+
+// loadGLTF loads a GLTF/GLB file, showing a spinner on log when it's a
+// terminal, or on the terminal a loadLog is given. Cancelling ctx cancels
+// the load.
+func loadGLTF(ctx context.Context, modelPath string, data []byte, fsys fs.FS, log io.Writer) (*models.Mesh, image.Image, error) {
+	// Only the texture the viewer shows gets decoded
+	loader := models.NewGLTFLoader()
+	loader.LazyTextures = true
+	var warnings []error
+	loader.Warn = func(err error) { warnings = append(warnings, err) }
+	var spinner *loadSpinner
+	if out := spinnerOutput(log); out != nil {
+		spinner = startSpinner(out, modelName(modelPath))
+		loader.Progress = spinner.Update
+	}
+	var mesh *models.Mesh
+	var img image.Image
+	var err error
+	if data != nil {
+		mesh, img, err = loader.LoadBytesWithTexture(ctx, data, modelName(modelPath), fsys)
+	} else {
+		mesh, img, err = loader.LoadWithTextureContext(ctx, modelPath)
+	}
+	if spinner != nil {
+		spinner.Stop()
+	}
+
+	// Printed once the spinner has erased its line
+	for _, w := range warnings {
+		fmt.Fprintf(log, "Warning: skipped %v\n", w)
+	}
+	return mesh, img, err
+}
+
+// Thresholds for splitting a frame's rasterization across goroutines
+const (
+	minParallelTriangles = 500   // Fewer rarely cover enough pixels to be worth it
+	minWorkerPixels      = 16000 // Framebuffer pixels each worker should have to fill
+)
+
+// shadingWorkers picks how many goroutines rasterize a frame of a mesh with
+// the given triangle count into a framebuffer of the given size. Each
+// worker fills its own band of rows but sets up every triangle, so extra
+// workers only pay off with enough pixels to share out.
+func shadingWorkers(triangles, pixels int) int {
+	if triangles < minParallelTriangles {
+		return 1
+	}
+	return max(1, min(runtime.NumCPU(), pixels/minWorkerPixels))
+}
+
+// frontFace returns the rasterizer winding that matches mesh's faces.
+func frontFace(mesh *models.Mesh) render.FrontFace {
+	if mesh.Winding == models.WindingCCW {
+		return render.FrontFaceCCW
+	}
+	return render.FrontFaceCW
+}
+
+// captureName returns the file a screenshot or recording finished at t is
+// saved to, named after the model, e.g. "robot-20250102-150405.png".
+func captureName(modelPath string, t time.Time, ext string) string {
+	name := modelName(modelPath)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return fmt.Sprintf("%s-%s%s", name, t.Format("20060102-150405"), ext)
+}
+
+// parsePalette returns the overlay palette named by --palette.
+func parsePalette(name string) (render.Palette, error) {
+	switch strings.ToLower(name) {
+	case "default":
+		return render.DefaultPalette, nil
+	case "colorblind", "color-blind":
+		return render.ColorBlindPalette, nil
+	}
+	return render.Palette{}, fmt.Errorf("unknown palette %q (use default or colorblind)", name)
+}
+
+// parseBackground returns the color given by --bg: R,G,B, or transparent
+// to leave the terminal's own background showing behind the model.
+func parseBackground(s string) render.Color {
+	if strings.EqualFold(s, "transparent") {
+		return render.Color{}
+	}
+	var r, g, b uint8 = 30, 30, 40
+	fmt.Sscanf(s, "%d,%d,%d", &r, &g, &b)
+	return render.RGB(r, g, b)
+}
+
+// Limits for --cell-aspect
+const (
+	minCellAspect = 1.0
+	maxCellAspect = 4.0
+)
+
+// newViewCamera creates the viewer camera looking at the model from +Z
+// with the --fov field of view, far enough back to fit its bounds.
+func newViewCamera(fbWidth, fbHeight int, bounds render.AABB) *render.Camera {
+	camera := render.NewCamera()
+	camera.SetAspectRatio(render.ViewAspect(fbWidth, fbHeight, cellAspect))
+	camera.SetFOV(fovDegrees * math.Pi / 180)
+	camera.SetClipPlanes(0.1, 100)
+	camera.SetPosition(math3d.V3(0, 0, 1))
+	camera.LookAt(math3d.V3(0, 0, 0))
+	camera.FrameBounds(bounds, homeMargin)
+	return camera
+}
+
+// run views the models at modelPaths, starting with the first.
+func run(modelPaths []string) error {
+	background := parseBackground(bgColor)
+
+	// Validate rotation mode and keymap before taking over the terminal
+	rotation, err := NewRotator(rotateMode, targetFPS)
+	if err != nil {
+		return err
+	}
+	keymapPath, err := KeymapPath()
+	if err != nil {
+		return fmt.Errorf("locate keymap: %w", err)
+	}
+	keymap, err := LoadKeymap(keymapPath)
+	if err != nil {
+		return err
+	}
+	axis, err := parseSpinAxis(spinAxis)
+	if err != nil {
+		return err
+	}
+	if fovDegrees < minFOV || fovDegrees > maxFOV {
+		return fmt.Errorf("invalid --fov %g: want %g-%g degrees", fovDegrees, minFOV, maxFOV)
+	}
+	palette, err := parsePalette(paletteName)
+	if err != nil {
+		return err
+	}
+	glyph, err := render.ParseGlyph(glyphName)
+	if err != nil {
+		return err
+	}
+	filter, err := render.ParseFilter(filterName)
+	if err != nil {
+		return err
+	}
+	if cellAspect < minCellAspect || cellAspect > maxCellAspect {
+		return fmt.Errorf("invalid --cell-aspect %g: want %g-%g", cellAspect, minCellAspect, maxCellAspect)
+	}
+	if gamma < minGamma || gamma > maxGamma {
+		return fmt.Errorf("invalid --gamma %g: want %g-%g", gamma, minGamma, maxGamma)
+	}
+	if brightness < minBrightness || brightness > maxBrightness {
+		return fmt.Errorf("invalid --brightness %g: want %g-%g", brightness, minBrightness, maxBrightness)
+	}
+
+	// Piped or redirected output can't host the interactive viewer; print a
+	// single frame instead and keep status messages off stdout. The viewer
+	// shows them once it's up, since the alt screen would hide them.
+	interactive := xterm.IsTerminal(os.Stdout.Fd())
+	notes := &loadLog{progress: os.Stdout}
+	var log io.Writer = notes
+	if !interactive {
+		log = os.Stderr
+	}
+
+	// Load before taking over the terminal so errors print normally,
+	// skipping models that fail. The rest load when first switched to.
+	library := newModelSet(modelPaths)
+	loadCtx, stopLoad := signal.NotifyContext(context.Background(), os.Interrupt)
+	first, err := library.SelectFirst(loadCtx, log)
+	stopLoad()
+	if err != nil {
+		return err
+	}
+	mesh, texture := first.mesh, first.texture
+	if !interactive {
+		texture.FilterMode = filter
+		return renderHeadless(mesh, texture, os.Stdout)
+	}
+
+	// Create terminal. A model piped in has used up stdin, so read keys
+	// from the terminal itself.
+	term := uv.DefaultTerminal()
+	if !xterm.IsTerminal(os.Stdin.Fd()) {
+		tty, _, err := uv.OpenTTY()
 		if err != nil {
-			return fmt.Errorf("load model: %w", err)
+			return fmt.Errorf("open terminal: %w", err)
 		}
-	default:
-		return fmt.Errorf("unsupported format: %s (use .obj, .glb, or .stl)", ext)
+		defer tty.Close()
+		term = uv.NewTerminal(uv.NewConsole(tty, os.Stdout, os.Environ()), nil)
 	}
 
-	// Generate fallback texture if none
-	if texture == nil {
-		texture = render.NewCheckerTexture(64, 64, 8, render.RGB(200, 200, 200), render.RGB(100, 100, 100))
+	width, height, err := term.GetSize()
+	if err != nil {
+		return fmt.Errorf("get terminal size: %w", err)
 	}
 
-	fmt.Printf("Loaded: %s (%d vertices, %d triangles)\n", filepath.Base(modelPath), mesh.VertexCount(), mesh.TriangleCount())
+	if err := term.Start(); err != nil {
+		return fmt.Errorf("start terminal: %w", err)
+	}
 
-	// Create HUD
-	hud := NewHUD(filepath.Base(modelPath), mesh.TriangleCount())
+	screen := term.Screen()
+	screen.EnterAltScreen()
+	screen.HideCursor()
+	screen.Resize(width, height)
 
-	// Center and scale model
-	mesh.CalculateBounds()
-	center := mesh.Center()
-	size := mesh.Size()
-	maxDim := math.Max(size.X, math.Max(size.Y, size.Z))
-	if maxDim > 0 {
-		scale := 2.0 / maxDim
-		transform := math3d.Scale(math3d.V3(scale, scale, scale)).Mul(math3d.Translate(center.Scale(-1)))
-		mesh.Transform(transform)
+	// Enable mouse mode
+	fmt.Fprint(os.Stdout, "\x1b[?1003h") // Enable any-event mouse tracking
+	fmt.Fprint(os.Stdout, "\x1b[?1006h") // Enable SGR extended mouse mode
+
+	// Create renderer
+	termRenderer := render.NewTerminalRenderer(term, width, height)
+	fbWidth, fbHeight := termRenderer.FramebufferSize()
+	fb := render.NewFramebuffer(fbWidth, fbHeight)
+
+	bounds := render.NewAABB(mesh.BoundsMin, mesh.BoundsMax)
+	camera := newViewCamera(fbWidth, fbHeight, bounds)
+	home := camera.Position
+	scene := &render.Scene{
+		Camera:         camera,
+		Palette:        palette,
+		AntialiasLines: antialias,
+		LineWidth:      wireWidth,
+		Picking:        true,
+		CellAspect:     cellAspect,
 	}
+	occlusion, occlusionStrength := occlusionTexture(mesh)
 
-	// Initialize rotation and view state
-	rotation := NewRotationState(targetFPS)
+	// Create HUD
+	hud := NewHUD(library.Title(), mesh.TriangleCount(), keymap)
+	unit := lengthUnit(library.Path())
+	hud.size = formatSize(mesh.SourceSize(), unit)
+	hud.Toast(notes.Lines())
+
+	// Subdivision rebuilds from the loaded mesh, never compounding on itself.
+	// Each smoothing pass is kept so undo is instant. Normals are then
+	// recomputed for the smoothing angle, and hidden parts filtered out
+	// last, giving the mesh drawn.
+	base := mesh
+	subdivided := 0
+	smoothed := []*models.Mesh{mesh}
+	detailed := mesh
+	creased := mesh
+	creasedAngle := smoothAngle // loadMesh already applied --smooth-angle
+	parts := newPartsView(mesh)
+	partsChanged := false
+	var capMesh *models.Mesh // Closes the section plane's cut; nil with the section off
+
+	// Heatmap vertex colors for the current mesh, and which scalar they show
+	var heatColors []render.Color
+	heatScalar := 0
+	helpPages := 1 // Pages the key help overlay took when last drawn
+
+	// Initialize view state
 	viewState := NewViewState()
+	viewState.SpinAxis = axis
+	viewState.SpinSpeed = math.Max(minSpinSpeed, math.Min(maxSpinSpeed, spinSpeed))
+	viewState.Headlamp = headlamp
+	viewState.SmoothAngle = smoothAngle
+	viewState.Gamma = gamma
+	viewState.Brightness = brightness
+	viewState.Filter = filter
+	termRenderer.SetTone(viewState.Gamma, viewState.Brightness)
+	termRenderer.SetGlyph(glyph)
+
+	// The view as started, which reset returns to
+	startView := *viewState
 
 	// Context for clean shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -491,97 +1283,316 @@ func run(modelPath string) error {
 	// Input state
 	inputTorque := struct{ pitch, yaw, roll float64 }{}
 	const torqueStrength = 3.0
+	const torqueDecay = 0.9     // Fraction of input torque left after 1/60 s
+	const pausedDragTurn = 0.05 // Radians per cell dragged while paused
 
 	// Mouse state
 	var mouseDown bool
+	var panning bool // Current drag pans instead of rotating
 	var lastMouseX, lastMouseY int
-	cameraZ := 5.0
+	var dragged bool // Mouse moved since the click, so releasing it doesn't pick
+
+	// Cell to pick a face at on the next frame, set by a click without a drag
+	var pickCol, pickRow int
+	var pickPending bool
+	var screenshotPending bool // Save the next frame as a PNG
+	var recordToggle bool      // Start or stop recording on the next frame
+	var modelStep int          // Switch this many models along on the next frame
+	var resizePending bool     // Fit the buffers to width and height on the next frame
+	var paused bool            // Hold spin and momentum; input turns the model directly
+	var stepPending bool       // Advance one frame of motion while paused
+	var recorder *render.GIFRecorder
+	var measure measurement
+	zoom := newCameraZoom(camera, bounds.HalfSize().Len())
+
+	// Applies a changed display tone and shows it on the HUD
+	setTone := func() {
+		termRenderer.SetTone(viewState.Gamma, viewState.Brightness)
+		hud.pick = toneStatus(viewState.Gamma, viewState.Brightness)
+	}
+
+	// Signaled on every event so an idle main loop resumes full rate at once
+	wake := make(chan struct{}, 1)
+
+	// Guards the view state shared by the event handler and the main loop.
+	// The main loop holds it for a whole frame, so events apply between
+	// frames, never halfway through one.
+	var mu sync.Mutex
 
 	// Event handler
 	go func() {
 		for ev := range term.Events() {
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+
+			mu.Lock()
 			switch ev := ev.(type) {
 			case uv.WindowSizeEvent:
+				// Resized in place on the next frame
 				width, height = ev.Width, ev.Height
-				term.Erase()
-				term.Resize(width, height)
-				termRenderer = render.NewTerminalRenderer(term, width, height)
-				fbWidth, fbHeight = termRenderer.FramebufferSize()
-				fb = render.NewFramebuffer(fbWidth, fbHeight)
-				rasterizer = render.NewRasterizer(camera, fb)
-				camera.SetAspectRatio(float64(fbWidth) / float64(fbHeight))
+				resizePending = true
 
 			case uv.KeyPressEvent:
+				if ev.MatchString("ctrl+c") {
+					cancel()
+					mu.Unlock()
+					return
+				}
+				if view, ok := keymap.ViewFor(ev); ok {
+					// Canonical view: stop spinning so it stays put
+					rotation.SnapTo(view.Pitch, view.Yaw, view.Roll)
+					viewState.SpinMode = false
+					inputTorque.pitch, inputTorque.yaw, inputTorque.roll = 0, 0, 0
+					break
+				}
 				switch {
-				case ev.MatchString("escape"):
+				case ev.MatchString(keymap.Quit...):
 					if viewState.LightMode {
 						// Cancel light positioning mode
 						viewState.LightMode = false
 					} else {
 						cancel()
+						mu.Unlock()
 						return
 					}
-				case ev.MatchString("ctrl+c"):
-					cancel()
-					return
-				case ev.MatchString("q"):
+				case ev.MatchString(keymap.RollLeft...):
 					inputTorque.roll = -torqueStrength
-				case ev.MatchString("r"):
+				case ev.MatchString(keymap.ResetRotate...):
+					// Reset rotation, zoom, and pan
+					rotation.Reset()
+					zoom.Snap(camera, home)
+				case ev.MatchString(keymap.Reset...):
+					// Back to the view as started, keeping the overlays shown
 					rotation.Reset()
-					cameraZ = 5.0
-					camera.SetPosition(math3d.V3(0, 0, cameraZ))
-				case ev.MatchString("w", "up"):
+					home = zoom.SetFOV(camera, fovDegrees*math.Pi/180, home)
+					zoom.Snap(camera, home)
+					overlays := *viewState
+					*viewState = startView
+					viewState.ShowHUD, viewState.ShowHelp, viewState.HelpPage = overlays.ShowHUD, overlays.ShowHelp, overlays.HelpPage
+					viewState.ShowStats, viewState.ShowParts = overlays.ShowStats, overlays.ShowParts
+					termRenderer.SetTone(viewState.Gamma, viewState.Brightness)
+					measure.Reset()
+					partsChanged = true
+					hud.pick = "View reset"
+				case ev.MatchString(keymap.PitchUp...):
 					inputTorque.pitch = -torqueStrength
-				case ev.MatchString("s", "down"):
+				case ev.MatchString(keymap.PitchDown...):
 					inputTorque.pitch = torqueStrength
-				case ev.MatchString("a", "left"):
+				case ev.MatchString(keymap.YawLeft...):
 					inputTorque.yaw = -torqueStrength
-				case ev.MatchString("d", "right"):
+				case ev.MatchString(keymap.YawRight...):
 					inputTorque.yaw = torqueStrength
-				case ev.MatchString("e"):
+				case ev.MatchString(keymap.RollRight...):
 					inputTorque.roll = torqueStrength
-				case ev.MatchString("space"):
+				case ev.MatchString(keymap.Spin...):
 					// Toggle spin mode
 					viewState.SpinMode = !viewState.SpinMode
 					if viewState.SpinMode {
-						// Set a gentle constant spin
-						rotation.Yaw.Velocity = 0.02
+						rotation.SetSpin(viewState.SpinAxis, viewState.SpinSpeed)
+					}
+				case ev.MatchString(keymap.SpinFaster...):
+					viewState.SpinSpeed = math.Min(viewState.SpinSpeed*spinSpeedStep, maxSpinSpeed)
+					if viewState.SpinMode {
+						rotation.SetSpin(viewState.SpinAxis, viewState.SpinSpeed)
+					}
+				case ev.MatchString(keymap.SpinSlower...):
+					viewState.SpinSpeed = math.Max(viewState.SpinSpeed/spinSpeedStep, minSpinSpeed)
+					if viewState.SpinMode {
+						rotation.SetSpin(viewState.SpinAxis, viewState.SpinSpeed)
+					}
+				case ev.MatchString(keymap.Pause...):
+					paused = !paused
+					if paused {
+						hud.pick = "Paused"
+					} else {
+						hud.pick = ""
+					}
+				case ev.MatchString(keymap.Step...):
+					// Stepping a running view pauses it first
+					if paused {
+						stepPending = true
+					}
+					paused = true
+					hud.pick = "Paused"
+				case ev.MatchString(keymap.SpinAxis...):
+					viewState.SpinAxis = nextSpinAxis(viewState.SpinAxis)
+					if viewState.SpinMode {
+						rotation.SetSpin(viewState.SpinAxis, viewState.SpinSpeed)
 					}
-				case ev.MatchString("+", "="):
-					cameraZ = math.Max(1, cameraZ-0.5)
-					camera.SetPosition(math3d.V3(0, 0, cameraZ))
-				case ev.MatchString("-", "_"):
-					cameraZ = math.Min(20, cameraZ+0.5)
-					camera.SetPosition(math3d.V3(0, 0, cameraZ))
-				case ev.MatchString("t"):
+				case ev.MatchString(keymap.FOVWider...):
+					home = zoom.SetFOV(camera, camera.FOV+fovStep*math.Pi/180, home)
+				case ev.MatchString(keymap.FOVNarrower...):
+					home = zoom.SetFOV(camera, camera.FOV-fovStep*math.Pi/180, home)
+				case ev.MatchString(keymap.Brighter...):
+					viewState.Brightness = stepTone(viewState.Brightness, toneStep, minBrightness, maxBrightness)
+					setTone()
+				case ev.MatchString(keymap.Dimmer...):
+					viewState.Brightness = stepTone(viewState.Brightness, -toneStep, minBrightness, maxBrightness)
+					setTone()
+				case ev.MatchString(keymap.GammaUp...):
+					viewState.Gamma = stepTone(viewState.Gamma, toneStep, minGamma, maxGamma)
+					setTone()
+				case ev.MatchString(keymap.GammaDown...):
+					viewState.Gamma = stepTone(viewState.Gamma, -toneStep, minGamma, maxGamma)
+					setTone()
+				case ev.MatchString(keymap.ZoomIn...):
+					zoom.Zoom(1)
+				case ev.MatchString(keymap.ZoomOut...):
+					zoom.Zoom(-1)
+				case ev.MatchString(keymap.Texture...):
 					// Toggle texture
 					viewState.TextureEnabled = !viewState.TextureEnabled
-				case ev.MatchString("x"):
+				case ev.MatchString(keymap.Wireframe...):
 					// Toggle wireframe mode
 					if viewState.RenderMode == RenderModeWireframe {
 						viewState.RenderMode = RenderModeTextured
 					} else {
 						viewState.RenderMode = RenderModeWireframe
 					}
-				case ev.MatchString("l"):
-					// Enter light positioning mode
+				case ev.MatchString(keymap.Filter...):
+					if viewState.Filter == render.FilterBilinear {
+						viewState.Filter = render.FilterNearest
+						hud.pick = "Nearest texture filtering"
+					} else {
+						viewState.Filter = render.FilterBilinear
+						hud.pick = "Bilinear texture filtering"
+					}
+				case ev.MatchString(keymap.Inspect...):
+					viewState.InspectTexture = (viewState.InspectTexture + 1) % (len(inspectTextures) + 1)
+					viewState.TextureEnabled = true
+					hud.pick = inspectStatus(viewState.InspectTexture)
+				case ev.MatchString(keymap.UVView...):
+					viewState.UVView = !viewState.UVView
+					if viewState.UVView {
+						hud.pick = "UV layout (red where faces overlap)"
+					} else {
+						hud.pick = ""
+					}
+				case ev.MatchString(keymap.FlatShading...):
+					viewState.FlatShading = !viewState.FlatShading
+					if viewState.FlatShading {
+						hud.pick = "Flat shading"
+					} else {
+						hud.pick = "Smooth shading"
+					}
+				case ev.MatchString(keymap.SmoothAngle...):
+					viewState.SmoothAngle = nextSmoothAngle(viewState.SmoothAngle)
+					hud.pick = smoothAngleStatus(viewState.SmoothAngle)
+				case ev.MatchString(keymap.Heatmap...):
+					// Cycle off, then each heatmap scalar
+					switch {
+					case viewState.RenderMode != RenderModeHeatmap:
+						viewState.RenderMode = RenderModeHeatmap
+						viewState.HeatmapScalar = 0
+					case viewState.HeatmapScalar+1 < len(heatmapScalars):
+						viewState.HeatmapScalar++
+					default:
+						viewState.RenderMode = RenderModeTextured
+					}
+					hud.pick = heatmapStatus(viewState.RenderMode, viewState.HeatmapScalar)
+				case ev.MatchString(keymap.WireColor...):
+					viewState.WireColor = (viewState.WireColor + 1) % (render.WireframeByNormal + 1)
+				case ev.MatchString(keymap.Light...):
+					// Enter light positioning mode, starting from the light
+					// shown; placing the light replaces the headlamp
 					viewState.LightMode = true
 					viewState.PendingLight = viewState.LightDir
-				case ev.MatchString("b"):
+					if viewState.Headlamp {
+						viewState.PendingLight = camera.Forward().Scale(-1)
+					}
+				case ev.MatchString(keymap.Headlamp...):
+					viewState.Headlamp = !viewState.Headlamp
+				case ev.MatchString(keymap.Backface...):
 					// Toggle backface culling
 					viewState.BackfaceCull = !viewState.BackfaceCull
-				case ev.MatchString("?"), ev.MatchString("shift+/"):
+				case ev.MatchString(keymap.HUD...):
 					// Toggle HUD
 					viewState.ShowHUD = !viewState.ShowHUD
+				case ev.MatchString(keymap.Help...):
+					// Open the key help overlay, page through it, then close
+					switch {
+					case !viewState.ShowHelp:
+						viewState.ShowHelp, viewState.HelpPage = true, 0
+					case viewState.HelpPage+1 < helpPages:
+						viewState.HelpPage++
+					default:
+						viewState.ShowHelp = false
+					}
+				case ev.MatchString(keymap.Stats...):
+					// Toggle render stats overlay
+					viewState.ShowStats = !viewState.ShowStats
+				case ev.MatchString(keymap.SubdivMore...):
+					// Each level quadruples the triangles, so stop before it gets too slow
+					if viewState.Subdivide < maxSubdivide && base.TriangleCount()<<(2*(viewState.Subdivide+1)) <= maxSubdivideTriangles {
+						viewState.Subdivide++
+					}
+				case ev.MatchString(keymap.SubdivLess...):
+					viewState.Subdivide = max(viewState.Subdivide-1, 0)
+				case ev.MatchString(keymap.Smooth...):
+					viewState.Smooth = min(viewState.Smooth+1, maxSmooth)
+				case ev.MatchString(keymap.Unsmooth...):
+					viewState.Smooth = max(viewState.Smooth-1, 0)
+				case ev.MatchString(keymap.NextModel...):
+					modelStep++
+				case ev.MatchString(keymap.PrevModel...):
+					modelStep--
+				case ev.MatchString(keymap.Parts...):
+					viewState.ShowParts = !viewState.ShowParts
+					hud.pick = parts.Status()
+				case ev.MatchString(keymap.NextPart...):
+					parts.Step(1)
+					hud.pick = parts.Status()
+				case ev.MatchString(keymap.PrevPart...):
+					parts.Step(-1)
+					hud.pick = parts.Status()
+				case ev.MatchString(keymap.HidePart...):
+					parts.Toggle()
+					partsChanged = true
+					hud.pick = parts.Status()
+				case ev.MatchString(keymap.IsolatePart...):
+					parts.Isolate()
+					partsChanged = true
+					hud.pick = parts.Status()
+				case ev.MatchString(keymap.ExplodeMore...), ev.MatchString(keymap.ExplodeLess...):
+					step := explodeStep
+					if ev.MatchString(keymap.ExplodeLess...) {
+						step = -explodeStep
+					}
+					viewState.Explode = math.Max(0, math.Min(maxExplode, viewState.Explode+step))
+					partsChanged = true
+					hud.pick = explodeStatus(parts, viewState.Explode)
+				case ev.MatchString(keymap.Section...):
+					viewState.Section = (viewState.Section + 1) % (len(sectionAxes) + 1)
+					partsChanged = true
+					hud.pick = sectionStatus(viewState.Section, viewState.SectionAt)
+				case ev.MatchString(keymap.SectionBack...), ev.MatchString(keymap.SectionFwd...):
+					step := sectionStep
+					if ev.MatchString(keymap.SectionBack...) {
+						step = -sectionStep
+					}
+					viewState.SectionAt = math.Max(0, math.Min(1, viewState.SectionAt+step))
+					partsChanged = true
+					hud.pick = sectionStatus(viewState.Section, viewState.SectionAt)
+				case ev.MatchString(keymap.Screenshot...):
+					screenshotPending = true
+				case ev.MatchString(keymap.Record...):
+					recordToggle = true
+				case ev.MatchString(keymap.Measure...):
+					// Toggle measuring; clicks identify faces again when off
+					viewState.Measure = !viewState.Measure
+					measure.Reset()
+					hud.pick = ""
 				}
 
 			case uv.KeyReleaseEvent:
 				switch {
-				case ev.MatchString("w"), ev.MatchString("up"), ev.MatchString("s"), ev.MatchString("down"):
+				case ev.MatchString(keymap.PitchUp...), ev.MatchString(keymap.PitchDown...):
 					inputTorque.pitch = 0
-				case ev.MatchString("a"), ev.MatchString("left"), ev.MatchString("d"), ev.MatchString("right"):
+				case ev.MatchString(keymap.YawLeft...), ev.MatchString(keymap.YawRight...):
 					inputTorque.yaw = 0
-				case ev.MatchString("q"), ev.MatchString("e"):
+				case ev.MatchString(keymap.RollLeft...), ev.MatchString(keymap.RollRight...):
 					inputTorque.roll = 0
 				}
 
@@ -590,13 +1601,20 @@ func run(modelPath string) error {
 					// Set light position and exit light mode
 					viewState.LightDir = viewState.PendingLight
 					viewState.LightMode = false
+					viewState.Headlamp = false
 				} else {
 					mouseDown = true
+					panning = ev.Button == uv.MouseMiddle || ev.Mod.Contains(uv.ModShift)
 					lastMouseX, lastMouseY = ev.X, ev.Y
+					dragged = false
 				}
 
 			case uv.MouseReleaseEvent:
 				if !viewState.LightMode {
+					if mouseDown && !dragged && !panning {
+						pickCol, pickRow = ev.X, ev.Y
+						pickPending = true
+					}
 					mouseDown = false
 				}
 
@@ -607,25 +1625,32 @@ func run(modelPath string) error {
 				} else if mouseDown {
 					dx := ev.X - lastMouseX
 					dy := ev.Y - lastMouseY
-					rotation.ApplyImpulse(float64(dy)*0.03, float64(dx)*0.03, 0)
+					if dx != 0 || dy != 0 {
+						dragged = true
+					}
+					if panning {
+						// Move the camera against the drag so the model follows
+						// the cursor. Cells are one pixel wide and two tall.
+						before := camera.Position
+						camera.Pan(-float64(dx)/float64(2*height), float64(dy)/float64(height))
+						zoom.Shift(camera.Position.Sub(before))
+					} else if paused {
+						rotation.Turn(float64(dy)*pausedDragTurn, float64(dx)*pausedDragTurn, 0)
+					} else {
+						rotation.ApplyImpulse(float64(dy)*0.03, float64(dx)*0.03, 0)
+					}
 					lastMouseX, lastMouseY = ev.X, ev.Y
 				}
 
 			case uv.MouseWheelEvent:
 				switch ev.Button {
 				case uv.MouseWheelUp:
-					cameraZ -= 0.5
-					if cameraZ < 1 {
-						cameraZ = 1
-					}
+					zoom.ZoomAt(camera, ev.X, ev.Y, width, height, 1)
 				case uv.MouseWheelDown:
-					cameraZ += 0.5
-					if cameraZ > 20 {
-						cameraZ = 20
-					}
+					zoom.ZoomAt(camera, ev.X, ev.Y, width, height, -1)
 				}
-				camera.SetPosition(math3d.V3(0, 0, cameraZ))
 			}
+			mu.Unlock()
 		}
 	}()
 
@@ -633,12 +1658,46 @@ func run(modelPath string) error {
 	targetDuration := time.Second / time.Duration(targetFPS)
 	lastFrame := time.Now()
 
+	// Drop to --idle-fps once nothing has moved or happened for idleDelay
+	const idleDelay = time.Second
+	lastActive := time.Now()
+
 	cleanup := func() {
 		fmt.Fprint(os.Stdout, "\x1b[?1003l")
 		fmt.Fprint(os.Stdout, "\x1b[?1006l")
-		term.ExitAltScreen()
-		term.ShowCursor()
-		term.Shutdown(context.Background())
+		term.Stop()
+	}
+
+	// Switching models loads those not shown yet in the background; at
+	// most one load is current, and results from superseded ones are dropped
+	type modelLoad struct {
+		id    int
+		model *loadedModel
+		notes *loadLog
+		err   error
+	}
+	loads := make(chan modelLoad)
+	var loadID, loadTarget int
+	var loadStart time.Time
+	var cancelLoad context.CancelFunc // Set while a load is in flight
+
+	// Puts m in place of the current model
+	showModel := func(m *loadedModel) {
+		mesh, texture = m.mesh, m.texture
+		base, detailed = mesh, mesh
+		creasedAngle = -1
+		smoothed = []*models.Mesh{mesh}
+		subdivided = 0
+		parts = newPartsView(mesh)
+		partsChanged = true
+		viewState.Subdivide, viewState.Smooth = 0, 0
+		occlusion, occlusionStrength = occlusionTexture(mesh)
+		unit = lengthUnit(library.Path())
+		hud.filename = library.Title()
+		hud.polyCount = mesh.TriangleCount()
+		hud.size = formatSize(mesh.SourceSize(), unit)
+		measure.Reset()
+		hud.pick = ""
 	}
 
 	for {
@@ -649,6 +1708,7 @@ func run(modelPath string) error {
 		default:
 		}
 
+		mu.Lock()
 		now := time.Now()
 		dt := now.Sub(lastFrame).Seconds()
 		lastFrame = now
@@ -657,69 +1717,327 @@ func run(modelPath string) error {
 			dt = 0.1
 		}
 
-		// Apply input torque and decay it (key release events unreliable)
-		rotation.ApplyImpulse(
-			inputTorque.pitch*dt,
-			inputTorque.yaw*dt,
-			inputTorque.roll*dt,
-		)
-		inputTorque.pitch *= 0.9
-		inputTorque.yaw *= 0.9
-		inputTorque.roll *= 0.9
-
-		// Update springs (harmonica handles timing internally)
-		rotation.Update(!viewState.SpinMode)
+		// Apply input torque and decay it (key release events unreliable).
+		// While paused, keys turn the model directly instead.
+		if paused {
+			rotation.Turn(inputTorque.pitch*dt, inputTorque.yaw*dt, inputTorque.roll*dt)
+		} else {
+			rotation.ApplyImpulse(
+				inputTorque.pitch*dt,
+				inputTorque.yaw*dt,
+				inputTorque.roll*dt,
+			)
+		}
+		decay := math.Pow(torqueDecay, dt*60)
+		inputTorque.pitch *= decay
+		inputTorque.yaw *= decay
+		inputTorque.roll *= decay
+
+		// Advance rotation, zoom, and springs by the measured frame time.
+		// Paused rotation only moves a nominal frame at a time when stepped.
+		switch {
+		case !paused:
+			rotation.Update(dt, !viewState.SpinMode)
+		case stepPending:
+			rotation.Update(targetDuration.Seconds(), !viewState.SpinMode)
+			stepPending = false
+		}
+		zoom.Update(camera, dt)
 
 		// Build transform
-		transform := math3d.RotateX(rotation.Pitch.Position).
-			Mul(math3d.RotateY(rotation.Yaw.Position)).
-			Mul(math3d.RotateZ(rotation.Roll.Position))
+		transform := rotation.Matrix()
+
+		// Switch models, keeping the camera, lighting, and view modes. Every
+		// model is fitted to the same size, so the view frames them all. One
+		// not loaded yet loads in the background, without holding up frames
+		// or events, and replaces the current one when it arrives.
+		if modelStep != 0 {
+			from := library.Current()
+			if cancelLoad != nil {
+				from = loadTarget
+				cancelLoad()
+				cancelLoad = nil
+				hud.Toast(nil)
+			}
+			next := library.Step(from, modelStep)
+			modelStep = 0
+			switch m := library.Loaded(next); {
+			case next == library.Current():
+			case m != nil:
+				library.Show(next, m)
+				showModel(m)
+			default:
+				var loadCtx context.Context
+				loadCtx, cancelLoad = context.WithCancel(ctx)
+				loadID++
+				loadTarget, loadStart = next, time.Now()
+				go func(id int) {
+					notes := &loadLog{}
+					m, err := library.Load(loadCtx, next, notes)
+					select {
+					case wake <- struct{}{}:
+					default:
+					}
+					select {
+					case loads <- modelLoad{id: id, model: m, notes: notes, err: err}:
+					case <-loadCtx.Done():
+					}
+				}(loadID)
+			}
+		}
+		if cancelLoad != nil {
+			select {
+			case l := <-loads:
+				if l.id != loadID {
+					break // Superseded by a later switch
+				}
+				cancelLoad()
+				cancelLoad = nil
+				if l.err != nil {
+					hud.Toast(nil)
+					hud.pick = fmt.Sprintf("Could not load %s: %v", modelName(modelPaths[loadTarget]), l.err)
+				} else {
+					library.Show(loadTarget, l.model)
+					showModel(l.model)
+					hud.Toast(l.notes.Lines())
+				}
+			default:
+				frame := int(time.Since(loadStart)/spinnerInterval) % len(spinnerFrames)
+				hud.Toast([]string{fmt.Sprintf("%c Loading %s", spinnerFrames[frame], modelName(modelPaths[loadTarget]))})
+			}
+		}
+
+		if passes := viewState.Smooth; passes != len(smoothed)-1 {
+			for len(smoothed)-1 < passes {
+				next := smoothed[len(smoothed)-1].Clone()
+				next.Smooth(1, smoothLambda, true)
+				smoothed = append(smoothed, next)
+			}
+			smoothed = smoothed[:passes+1]
+			base = smoothed[passes]
+			subdivided = -1 // Rebuild from the new base
+		}
+		if level := viewState.Subdivide; level != subdivided {
+			detailed = base
+			if level > 0 {
+				detailed = base.Clone()
+				detailed.Subdivide(level)
+			}
+			subdivided = level
+			creasedAngle = -1 // Recompute normals for the new detail
+		}
+		if angle := viewState.SmoothAngle; angle != creasedAngle {
+			creased = detailed
+			if angle > 0 {
+				creased = detailed.Clone()
+				creased.CalculateNormalsWithAngle(angle)
+			}
+			creasedAngle = angle
+			partsChanged = true
+		}
+		if partsChanged {
+			partsChanged = false
+			mesh = creased.Explode(viewState.Explode).FilterGroups(parts.Visible)
+			mesh, capMesh = sectionMesh(mesh, viewState.Section, viewState.SectionAt)
+			heatColors = nil
+			hud.polyCount = mesh.TriangleCount()
+			hud.size = formatSize(detailed.SourceSize(), unit)
+		}
+
+		// Follow the terminal's size, reusing the buffers' memory so
+		// drag-resizing doesn't allocate a frame's worth every event
+		if resizePending {
+			resizePending = false
+			screen.Resize(width, height)
+			termRenderer.Resize(width, height)
+			fbWidth, fbHeight = termRenderer.FramebufferSize()
+			fb.Resize(fbWidth, fbHeight)
+			camera.SetAspectRatio(render.ViewAspect(fbWidth, fbHeight, cellAspect))
+		}
 
 		// Render
-		fb.Clear(render.RGB(bgR, bgG, bgB))
-		rasterizer.ClearDepth()
+		renderStart := time.Now()
 
-		// Choose light direction (pending if in light mode, otherwise current)
+		// Choose light direction: pending if in light mode, else the
+		// headlamp's, lighting from the camera so the visible side is always
+		// lit, else the placed light, which the headlamp leaves as it was
 		lightDir := viewState.LightDir
-		if viewState.LightMode {
+		switch {
+		case viewState.LightMode:
 			lightDir = viewState.PendingLight
+		case viewState.Headlamp:
+			lightDir = camera.Forward().Scale(-1)
 		}
 
-		// Set backface culling mode
-		rasterizer.DisableBackfaceCulling = !viewState.BackfaceCull
-
-		// Draw mesh based on render mode
-		switch viewState.RenderMode {
-		case RenderModeWireframe:
-			// X-ray wireframe mode
-			rasterizer.DrawMeshWireframe(mesh, transform, render.RGB(0, 255, 128))
-		case RenderModeFlat:
-			// Flat shading (no texture)
-			rasterizer.DrawMeshGouraudOpt(mesh, transform, render.RGB(200, 200, 200), lightDir)
-		default:
-			// Textured mode
+		scene.Mesh, scene.Cap = mesh, nil
+		if capMesh != nil {
+			scene.Cap = capMesh
+		}
+		scene.Transform = transform
+		scene.LightDir = lightDir
+		scene.Background = background
+		scene.Occlusion, scene.AOStrength = occlusion, occlusionStrength
+
+		// Cull whichever side the loader wound as the back
+		scene.CullBackfaces = viewState.BackfaceCull
+		scene.FrontFace = frontFace(mesh)
+		scene.FlatShading = viewState.FlatShading || viewState.RenderMode == RenderModeFlat
+		scene.Workers = shadingWorkers(mesh.TriangleCount(), fbWidth*fbHeight)
+		scene.Wireframe = viewState.RenderMode == RenderModeWireframe
+		scene.WireColor = viewState.WireColor
+		scene.UVLayout = viewState.UVView
+
+		shownTexture := texture
+		if i := viewState.InspectTexture; i > 0 {
+			shownTexture = inspectTextures[i-1].Texture
+		}
+		shownTexture.FilterMode = viewState.Filter
+		scene.Color = render.RGB(200, 200, 200)
+		scene.Texture, scene.VertexColors = nil, nil
+		switch {
+		case viewState.UVView:
+			// The texture layout in place of the model
+			scene.Color = render.RGB(90, 90, 90)
 			if viewState.TextureEnabled {
-				rasterizer.DrawMeshTexturedOpt(mesh, transform, texture, lightDir)
+				scene.Texture = shownTexture
+			}
+		case viewState.RenderMode == RenderModeHeatmap:
+			if heatColors == nil || heatScalar != viewState.HeatmapScalar {
+				heatColors = heatmapColors(mesh, viewState.HeatmapScalar)
+				heatScalar = viewState.HeatmapScalar
+			}
+			scene.VertexColors = heatColors
+		case viewState.RenderMode == RenderModeTextured && viewState.TextureEnabled:
+			scene.Texture = shownTexture
+		}
+		scene.RenderTo(fb)
+		rasterizer := scene.Rasterizer()
+
+		hud.renderTime = time.Since(renderStart)
+
+		// Identify the clicked face. Each cell holds two pixel rows; use the top one.
+		if pickPending {
+			pickPending = false
+			face := rasterizer.PickFace(pickCol, pickRow*2)
+			picked := mesh
+			if capMesh != nil && face >= mesh.TriangleCount() {
+				picked, face = capMesh, face-mesh.TriangleCount()
+			}
+			switch {
+			case viewState.Measure:
+				if p, ok := surfacePoint(camera, picked, transform, face, pickCol, pickRow*2, fbWidth, fbHeight); ok {
+					measure.Add(p)
+				}
+			case picked == capMesh:
+				hud.pick = "Section cap"
+			default:
+				renumbered := decimate < 1 || viewState.Subdivide > 0 || viewState.Section != 0 ||
+					mesh.TriangleCount() != creased.TriangleCount()
+				hud.pick = describeFace(mesh, face, renumbered)
+			}
+		}
+		if viewState.Measure {
+			hud.pick = measure.Status(mesh.SourceScale, unit)
+			measure.Draw(fb, camera, transform, palette.Marker)
+		}
+
+		// Saved before the HUD, which is drawn into terminal cells, not the frame
+		if screenshotPending {
+			screenshotPending = false
+			path := captureName(library.Path(), time.Now(), ".png")
+			if err := fb.SavePNG(path); err != nil {
+				hud.pick = fmt.Sprintf("Screenshot failed: %v", err)
+			} else {
+				hud.pick = "Saved " + path
+			}
+		}
+		if recordToggle && recorder == nil {
+			recordToggle = false
+			recorder = render.NewGIFRecorder(recordFPS, maxRecordFrames)
+		}
+		if recorder != nil {
+			// Stops when toggled, when full, or when a resize changes the frame size
+			if !recordToggle && recorder.Capture(fb, time.Now()) {
+				hud.pick = fmt.Sprintf("● Recording (%d frames)", recorder.Frames())
 			} else {
-				rasterizer.DrawMeshGouraudOpt(mesh, transform, render.RGB(200, 200, 200), lightDir)
+				recordToggle = false
+				path := captureName(library.Path(), time.Now(), ".gif")
+				if err := recorder.SaveGIF(path); err != nil {
+					hud.pick = fmt.Sprintf("Recording failed: %v", err)
+				} else {
+					hud.pick = fmt.Sprintf("Saved %s (%d frames)", path, recorder.Frames())
+				}
+				recorder = nil
 			}
 		}
 
 		// Display
-		termRenderer.Render(fb)
+		termRenderer.RenderDiff(fb)
+
+		// HUD overlay, drawn over the frame's cells. Its rows are redrawn
+		// from the framebuffer next frame so toggling it off works.
+		hud.UpdateFPS()
+		hud.Render(screen, width, height, viewState)
+		termRenderer.InvalidateRow(0)
+		termRenderer.InvalidateRow(height - 1)
+		if row := hud.RenderPick(screen, height, viewState); row >= 0 {
+			termRenderer.InvalidateRow(row)
+		}
+		top, bottom := hud.RenderToast(screen, width, height, viewState)
+		for row := top; row <= bottom; row++ {
+			termRenderer.InvalidateRow(row)
+		}
+		if viewState.ShowStats {
+			top, bottom := hud.RenderStats(screen, height, rasterizer)
+			for row := top; row <= bottom; row++ {
+				termRenderer.InvalidateRow(row)
+			}
+		}
+		if viewState.ShowParts && parts.Len() > 1 {
+			top, bottom := parts.Render(screen, width, height)
+			for row := top; row <= bottom; row++ {
+				termRenderer.InvalidateRow(row)
+			}
+		}
+		if viewState.ShowHelp {
+			var top, bottom int
+			top, bottom, helpPages = keymap.RenderHelp(screen, width, height, viewState.HelpPage)
+			for row := top; row <= bottom; row++ {
+				termRenderer.InvalidateRow(row)
+			}
+		}
+
 		if err := termRenderer.Flush(); err != nil {
+			mu.Unlock()
 			cleanup()
 			return fmt.Errorf("flush: %w", err)
 		}
 
-		// HUD overlay (always update FPS, render clears lines when HUD off)
-		hud.UpdateFPS()
-		hud.Render(width, height, viewState)
+		// Frame timing. Torque decays geometrically, so treat small as zero.
+		torque := math.Abs(inputTorque.pitch) + math.Abs(inputTorque.yaw) + math.Abs(inputTorque.roll)
+		spinning := !paused && (rotation.Moving() || viewState.SpinMode)
+		if spinning || zoom.Moving(camera) || mouseDown || torque > 1e-3 {
+			lastActive = time.Now()
+		}
+		idle := idleFPS > 0 && idleFPS < targetFPS && time.Since(lastActive) > idleDelay
+		frameDuration := targetDuration
+		if idle {
+			frameDuration = time.Second / time.Duration(idleFPS)
+		}
+		mu.Unlock()
 
-		// Frame timing
 		elapsed := time.Since(now)
-		if elapsed < targetDuration {
-			time.Sleep(targetDuration - elapsed)
+		if elapsed < frameDuration {
+			select {
+			case <-wake:
+				lastActive = time.Now()
+				if !idle {
+					time.Sleep(time.Until(now.Add(targetDuration)))
+				}
+				// Coming out of idle renders right away
+			case <-time.After(frameDuration - elapsed):
+			case <-ctx.Done():
+			}
 		}
 	}
 }
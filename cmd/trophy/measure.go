@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+	"github.com/taigrr/trophy/pkg/models"
+	"github.com/taigrr/trophy/pkg/render"
+)
+
+// measurement collects two clicked surface points, in model space so the
+// distance doesn't change as the model turns.
+type measurement struct {
+	Points []math3d.Vec3
+}
+
+// Add records a point, starting a new measurement once one is complete.
+func (m *measurement) Add(p math3d.Vec3) {
+	if len(m.Points) == 2 {
+		m.Points = m.Points[:0]
+	}
+	m.Points = append(m.Points, p)
+}
+
+// Reset discards any points.
+func (m *measurement) Reset() {
+	m.Points = m.Points[:0]
+}
+
+// Status describes the measurement for the HUD, converting model-space
+// distance to real units with the mesh's SourceScale.
+func (m *measurement) Status(scale float64, unit string) string {
+	switch len(m.Points) {
+	case 0:
+		return "Measure: click the first point"
+	case 1:
+		return "Measure: click the second point"
+	default:
+		return fmt.Sprintf("Measure: %s (click to measure again)", formatLength(m.Points[0].Distance(m.Points[1])*scale, unit))
+	}
+}
+
+// Draw marks the points, and the segment between them, over the frame in
+// color c. Markers ignore depth so they stay visible when the model turns away.
+func (m *measurement) Draw(fb *render.Framebuffer, camera *render.Camera, transform math3d.Mat4, c render.Color) {
+	var xs, ys [2]int
+	for i, p := range m.Points {
+		x, y, _, ok := camera.WorldToScreen(transform.MulVec3(p), fb.Width, fb.Height)
+		if !ok {
+			return
+		}
+		xs[i], ys[i] = int(x), int(y)
+	}
+	if len(m.Points) == 2 {
+		fb.DrawLine(xs[0], ys[0], xs[1], ys[1], c)
+	}
+	for i := range m.Points {
+		fb.DrawRect(xs[i]-1, ys[i]-1, 3, 3, c)
+	}
+}
+
+// surfacePoint returns where the ray through framebuffer pixel (x, y)
+// meets face of mesh drawn with transform, in the mesh's own space.
+// The face comes from the pick buffer, so only its plane is intersected.
+func surfacePoint(camera *render.Camera, mesh *models.Mesh, transform math3d.Mat4, face, x, y, width, height int) (math3d.Vec3, bool) {
+	if face < 0 || face >= mesh.TriangleCount() {
+		return math3d.Vec3{}, false
+	}
+
+	// Bring the ray into model space rather than the triangle into world space
+	origin, dir := camera.ScreenToRay(float64(x)+0.5, float64(y)+0.5, width, height)
+	inv := transform.Inverse()
+	origin = inv.MulVec3(origin)
+	dir = inv.MulVec3Dir(dir)
+
+	f := mesh.GetFace(face)
+	p0, _, _ := mesh.GetVertex(f[0])
+	p1, _, _ := mesh.GetVertex(f[1])
+	p2, _, _ := mesh.GetVertex(f[2])
+	normal := p1.Sub(p0).Cross(p2.Sub(p0))
+
+	denom := normal.Dot(dir)
+	if math.Abs(denom) < 1e-12 {
+		return math3d.Vec3{}, false
+	}
+	t := normal.Dot(p0.Sub(origin)) / denom
+	if t < 0 {
+		return math3d.Vec3{}, false
+	}
+	return origin.Add(dir.Scale(t)), true
+}
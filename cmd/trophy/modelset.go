@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/taigrr/trophy/pkg/models"
+	"github.com/taigrr/trophy/pkg/render"
+)
+
+// modelSet is the list of models being viewed. Each is loaded the first
+// time it's shown and kept, so switching back to it is instant.
+type modelSet struct {
+	paths   []string
+	current int
+	loaded  []*loadedModel
+}
+
+// loadedModel is a model as returned by loadModel.
+type loadedModel struct {
+	mesh    *models.Mesh
+	texture *render.Texture
+}
+
+func newModelSet(paths []string) *modelSet {
+	return &modelSet{paths: paths, loaded: make([]*loadedModel, len(paths))}
+}
+
+// Path returns the current model's file.
+func (s *modelSet) Path() string {
+	return s.paths[s.current]
+}
+
+// stdinPath is the model path that reads from stdin.
+const stdinPath = "-"
+
+// modelName names the model at path for messages and the HUD.
+func modelName(path string) string {
+	if path == stdinPath {
+		return "stdin"
+	}
+	if isURL(path) {
+		return urlName(path)
+	}
+	return filepath.Base(path)
+}
+
+// Title names the current model for the HUD, with its place in the list
+// when there's more than one.
+func (s *modelSet) Title() string {
+	name := modelName(s.Path())
+	if len(s.paths) > 1 {
+		name = fmt.Sprintf("%s (%d/%d)", name, s.current+1, len(s.paths))
+	}
+	return name
+}
+
+// Current returns the index of the current model.
+func (s *modelSet) Current() int {
+	return s.current
+}
+
+// Step returns the index n models away from model from, wrapping around
+// at either end.
+func (s *modelSet) Step(from, n int) int {
+	count := len(s.paths)
+	return ((from+n)%count + count) % count
+}
+
+// Loaded returns model i if it has been loaded, or nil.
+func (s *modelSet) Loaded(i int) *loadedModel {
+	return s.loaded[i]
+}
+
+// Load loads model i without keeping it or making it current; pass it to
+// Show for that. It only reads the set's paths, so it may run on another
+// goroutine while the set is in use.
+func (s *modelSet) Load(ctx context.Context, i int, log io.Writer) (*loadedModel, error) {
+	mesh, texture, err := loadModel(ctx, s.paths[i], log)
+	if err != nil {
+		return nil, err
+	}
+	return &loadedModel{mesh: mesh, texture: texture}, nil
+}
+
+// Show keeps m as model i and makes it current.
+func (s *modelSet) Show(i int, m *loadedModel) {
+	s.loaded[i] = m
+	s.current = i
+}
+
+// Select makes model i current, loading it first if needed. On error the
+// current model is unchanged.
+func (s *modelSet) Select(ctx context.Context, i int, log io.Writer) (*loadedModel, error) {
+	m := s.loaded[i]
+	if m == nil {
+		var err error
+		if m, err = s.Load(ctx, i, log); err != nil {
+			return nil, err
+		}
+	}
+	s.Show(i, m)
+	return m, nil
+}
+
+// SelectFirst makes the first model that loads current, noting each one
+// skipped in log. It fails only if none load.
+func (s *modelSet) SelectFirst(ctx context.Context, log io.Writer) (*loadedModel, error) {
+	if len(s.paths) == 1 {
+		return s.Select(ctx, 0, log)
+	}
+	var errs []error
+	for i := range s.paths {
+		m, err := s.Select(ctx, i, log)
+		if err == nil {
+			return m, nil
+		}
+		fmt.Fprintf(log, "Warning: %v\n", err)
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("no model could be loaded: %w", errors.Join(errs...))
+}
+
+// modelExts are the file extensions loadMesh can open.
+var modelExts = []string{".obj", ".glb", ".gltf", ".stl"}
+
+// isModelFile reports whether path has an extension loadMesh can open.
+func isModelFile(path string) bool {
+	return slices.Contains(modelExts, strings.ToLower(filepath.Ext(path)))
+}
+
+// expandModelPaths replaces each directory in args with the models inside
+// it, descending into subdirectories if recurse is set, and each glob
+// pattern the shell left unexpanded with its matches. Files are kept as
+// given, whatever their extension, so loading reports unsupported ones.
+func expandModelPaths(args []string, recurse bool) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		if arg == stdinPath || isURL(arg) {
+			paths = append(paths, arg)
+			continue
+		}
+		info, err := os.Stat(arg)
+		if err != nil {
+			matches, _ := filepath.Glob(arg)
+			if len(matches) == 0 {
+				return nil, err
+			}
+			for _, m := range matches {
+				if isModelFile(m) {
+					paths = append(paths, m)
+				}
+			}
+			continue
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+
+		found := 0
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != arg && !recurse {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if isModelFile(path) {
+				paths = append(paths, path)
+				found++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list models: %w", err)
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("no models (%s) in %s", strings.Join(modelExts, ", "), arg)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no models match %s", strings.Join(args, " "))
+	}
+	return paths, nil
+}
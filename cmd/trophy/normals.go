@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+// smoothAngles are the crease angles, in degrees, the smoothing angle key
+// steps through; 0 keeps the model's own normals.
+var smoothAngles = []float64{0, 30, 45, 60, 90, 180}
+
+// nextSmoothAngle returns the smoothing angle after angle, wrapping to 0.
+// Angles between the steps, from --smooth-angle, go to the next step up.
+func nextSmoothAngle(angle float64) float64 {
+	for _, a := range smoothAngles {
+		if a > angle {
+			return a
+		}
+	}
+	return 0
+}
+
+// smoothAngleStatus describes the smoothing angle for the HUD.
+func smoothAngleStatus(angle float64) string {
+	if angle == 0 {
+		return "Model's normals"
+	}
+	return fmt.Sprintf("Smoothing angle %g°", angle)
+}
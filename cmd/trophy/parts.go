@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/taigrr/trophy/pkg/models"
+)
+
+// Limits for the explode keys
+const (
+	explodeStep = 0.25
+	maxExplode  = 2.0
+)
+
+// explodeStatus describes the explode amount for the HUD.
+func explodeStatus(parts *partsView, amount float64) string {
+	if parts.Len() < 2 {
+		return "Model has no separate parts"
+	}
+	return fmt.Sprintf("Explode %.2f", amount)
+}
+
+// partsView tracks which of a model's parts (its mesh groups) are shown,
+// and which one the part keys act on.
+type partsView struct {
+	names    []string
+	sizes    []int // Triangles in each part
+	hidden   []bool
+	selected int
+}
+
+func newPartsView(mesh *models.Mesh) *partsView {
+	return &partsView{
+		names:  mesh.Groups,
+		sizes:  mesh.GroupSizes(),
+		hidden: make([]bool, len(mesh.Groups)),
+	}
+}
+
+// Len returns how many parts the model has.
+func (p *partsView) Len() int {
+	return len(p.names)
+}
+
+// Visible reports whether part g is shown.
+func (p *partsView) Visible(g int) bool {
+	return g < 0 || g >= len(p.hidden) || !p.hidden[g]
+}
+
+// Step moves the selection n parts along, wrapping around.
+func (p *partsView) Step(n int) {
+	if count := len(p.names); count > 0 {
+		p.selected = ((p.selected+n)%count + count) % count
+	}
+}
+
+// Toggle shows or hides the selected part.
+func (p *partsView) Toggle() {
+	if len(p.hidden) > 0 {
+		p.hidden[p.selected] = !p.hidden[p.selected]
+	}
+}
+
+// Isolate shows only the selected part, or every part if that's already
+// the case.
+func (p *partsView) Isolate() {
+	isolated := true
+	for i, h := range p.hidden {
+		if h != (i != p.selected) {
+			isolated = false
+			break
+		}
+	}
+	for i := range p.hidden {
+		p.hidden[i] = !isolated && i != p.selected
+	}
+}
+
+// Status describes the selected part for the HUD.
+func (p *partsView) Status() string {
+	if len(p.names) < 2 {
+		return "Model has no separate parts"
+	}
+	status := fmt.Sprintf("Part %d/%d: %s", p.selected+1, len(p.names), p.names[p.selected])
+	if p.hidden[p.selected] {
+		status += " (hidden)"
+	}
+	return status
+}
+
+// Render draws the part list down the right side, scrolled to keep the
+// selected part in view. Returns the first and last rows it drew on.
+func (p *partsView) Render(scr uv.Screen, width, height int) (top, bottom int) {
+	const (
+		reset   = "\x1b[0m"
+		bgBlack = "\x1b[40m"
+		bgBlue  = "\x1b[44m"
+		fgWhite = "\x1b[97m"
+		fgGray  = "\x1b[90m"
+	)
+
+	// Every line is " [✓] <name:20> <tris:7> " so the box has straight edges
+	const boxWidth = 34
+
+	top = 1
+	rows := min(len(p.names), height-2-top)
+	first := min(max(p.selected-rows/2, 0), len(p.names)-rows)
+	col := max(width-boxWidth, 0)
+	bottom = top
+	for i := range rows {
+		part := first + i
+		bg, fg, check := bgBlack, fgWhite, "[✓]"
+		if p.hidden[part] {
+			fg, check = fgGray, "[ ]"
+		}
+		if part == p.selected {
+			bg = bgBlue
+		}
+		name := ansi.Truncate(p.names[part], 20, "…")
+		line := fmt.Sprintf("%s%s %s %s%s %7d %s",
+			bg, fg, check, name, fmt.Sprintf("%*s", 20-ansi.StringWidth(name), ""), p.sizes[part], reset)
+		drawText(scr, col, top+i, line)
+		bottom = top + i
+	}
+	return top, bottom
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Limits on each file fetched for a model given by URL
+const (
+	maxDownloadSize = 256 << 20
+	downloadTimeout = time.Minute
+)
+
+var httpClient = &http.Client{Timeout: downloadTimeout}
+
+// isURL reports whether a model path is an http or https URL.
+func isURL(p string) bool {
+	return strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://")
+}
+
+// urlName returns the file name at the end of a URL's path, e.g.
+// "model.glb" for "https://example.com/assets/model.glb?v=2".
+func urlName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+		return rawURL
+	}
+	return path.Base(u.Path)
+}
+
+// download fetches rawURL into memory, failing past maxDownloadSize.
+func download(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", rawURL, err)
+	}
+	if len(data) > maxDownloadSize {
+		return nil, fmt.Errorf("download %s: larger than %d MiB", rawURL, maxDownloadSize>>20)
+	}
+	return data, nil
+}
+
+// urlFS reads a glTF's external buffers and images relative to the URL it
+// was downloaded from. It only supports fs.ReadFile, which is all the
+// loader uses.
+type urlFS struct {
+	ctx  context.Context
+	base *url.URL
+}
+
+func (u urlFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+}
+
+func (u urlFS) ReadFile(name string) ([]byte, error) {
+	ref, err := url.Parse(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return download(u.ctx, u.base.ResolveReference(ref).String())
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+	"github.com/taigrr/trophy/pkg/models"
+)
+
+// sectionAxes are the section plane normals the section key cycles
+// through after "off".
+var sectionAxes = []struct {
+	Name   string
+	Normal math3d.Vec3
+}{
+	{"X", math3d.V3(1, 0, 0)},
+	{"Y", math3d.V3(0, 1, 0)},
+	{"Z", math3d.V3(0, 0, 1)},
+}
+
+// How far each section key press moves the plane, as a fraction of the
+// model's extent along the axis
+const sectionStep = 0.05
+
+// sectionMesh cuts mesh with the section plane for axis (1-based; 0 is
+// off) at fraction at of the way across its bounds, returning what's left
+// and the cap closing the cut, or nil with the section off.
+func sectionMesh(mesh *models.Mesh, axis int, at float64) (section, capMesh *models.Mesh) {
+	if axis == 0 {
+		return mesh, nil
+	}
+	normal := sectionAxes[axis-1].Normal
+	lo, hi := normal.Dot(mesh.BoundsMin), normal.Dot(mesh.BoundsMax)
+	offset := lo + at*(hi-lo)
+	return mesh.Section(normal, offset), mesh.SectionCap(normal, offset)
+}
+
+// sectionStatus describes the section plane for the HUD.
+func sectionStatus(axis int, at float64) string {
+	if axis == 0 {
+		return "Section off"
+	}
+	return fmt.Sprintf("Section %s at %.0f%%", sectionAxes[axis-1].Name, at*100)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// Spin speed limits and the factor the speed keys change it by
+const (
+	defaultSpinSpeed = 1.2 // Radians per second
+	minSpinSpeed     = 0.05
+	maxSpinSpeed     = 20.0
+	spinSpeedStep    = 1.25
+)
+
+// spinAxes are the named axes, in the order the spin axis key cycles them
+var spinAxes = []struct {
+	Name string
+	Axis math3d.Vec3
+}{
+	{"yaw", math3d.V3(0, 1, 0)},
+	{"pitch", math3d.V3(1, 0, 0)},
+	{"roll", math3d.V3(0, 0, 1)},
+}
+
+// parseSpinAxis accepts "yaw", "pitch", "roll", or an "x,y,z" vector
+func parseSpinAxis(s string) (math3d.Vec3, error) {
+	for _, a := range spinAxes {
+		if strings.EqualFold(s, a.Name) {
+			return a.Axis, nil
+		}
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return math3d.Vec3{}, fmt.Errorf("invalid spin axis %q (use yaw, pitch, roll, or x,y,z)", s)
+	}
+	var v [3]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return math3d.Vec3{}, fmt.Errorf("invalid spin axis %q: %w", s, err)
+		}
+		v[i] = f
+	}
+	axis := math3d.V3(v[0], v[1], v[2])
+	if axis.Len() == 0 {
+		return math3d.Vec3{}, fmt.Errorf("invalid spin axis %q: zero vector", s)
+	}
+	return axis.Normalize(), nil
+}
+
+// nextSpinAxis returns the named axis after axis, or yaw for a custom vector
+func nextSpinAxis(axis math3d.Vec3) math3d.Vec3 {
+	for i, a := range spinAxes {
+		if a.Axis == axis {
+			return spinAxes[(i+1)%len(spinAxes)].Axis
+		}
+	}
+	return spinAxes[0].Axis
+}
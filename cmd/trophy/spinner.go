@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/taigrr/trophy/pkg/models"
+)
+
+// spinnerFrames are drawn in turn while a load is in progress, one per
+// spinnerInterval
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+const spinnerInterval = 100 * time.Millisecond
+
+// loadSpinner animates a status line while a model loads, so a large file
+// doesn't look like a hang.
+type loadSpinner struct {
+	w    io.Writer
+	name string
+
+	mu       sync.Mutex
+	progress models.LoadProgress
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startSpinner begins drawing the status line for name on w.
+func startSpinner(w io.Writer, name string) *loadSpinner {
+	s := &loadSpinner{
+		w:    w,
+		name: name,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Update records the latest load progress. Safe to call from any goroutine.
+func (s *loadSpinner) Update(p models.LoadProgress) {
+	s.mu.Lock()
+	// Keep the byte count once reading is done and mesh counts take over
+	if p.BytesTotal == 0 {
+		p.BytesRead, p.BytesTotal = s.progress.BytesRead, s.progress.BytesTotal
+	}
+	s.progress = p
+	s.mu.Unlock()
+}
+
+// Stop erases the status line and waits for the spinner to exit.
+func (s *loadSpinner) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *loadSpinner) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		s.mu.Lock()
+		p := s.progress
+		s.mu.Unlock()
+
+		status := ""
+		switch {
+		case p.MeshesTotal > 0:
+			status = fmt.Sprintf(" (%d/%d meshes)", p.MeshesDone, p.MeshesTotal)
+		case p.BytesTotal > 0:
+			status = fmt.Sprintf(" (%d%%)", p.BytesRead*100/p.BytesTotal)
+		}
+		fmt.Fprintf(s.w, "\r\x1b[2K%c Loading %s%s  (ctrl+c to cancel)",
+			spinnerFrames[frame%len(spinnerFrames)], s.name, status)
+
+		select {
+		case <-s.stop:
+			fmt.Fprint(s.w, "\r\x1b[2K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
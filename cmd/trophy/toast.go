@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+	xterm "github.com/charmbracelet/x/term"
+)
+
+// toastDuration is how long load messages stay on screen
+const toastDuration = 5 * time.Second
+
+// loadLog collects the messages written while a model loads, so the viewer
+// can show them once the alt screen is up instead of printing them to a
+// screen it's about to hide.
+type loadLog struct {
+	progress io.Writer // Where the load spinner draws, or nil for none
+	lines    []string
+	partial  string
+}
+
+// Write records each complete line of p.
+func (l *loadLog) Write(p []byte) (int, error) {
+	text := l.partial + string(p)
+	lines := strings.Split(text, "\n")
+	l.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		if line != "" {
+			l.lines = append(l.lines, line)
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the messages written so far.
+func (l *loadLog) Lines() []string {
+	return l.lines
+}
+
+// spinnerOutput returns the terminal a load logging to log can draw its
+// spinner on, or nil if there isn't one.
+func spinnerOutput(log io.Writer) io.Writer {
+	if l, ok := log.(*loadLog); ok {
+		log = l.progress
+	}
+	if f, ok := log.(*os.File); ok && xterm.IsTerminal(f.Fd()) {
+		return f
+	}
+	return nil
+}
+
+// Toast shows lines above the HUD's bottom rows for toastDuration.
+func (h *HUD) Toast(lines []string) {
+	h.toast = lines
+	h.toastUntil = time.Now().Add(toastDuration)
+}
+
+// RenderToast draws the toast lines, if they haven't expired, above the
+// pick row. They show even with the HUD hidden, since they're shown once.
+// Returns the first and last rows it drew on; bottom < top if none.
+func (h *HUD) RenderToast(scr uv.Screen, width, height int, viewState *ViewState) (top, bottom int) {
+	const (
+		reset    = "\x1b[0m"
+		bgBlack  = "\x1b[40m"
+		fgYellow = "\x1b[93m"
+	)
+
+	if time.Now().After(h.toastUntil) {
+		h.toast = nil
+	}
+	if len(h.toast) == 0 || viewState.LightMode {
+		return 0, -1
+	}
+
+	// Rows 1 to bottom are free; newest lines are kept if they don't all fit
+	bottom = height - 3
+	if bottom < 1 {
+		return 0, -1
+	}
+	lines := h.toast[max(len(h.toast)-bottom, 0):]
+	top = bottom - len(lines) + 1
+	for i, line := range lines {
+		line = ansi.Truncate(line, max(width-2, 1), "…")
+		drawText(scr, 0, top+i, fmt.Sprintf("%s%s %s %s", bgBlack, fgYellow, line, reset))
+	}
+	return top, bottom
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Limits for --gamma and --brightness and the keys that adjust them live
+const (
+	minGamma      = 0.2
+	maxGamma      = 4.0
+	minBrightness = 0.1
+	maxBrightness = 4.0
+	toneStep      = 0.1
+)
+
+// stepTone moves v by delta within [lo, hi], rounded to toneStep so repeated
+// presses land on round numbers.
+func stepTone(v, delta, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, math.Round((v+delta)/toneStep)*toneStep))
+}
+
+// toneStatus describes the display tone for the HUD.
+func toneStatus(gamma, brightness float64) string {
+	return fmt.Sprintf("Brightness %.1f · Gamma %.1f", brightness, gamma)
+}
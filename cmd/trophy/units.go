@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// lengthUnit returns the unit to label real-world lengths with: --unit when
+// given, meters for glTF, whose spec fixes the unit, or "units" otherwise,
+// since OBJ and STL don't say.
+func lengthUnit(modelPath string) string {
+	if unitName != "" {
+		return unitName
+	}
+	switch strings.ToLower(filepath.Ext(modelPath)) {
+	case ".glb", ".gltf":
+		return "m"
+	}
+	return "units"
+}
+
+// formatLength formats a length with its unit, e.g. "1.8 m".
+func formatLength(v float64, unit string) string {
+	return fmt.Sprintf("%.3g %s", v, unit)
+}
+
+// formatSize formats bounding box dimensions, e.g. "0.4 x 1.8 x 0.3 m".
+func formatSize(size math3d.Vec3, unit string) string {
+	return fmt.Sprintf("%.3g x %.3g x %.3g %s", size.X, size.Y, size.Z, unit)
+}
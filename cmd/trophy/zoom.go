@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+
+	"github.com/charmbracelet/harmonica"
+	"github.com/taigrr/trophy/pkg/math3d"
+	"github.com/taigrr/trophy/pkg/render"
+)
+
+// Zoom tuning
+const (
+	zoomStep      = 0.1  // Fraction of the current distance moved per step
+	zoomFrequency = 10.0 // Spring speed: higher settles faster
+	zoomDamping   = 1.0  // Critically damped, no overshoot
+
+	// Framing relative to the model's bounding sphere
+	homeMargin = 1.45 // Initial and reset view, a little room around the model
+	maxMargin  = 10.0 // Furthest zoom, before the model shrinks to a dot
+)
+
+// Field of view limits and step, in degrees
+const (
+	defaultFOV = 60.0
+	minFOV     = 10.0  // Nearly orthographic, for undistorted faces
+	maxFOV     = 120.0 // Dramatic close-ups
+	fovStep    = 5.0
+)
+
+// cameraZoom eases the camera toward a target position so zoom steps glide
+// instead of jumping. Pan moves the target and camera together.
+type cameraZoom struct {
+	Target     math3d.Vec3
+	MinZ, MaxZ float64 // Distance limits from the model plane
+	vel        math3d.Vec3
+}
+
+// newCameraZoom starts at rest at the camera's position, with limits
+// that keep the camera outside a model of the given bounding radius
+// and close enough that it doesn't vanish
+func newCameraZoom(camera *render.Camera, radius float64) *cameraZoom {
+	return &cameraZoom{
+		Target: camera.Position,
+		MinZ:   radius + camera.Near,
+		MaxZ:   camera.FitDistance(radius) * maxMargin,
+	}
+}
+
+// stepDistance moves d by steps zoom steps (positive zooms in), each a
+// fixed fraction of the distance so zooming feels the same near and far
+func (z *cameraZoom) stepDistance(d float64, steps int) float64 {
+	d *= math.Pow(1-zoomStep, float64(steps))
+	return math.Max(z.MinZ, math.Min(z.MaxZ, d))
+}
+
+// Zoom steps the target distance, keeping its X/Y
+func (z *cameraZoom) Zoom(steps int) {
+	z.Target.Z = z.stepDistance(z.Target.Z, steps)
+}
+
+// ZoomAt steps the target distance while keeping the point under the
+// terminal cell (col, row) fixed on screen
+func (z *cameraZoom) ZoomAt(camera *render.Camera, col, row, width, height, steps int) {
+	newZ := z.stepDistance(z.Target.Z, steps)
+	pos := z.Target
+
+	// Ray through the cursor cell's center in framebuffer pixels
+	origin, dir := camera.ScreenToRay(float64(col)+0.5, float64(row*2+1), width, height*2)
+	if dir.Z >= 0 || pos.Z <= 0 {
+		z.Target.Z = newZ
+		return
+	}
+
+	// Point under the cursor on the z=0 plane through the model center.
+	// Sliding the target along the line to it keeps its screen position.
+	hit := origin.Add(dir.Scale(-origin.Z / dir.Z))
+	z.Target = hit.Add(pos.Sub(hit).Scale(newZ / pos.Z))
+}
+
+// Shift moves the target by d, for pans that should not be eased
+func (z *cameraZoom) Shift(d math3d.Vec3) {
+	z.Target = z.Target.Add(d)
+}
+
+// Snap jumps the camera and target to pos and stops any easing
+func (z *cameraZoom) Snap(camera *render.Camera, pos math3d.Vec3) {
+	z.Target = pos
+	z.vel = math3d.Vec3{}
+	camera.SetPosition(pos)
+}
+
+// Update eases the camera toward the target over dt seconds
+func (z *cameraZoom) Update(camera *render.Camera, dt float64) {
+	if !z.Moving(camera) {
+		return
+	}
+	spring := harmonica.NewSpring(dt, zoomFrequency, zoomDamping)
+	pos := camera.Position
+	pos.X, z.vel.X = spring.Update(pos.X, z.vel.X, z.Target.X)
+	pos.Y, z.vel.Y = spring.Update(pos.Y, z.vel.Y, z.Target.Y)
+	pos.Z, z.vel.Z = spring.Update(pos.Z, z.vel.Z, z.Target.Z)
+	camera.SetPosition(pos)
+}
+
+// Moving reports whether the camera hasn't yet settled on the target
+func (z *cameraZoom) Moving(camera *render.Camera) bool {
+	return camera.Position.Sub(z.Target).Len() > 1e-4 || z.vel.Len() > 1e-4
+}
+
+// SetFOV changes the camera's field of view, clamped to the allowed
+// range, and moves the camera and target so the model keeps its size on
+// screen. The zoom-out limit and home position, home, are refit to the new
+// FOV; the refit home is returned.
+func (z *cameraZoom) SetFOV(camera *render.Camera, fov float64, home math3d.Vec3) math3d.Vec3 {
+	fov = math.Max(minFOV*math.Pi/180, math.Min(maxFOV*math.Pi/180, fov))
+	before := camera.FitDistance(1)
+	camera.SetFOV(fov)
+	k := camera.FitDistance(1) / before
+
+	z.MaxZ *= k
+	z.Target.Z = math.Max(z.MinZ, math.Min(z.MaxZ, z.Target.Z*k))
+	pos := camera.Position
+	pos.Z = math.Max(z.MinZ, math.Min(z.MaxZ, pos.Z*k))
+	camera.SetPosition(pos)
+	home.Z *= k
+	return home
+}
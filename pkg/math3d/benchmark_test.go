@@ -93,3 +93,46 @@ func BenchmarkViewProjection(b *testing.B) {
 		_ = proj.Mul(view)
 	}
 }
+
+func BenchmarkQuatMul(b *testing.B) {
+	q1 := QuatFromAxisAngle(V3(1, 2, 3), 0.5)
+	q2 := QuatFromAxisAngle(V3(0, 1, 0), 1.2)
+
+	for b.Loop() {
+		_ = q1.Mul(q2)
+	}
+}
+
+func BenchmarkQuatSlerp(b *testing.B) {
+	q1 := QuatIdentity()
+	q2 := QuatFromAxisAngle(V3(0, 1, 0), 1.2)
+
+	for b.Loop() {
+		_ = q1.Slerp(q2, 0.3)
+	}
+}
+
+func BenchmarkMat4NormalMatrix(b *testing.B) {
+	m := Translate(V3(1, 2, 3)).Mul(RotateY(0.5)).Mul(Scale(V3(1, 1, 3)))
+
+	for b.Loop() {
+		_ = m.NormalMatrix()
+	}
+}
+
+func BenchmarkMat3Mul(b *testing.B) {
+	m1 := Mat3FromMat4(RotateX(0.3))
+	m2 := Mat3FromMat4(RotateY(0.5))
+
+	for b.Loop() {
+		_ = m1.Mul(m2)
+	}
+}
+
+func BenchmarkMat3Inverse(b *testing.B) {
+	m := Mat3FromMat4(RotateY(0.5).Mul(Scale(V3(2, 2, 2))))
+
+	for b.Loop() {
+		_ = m.Inverse()
+	}
+}
@@ -0,0 +1,149 @@
+package math3d
+
+// Mat3 is a 3x3 matrix stored in column-major order.
+// Used for rotations and normal transforms where translation is irrelevant.
+//
+// Memory layout (indices):
+// | 0  3  6 |
+// | 1  4  7 |
+// | 2  5  8 |
+type Mat3 [9]float64
+
+// Identity3 returns the 3x3 identity matrix.
+func Identity3() Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// Mat3FromMat4 extracts the upper-left 3x3 of a Mat4.
+func Mat3FromMat4(m Mat4) Mat3 {
+	return Mat3{
+		m[0], m[1], m[2],
+		m[4], m[5], m[6],
+		m[8], m[9], m[10],
+	}
+}
+
+// Mat4 embeds the matrix in the upper-left of a Mat4 with no translation.
+func (m Mat3) Mat4() Mat4 {
+	return Mat4{
+		m[0], m[1], m[2], 0,
+		m[3], m[4], m[5], 0,
+		m[6], m[7], m[8], 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Mul multiplies two matrices: a * b.
+//
+//nolint:st1016 // a*b naming convention is clearer for matrix multiplication
+func (a Mat3) Mul(b Mat3) Mat3 {
+	var m Mat3
+	for col := range 3 {
+		for row := range 3 {
+			var sum float64
+			for k := range 3 {
+				sum += a[row+k*3] * b[k+col*3]
+			}
+			m[row+col*3] = sum
+		}
+	}
+	return m
+}
+
+// MulVec3 transforms a Vec3.
+func (m Mat3) MulVec3(v Vec3) Vec3 {
+	return Vec3{
+		m[0]*v.X + m[3]*v.Y + m[6]*v.Z,
+		m[1]*v.X + m[4]*v.Y + m[7]*v.Z,
+		m[2]*v.X + m[5]*v.Y + m[8]*v.Z,
+	}
+}
+
+// Transpose returns the transposed matrix.
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{
+		m[0], m[3], m[6],
+		m[1], m[4], m[7],
+		m[2], m[5], m[8],
+	}
+}
+
+// Determinant returns the determinant of the matrix.
+func (m Mat3) Determinant() float64 {
+	return m.col(0).Dot(m.col(1).Cross(m.col(2)))
+}
+
+// Inverse returns the inverse of the matrix.
+// Returns identity if the matrix is singular (det=0).
+func (m Mat3) Inverse() Mat3 {
+	cof, det := m.cofactor()
+	if det == 0 {
+		return Identity3()
+	}
+	return cof.Transpose().scale(1.0 / det)
+}
+
+// InverseTranspose returns the transpose of the inverse, which is the
+// correct matrix for transforming normals.
+// Returns identity if the matrix is singular (det=0).
+func (m Mat3) InverseTranspose() Mat3 {
+	cof, det := m.cofactor()
+	if det == 0 {
+		return Identity3()
+	}
+	return cof.scale(1.0 / det)
+}
+
+// IsOrthonormal reports whether the columns are unit length and mutually
+// perpendicular to within tolerance, as for a rotation or a mirror. Such a
+// matrix keeps unit vectors unit.
+func (m Mat3) IsOrthonormal(tolerance float64) bool {
+	c0, c1, c2 := m.col(0), m.col(1), m.col(2)
+	for _, d := range []float64{c0.LenSq() - 1, c1.LenSq() - 1, c2.LenSq() - 1, c0.Dot(c1), c1.Dot(c2), c2.Dot(c0)} {
+		if d > tolerance || d < -tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns the element at (row, col).
+func (m Mat3) Get(row, col int) float64 {
+	return m[row+col*3]
+}
+
+// Set sets the element at (row, col).
+func (m *Mat3) Set(row, col int, val float64) {
+	m[row+col*3] = val
+}
+
+// col returns column i as a vector.
+func (m Mat3) col(i int) Vec3 {
+	return Vec3{m[i*3], m[i*3+1], m[i*3+2]}
+}
+
+// cofactor returns the cofactor matrix and the determinant.
+// Its columns are c1×c2, c2×c0, c0×c1 for input columns c0, c1, c2.
+func (m Mat3) cofactor() (Mat3, float64) {
+	c0, c1, c2 := m.col(0), m.col(1), m.col(2)
+	x := c1.Cross(c2)
+	y := c2.Cross(c0)
+	z := c0.Cross(c1)
+	return Mat3{
+		x.X, x.Y, x.Z,
+		y.X, y.Y, y.Z,
+		z.X, z.Y, z.Z,
+	}, c0.Dot(x)
+}
+
+// scale multiplies every element by s.
+func (m Mat3) scale(s float64) Mat3 {
+	for i := range m {
+		m[i] *= s
+	}
+	return m
+}
@@ -0,0 +1,117 @@
+package math3d
+
+import (
+	"math"
+	"testing"
+)
+
+func mat3ApproxEqual(a, b Mat3, eps float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMat3FromMat4RoundTrip(t *testing.T) {
+	rot := RotateY(0.5).Mul(RotateX(0.25))
+	m := Mat3FromMat4(rot.Mul(Translate(V3(1, 2, 3))))
+
+	if got := m.Mat4(); !mat4ApproxEqual(got, rot, 1e-12) {
+		t.Errorf("Mat3FromMat4().Mat4() = %v, want %v", got, rot)
+	}
+}
+
+func TestMat3MulMatchesMat4(t *testing.T) {
+	a := RotateX(0.3).Mul(Scale(V3(1, 2, 3)))
+	b := RotateZ(-1.2)
+
+	want := Mat3FromMat4(a.Mul(b))
+	got := Mat3FromMat4(a).Mul(Mat3FromMat4(b))
+
+	if !mat3ApproxEqual(got, want, 1e-12) {
+		t.Errorf("Mat3.Mul = %v, want %v", got, want)
+	}
+}
+
+func TestMat3MulVec3(t *testing.T) {
+	m4 := RotateY(0.8).Mul(Scale(V3(2, 1, 0.5)))
+	m3 := Mat3FromMat4(m4)
+	v := V3(1, -2, 3)
+
+	want := m4.MulVec3Dir(v)
+	got := m3.MulVec3(v)
+
+	if got.Sub(want).Len() > 1e-12 {
+		t.Errorf("MulVec3 = %v, want %v", got, want)
+	}
+}
+
+func TestMat3Transpose(t *testing.T) {
+	m := Mat3{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	tr := m.Transpose()
+
+	for row := range 3 {
+		for col := range 3 {
+			if tr.Get(row, col) != m.Get(col, row) {
+				t.Errorf("Transpose()[%d,%d] = %v, want %v", row, col, tr.Get(row, col), m.Get(col, row))
+			}
+		}
+	}
+}
+
+func TestMat3Inverse(t *testing.T) {
+	m := Mat3FromMat4(RotateX(0.4).Mul(Scale(V3(2, 3, 0.5))))
+	got := m.Mul(m.Inverse())
+
+	if !mat3ApproxEqual(got, Identity3(), 1e-12) {
+		t.Errorf("m * m.Inverse() = %v, want identity", got)
+	}
+}
+
+func TestMat3InverseSingular(t *testing.T) {
+	m := Mat3{1, 0, 0, 0, 0, 0, 0, 0, 1}
+	if got := m.Inverse(); got != Identity3() {
+		t.Errorf("singular Inverse() = %v, want identity", got)
+	}
+}
+
+func TestMat3InverseTranspose(t *testing.T) {
+	m := Mat3FromMat4(RotateZ(0.9).Mul(Scale(V3(1, 1, 3))))
+
+	want := m.Inverse().Transpose()
+	got := m.InverseTranspose()
+
+	if !mat3ApproxEqual(got, want, 1e-12) {
+		t.Errorf("InverseTranspose() = %v, want %v", got, want)
+	}
+}
+
+func TestMat3Determinant(t *testing.T) {
+	m := Mat3FromMat4(Scale(V3(2, 3, 4)))
+	if det := m.Determinant(); math.Abs(det-24) > 1e-12 {
+		t.Errorf("Determinant() = %v, want 24", det)
+	}
+}
+
+func TestMat3IsOrthonormal(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Mat4
+		want bool
+	}{
+		{"identity", Identity(), true},
+		{"rotation", RotateX(0.4).Mul(RotateY(-1.3)).Mul(RotateZ(2.2)), true},
+		{"mirror", Scale(V3(-1, 1, 1)), true},
+		{"translated rotation", Translate(V3(5, 6, 7)).Mul(RotateY(0.8)), true},
+		{"uniform scale", ScaleUniform(2), false},
+		{"non-uniform scale", RotateY(0.8).Mul(Scale(V3(1, 1, 1.01))), false},
+		{"shear", Mat4{1, 0, 0, 0, 0.1, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}, false},
+	}
+	for _, tt := range tests {
+		if got := Mat3FromMat4(tt.m).IsOrthonormal(1e-9); got != tt.want {
+			t.Errorf("%s: IsOrthonormal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -246,6 +246,47 @@ func (m Mat4) Inverse() Mat4 {
 	return inv
 }
 
+// NormalMatrix returns the inverse-transpose of the upper-left 3x3, embedded in a Mat4
+// with no translation. Use it with MulVec3Dir to transform normals so they stay
+// perpendicular to surfaces under non-uniform scale.
+// Falls back to the plain upper-left 3x3 if that block is singular.
+func (m Mat4) NormalMatrix() Mat4 {
+	upper := Mat3FromMat4(m)
+	if upper.Determinant() == 0 {
+		return upper.Mat4()
+	}
+	return upper.InverseTranspose().Mat4()
+}
+
+// Decompose splits an affine transform into translation, rotation, and scale
+// such that Translate(t).Mul(r.ToMat4()).Mul(Scale(s)) reproduces it.
+// Shear is not represented. A mirrored transform (negative determinant) is
+// reported as a negative X scale so the rotation stays a proper rotation.
+func (m Mat4) Decompose() (translation Vec3, rotation Quat, scale Vec3) {
+	translation = m.Translation()
+
+	upper := Mat3FromMat4(m)
+	scale = Vec3{upper.col(0).Len(), upper.col(1).Len(), upper.col(2).Len()}
+	if upper.Determinant() < 0 {
+		scale.X = -scale.X
+	}
+
+	if scale.X == 0 || scale.Y == 0 || scale.Z == 0 {
+		return translation, QuatIdentity(), scale
+	}
+
+	// Divide each basis column by its scale to leave the pure rotation
+	var rot Mat3
+	for col, s := range [3]float64{scale.X, scale.Y, scale.Z} {
+		for row := range 3 {
+			rot[row+col*3] = upper[row+col*3] / s
+		}
+	}
+	rotation = QuatFromMat3(rot)
+
+	return translation, rotation, scale
+}
+
 // Get returns the element at (row, col).
 func (m Mat4) Get(row, col int) float64 {
 	return m[row+col*4]
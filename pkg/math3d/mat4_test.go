@@ -0,0 +1,90 @@
+package math3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalMatrixRotationUnchanged(t *testing.T) {
+	// For a pure rotation the inverse-transpose is the rotation itself
+	m := RotateY(0.7).Mul(RotateX(-0.3)).Mul(Translate(V3(4, 5, 6)))
+	got := m.NormalMatrix()
+	want := RotateY(0.7).Mul(RotateX(-0.3))
+
+	if !mat4ApproxEqual(got, want, 1e-9) {
+		t.Errorf("NormalMatrix() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalMatrixNonUniformScale(t *testing.T) {
+	m := Scale(V3(1, 1, 3))
+	n := m.NormalMatrix()
+
+	// Plane x + z = 0 has normal (1, 0, 1) and contains direction (1, 0, -1)
+	normal := n.MulVec3Dir(V3(1, 0, 1))
+	tangent := m.MulVec3Dir(V3(1, 0, -1))
+
+	if d := normal.Dot(tangent); math.Abs(d) > 1e-9 {
+		t.Errorf("transformed normal not perpendicular to surface: dot = %v", d)
+	}
+}
+
+func TestNormalMatrixSingular(t *testing.T) {
+	m := Scale(V3(1, 0, 1))
+	got := m.NormalMatrix()
+
+	if !mat4ApproxEqual(got, m, 1e-9) {
+		t.Errorf("singular NormalMatrix() = %v, want upper-left 3x3 %v", got, m)
+	}
+}
+
+func TestMat4DecomposeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		t    Vec3
+		r    Quat
+		s    Vec3
+	}{
+		{"identity", Zero3(), QuatIdentity(), V3(1, 1, 1)},
+		{"translate only", V3(1, -2, 3), QuatIdentity(), V3(1, 1, 1)},
+		{"rotate and scale", V3(0.5, 0, -4), QuatFromAxisAngle(V3(1, 1, 0), 2.5), V3(2, 0.5, 3)},
+		{"half turn", Zero3(), QuatFromAxisAngle(V3(0, 0, 1), math.Pi), V3(1, 2, 1)},
+		{"mirrored", V3(1, 1, 1), QuatFromAxisAngle(Up(), 0.7), V3(-2, 1, 1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := Translate(tc.t).Mul(tc.r.ToMat4()).Mul(Scale(tc.s))
+			tr, rot, sc := m.Decompose()
+
+			if tr.Sub(tc.t).Len() > 1e-9 {
+				t.Errorf("translation = %v, want %v", tr, tc.t)
+			}
+			if sc.Sub(tc.s).Len() > 1e-9 {
+				t.Errorf("scale = %v, want %v", sc, tc.s)
+			}
+			if math.Abs(math.Abs(rot.Dot(tc.r))-1) > 1e-9 {
+				t.Errorf("rotation = %v, want %v", rot, tc.r)
+			}
+
+			recomposed := Translate(tr).Mul(rot.ToMat4()).Mul(Scale(sc))
+			if !mat4ApproxEqual(recomposed, m, 1e-9) {
+				t.Errorf("recomposed = %v, want %v", recomposed, m)
+			}
+		})
+	}
+}
+
+func TestMat4DecomposeMirrorFlag(t *testing.T) {
+	m := Scale(V3(1, 1, -1)) // mirror in Z
+	_, rot, sc := m.Decompose()
+
+	if sc.X*sc.Y*sc.Z >= 0 {
+		t.Errorf("mirrored matrix should report negative scale product, got %v", sc)
+	}
+
+	recomposed := rot.ToMat4().Mul(Scale(sc))
+	if !mat4ApproxEqual(recomposed, m, 1e-9) {
+		t.Errorf("recomposed = %v, want %v", recomposed, m)
+	}
+}
@@ -0,0 +1,184 @@
+package math3d
+
+import "math"
+
+// Quat is a rotation quaternion with vector part (X, Y, Z) and scalar part W.
+// This matches the GLTF component order (x, y, z, w).
+type Quat struct {
+	X, Y, Z, W float64
+}
+
+// QuatIdentity returns the identity rotation.
+func QuatIdentity() Quat {
+	return Quat{0, 0, 0, 1}
+}
+
+// QuatFromAxisAngle creates a quaternion rotating by angle (radians) around axis.
+func QuatFromAxisAngle(axis Vec3, angle float64) Quat {
+	axis = axis.Normalize()
+	s, c := math.Sincos(angle / 2)
+	return Quat{axis.X * s, axis.Y * s, axis.Z * s, c}
+}
+
+// QuatFromEuler creates a quaternion from Euler angles (radians).
+// The result matches RotateX(pitch).Mul(RotateY(yaw)).Mul(RotateZ(roll)),
+// which is the order the viewer composes its rotation in.
+func QuatFromEuler(pitch, yaw, roll float64) Quat {
+	qx := QuatFromAxisAngle(Right(), pitch)
+	qy := QuatFromAxisAngle(Up(), yaw)
+	qz := QuatFromAxisAngle(V3(0, 0, 1), roll)
+	return qx.Mul(qy).Mul(qz)
+}
+
+// QuatFromMat3 converts a pure rotation matrix to a quaternion.
+// The matrix must be orthonormal with determinant +1.
+func QuatFromMat3(m Mat3) Quat {
+	m00, m11, m22 := m.Get(0, 0), m.Get(1, 1), m.Get(2, 2)
+	trace := m00 + m11 + m22
+
+	// Pick the largest diagonal term to keep the square root well-conditioned
+	var q Quat
+	switch {
+	case trace > 0:
+		s := math.Sqrt(trace+1) * 2
+		q = Quat{
+			(m.Get(2, 1) - m.Get(1, 2)) / s,
+			(m.Get(0, 2) - m.Get(2, 0)) / s,
+			(m.Get(1, 0) - m.Get(0, 1)) / s,
+			s / 4,
+		}
+	case m00 > m11 && m00 > m22:
+		s := math.Sqrt(1+m00-m11-m22) * 2
+		q = Quat{
+			s / 4,
+			(m.Get(0, 1) + m.Get(1, 0)) / s,
+			(m.Get(0, 2) + m.Get(2, 0)) / s,
+			(m.Get(2, 1) - m.Get(1, 2)) / s,
+		}
+	case m11 > m22:
+		s := math.Sqrt(1+m11-m00-m22) * 2
+		q = Quat{
+			(m.Get(0, 1) + m.Get(1, 0)) / s,
+			s / 4,
+			(m.Get(1, 2) + m.Get(2, 1)) / s,
+			(m.Get(0, 2) - m.Get(2, 0)) / s,
+		}
+	default:
+		s := math.Sqrt(1+m22-m00-m11) * 2
+		q = Quat{
+			(m.Get(0, 2) + m.Get(2, 0)) / s,
+			(m.Get(1, 2) + m.Get(2, 1)) / s,
+			s / 4,
+			(m.Get(1, 0) - m.Get(0, 1)) / s,
+		}
+	}
+	return q.Normalize()
+}
+
+// Mul returns the Hamilton product a * b (apply b first, then a).
+//
+//nolint:st1016 // a*b naming convention is clearer for quaternion multiplication
+func (a Quat) Mul(b Quat) Quat {
+	return Quat{
+		a.W*b.X + a.X*b.W + a.Y*b.Z - a.Z*b.Y,
+		a.W*b.Y - a.X*b.Z + a.Y*b.W + a.Z*b.X,
+		a.W*b.Z + a.X*b.Y - a.Y*b.X + a.Z*b.W,
+		a.W*b.W - a.X*b.X - a.Y*b.Y - a.Z*b.Z,
+	}
+}
+
+// Dot returns the 4D dot product of two quaternions.
+//
+//nolint:st1016 // a·b naming convention is clearer for quaternion operations
+func (a Quat) Dot(b Quat) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z + a.W*b.W
+}
+
+// Len returns the magnitude of the quaternion.
+func (q Quat) Len() float64 {
+	return math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z + q.W*q.W)
+}
+
+// Normalize returns the unit quaternion.
+// Returns identity if the quaternion has zero length.
+func (q Quat) Normalize() Quat {
+	l := q.Len()
+	if l == 0 {
+		return QuatIdentity()
+	}
+	return Quat{q.X / l, q.Y / l, q.Z / l, q.W / l}
+}
+
+// Conjugate returns the conjugate (the inverse for unit quaternions).
+func (q Quat) Conjugate() Quat {
+	return Quat{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Rotate rotates a vector by the quaternion.
+func (q Quat) Rotate(v Vec3) Vec3 {
+	// v' = v + 2w(u × v) + 2u × (u × v), with u = (X, Y, Z)
+	u := Vec3{q.X, q.Y, q.Z}
+	t := u.Cross(v).Scale(2)
+	return v.Add(t.Scale(q.W)).Add(u.Cross(t))
+}
+
+// ToMat4 converts the quaternion to a rotation matrix.
+func (q Quat) ToMat4() Mat4 {
+	return QuatToMat4(q.X, q.Y, q.Z, q.W)
+}
+
+// ToEuler returns the Euler angles (radians) such that
+// QuatFromEuler(pitch, yaw, roll) reproduces the same rotation.
+// At yaw = ±90° (gimbal lock) roll is reported as 0.
+func (q Quat) ToEuler() (pitch, yaw, roll float64) {
+	m := q.ToMat4()
+
+	// Row 0, column 2 holds sin(yaw) for the X*Y*Z composition
+	sy := math.Max(-1, math.Min(1, m[8]))
+	yaw = math.Asin(sy)
+
+	if math.Abs(sy) < 1-1e-9 {
+		pitch = math.Atan2(-m[9], m[10])
+		roll = math.Atan2(-m[4], m[0])
+	} else {
+		pitch = math.Atan2(m[6], m[5])
+		roll = 0
+	}
+	return pitch, yaw, roll
+}
+
+// Slerp returns the spherical linear interpolation between a and b by t.
+// Always takes the shortest path around the hypersphere.
+//
+//nolint:st1016 // a,b naming convention is clearer for interpolation
+func (a Quat) Slerp(b Quat, t float64) Quat {
+	cosTheta := a.Dot(b)
+
+	// Flip to take the shorter arc
+	if cosTheta < 0 {
+		b = Quat{-b.X, -b.Y, -b.Z, -b.W}
+		cosTheta = -cosTheta
+	}
+
+	// Nearly parallel: fall back to normalized lerp to avoid dividing by ~0
+	if cosTheta > 0.9995 {
+		return Quat{
+			a.X + (b.X-a.X)*t,
+			a.Y + (b.Y-a.Y)*t,
+			a.Z + (b.Z-a.Z)*t,
+			a.W + (b.W-a.W)*t,
+		}.Normalize()
+	}
+
+	theta := math.Acos(cosTheta)
+	sinTheta := math.Sin(theta)
+	wa := math.Sin((1-t)*theta) / sinTheta
+	wb := math.Sin(t*theta) / sinTheta
+
+	return Quat{
+		a.X*wa + b.X*wb,
+		a.Y*wa + b.Y*wb,
+		a.Z*wa + b.Z*wb,
+		a.W*wa + b.W*wb,
+	}
+}
@@ -0,0 +1,108 @@
+package math3d
+
+import (
+	"math"
+	"testing"
+)
+
+func mat4ApproxEqual(a, b Mat4, eps float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuatFromEulerMatchesMatrices(t *testing.T) {
+	pitch, yaw, roll := 0.3, -1.1, 0.7
+
+	want := RotateX(pitch).Mul(RotateY(yaw)).Mul(RotateZ(roll))
+	got := QuatFromEuler(pitch, yaw, roll).ToMat4()
+
+	if !mat4ApproxEqual(got, want, 1e-9) {
+		t.Errorf("QuatFromEuler().ToMat4() = %v, want %v", got, want)
+	}
+}
+
+func TestQuatMulMatchesMat4Mul(t *testing.T) {
+	a := QuatFromAxisAngle(V3(1, 2, 3), 0.8)
+	b := QuatFromAxisAngle(V3(-1, 0, 1), 1.9)
+
+	want := a.ToMat4().Mul(b.ToMat4())
+	got := a.Mul(b).ToMat4()
+
+	if !mat4ApproxEqual(got, want, 1e-9) {
+		t.Errorf("a.Mul(b).ToMat4() = %v, want %v", got, want)
+	}
+}
+
+func TestQuatToEulerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name             string
+		pitch, yaw, roll float64
+	}{
+		{"zero", 0, 0, 0},
+		{"pitch only", 0.5, 0, 0},
+		{"mixed", 0.3, -1.1, 0.7},
+		{"negative", -2.0, 0.4, -1.5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, y, r := QuatFromEuler(tc.pitch, tc.yaw, tc.roll).ToEuler()
+			if math.Abs(p-tc.pitch) > 1e-9 || math.Abs(y-tc.yaw) > 1e-9 || math.Abs(r-tc.roll) > 1e-9 {
+				t.Errorf("ToEuler() = (%v, %v, %v), want (%v, %v, %v)", p, y, r, tc.pitch, tc.yaw, tc.roll)
+			}
+		})
+	}
+}
+
+func TestQuatRotate(t *testing.T) {
+	q := QuatFromAxisAngle(Up(), math.Pi/2)
+	v := q.Rotate(V3(1, 0, 0))
+
+	// Same convention as RotateY: +X maps to -Z
+	if math.Abs(v.X) > 1e-9 || math.Abs(v.Y) > 1e-9 || math.Abs(v.Z+1) > 1e-9 {
+		t.Errorf("Rotate((1,0,0)) = %v, want (0,0,-1)", v)
+	}
+}
+
+func TestQuatSlerp(t *testing.T) {
+	a := QuatIdentity()
+	b := QuatFromAxisAngle(Up(), math.Pi/2)
+
+	if got := a.Slerp(b, 0); math.Abs(got.Dot(a)-1) > 1e-9 {
+		t.Errorf("Slerp(t=0) = %v, want %v", got, a)
+	}
+	if got := a.Slerp(b, 1); math.Abs(got.Dot(b)-1) > 1e-9 {
+		t.Errorf("Slerp(t=1) = %v, want %v", got, b)
+	}
+
+	mid := a.Slerp(b, 0.5)
+	want := QuatFromAxisAngle(Up(), math.Pi/4)
+	if math.Abs(mid.Dot(want)-1) > 1e-9 {
+		t.Errorf("Slerp(t=0.5) = %v, want %v", mid, want)
+	}
+	if math.Abs(mid.Len()-1) > 1e-9 {
+		t.Errorf("Slerp result should be unit length, got %v", mid.Len())
+	}
+}
+
+func TestQuatSlerpShortestPath(t *testing.T) {
+	a := QuatIdentity()
+	b := QuatFromAxisAngle(Up(), 0.2)
+	negB := Quat{-b.X, -b.Y, -b.Z, -b.W} // same rotation, opposite hemisphere
+
+	got := a.Slerp(negB, 0.5).ToMat4()
+	want := a.Slerp(b, 0.5).ToMat4()
+	if !mat4ApproxEqual(got, want, 1e-9) {
+		t.Errorf("Slerp should take the shortest arc: got %v, want %v", got, want)
+	}
+}
+
+func TestQuatNormalizeZero(t *testing.T) {
+	if got := (Quat{}).Normalize(); got != QuatIdentity() {
+		t.Errorf("Normalize of zero quaternion = %v, want identity", got)
+	}
+}
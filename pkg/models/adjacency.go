@@ -0,0 +1,100 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// posEdge is an undirected edge between position clusters, lower index first.
+type posEdge struct{ a, b int }
+
+// edgeKey returns the posEdge between clusters a and b.
+func edgeKey(a, b int) posEdge {
+	return posEdge{min(a, b), max(a, b)}
+}
+
+// adjacency describes how a mesh's faces connect, treating vertices that
+// share a position as one, since loaders split vertices along UV and
+// normal seams that geometry edits should not tear open.
+type adjacency struct {
+	cluster   []int             // Vertex index to position cluster
+	pos       []math3d.Vec3     // Position of each cluster
+	neighbors [][]int           // Clusters sharing an edge with each cluster
+	opposite  map[posEdge][]int // Per edge, the far corner of each face using it
+}
+
+// buildAdjacency computes the mesh's position adjacency.
+func (m *Mesh) buildAdjacency() *adjacency {
+	cluster, pos := m.positionClusters()
+	adj := &adjacency{
+		cluster:   cluster,
+		pos:       pos,
+		neighbors: make([][]int, len(pos)),
+		opposite:  make(map[posEdge][]int),
+	}
+	for _, f := range m.Faces {
+		c := [3]int{cluster[f.V[0]], cluster[f.V[1]], cluster[f.V[2]]}
+		for i := range 3 {
+			a, b, o := c[i], c[(i+1)%3], c[(i+2)%3]
+			adj.opposite[edgeKey(a, b)] = append(adj.opposite[edgeKey(a, b)], o)
+			if !slices.Contains(adj.neighbors[a], b) {
+				adj.neighbors[a] = append(adj.neighbors[a], b)
+				adj.neighbors[b] = append(adj.neighbors[b], a)
+			}
+		}
+	}
+	return adj
+}
+
+// isBoundary reports whether the edge between clusters a and b has only one face.
+func (adj *adjacency) isBoundary(a, b int) bool {
+	return len(adj.opposite[edgeKey(a, b)]) == 1
+}
+
+// boundaryNeighbors returns the clusters joined to c by boundary edges.
+func (adj *adjacency) boundaryNeighbors(c int) []int {
+	var out []int
+	for _, n := range adj.neighbors[c] {
+		if adj.isBoundary(c, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// positionClusters groups vertices with identical positions, returning each
+// vertex's group and the groups' positions.
+func (m *Mesh) positionClusters() (cluster []int, pos []math3d.Vec3) {
+	ids := make(map[math3d.Vec3]int)
+	cluster = make([]int, len(m.Vertices))
+	for i, v := range m.Vertices {
+		id, ok := ids[v.Position]
+		if !ok {
+			id = len(pos)
+			ids[v.Position] = id
+			pos = append(pos, v.Position)
+		}
+		cluster[i] = id
+	}
+	return cluster, pos
+}
+
+// smoothClusterNormals sets every vertex normal to the area-weighted
+// average of the face normals around its position, so split vertices
+// shade as one. count is the number of clusters.
+func (m *Mesh) smoothClusterNormals(cluster []int, count int) {
+	normals := make([]math3d.Vec3, count)
+	for _, f := range m.Faces {
+		p0 := m.Vertices[f.V[0]].Position
+		n := m.Vertices[f.V[1]].Position.Sub(p0).Cross(m.Vertices[f.V[2]].Position.Sub(p0))
+		for _, vi := range f.V {
+			normals[cluster[vi]] = normals[cluster[vi]].Add(n)
+		}
+	}
+	for i := range m.Vertices {
+		if n := normals[cluster[i]]; n.LenSq() > 0 {
+			m.Vertices[i].Normal = n.Normalize()
+		}
+	}
+}
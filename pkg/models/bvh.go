@@ -0,0 +1,201 @@
+package models
+
+import (
+	"math"
+	"slices"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// bvhLeafSize is the most faces a leaf holds before it is split.
+const bvhLeafSize = 4
+
+// Culler reports whether an axis-aligned box may be visible.
+// render.Frustum implements it.
+type Culler interface {
+	IntersectsBox(min, max math3d.Vec3) bool
+}
+
+// BVH is a bounding volume hierarchy over a mesh's faces, for raycasts and
+// visibility queries that skip most of the mesh. It captures the mesh's
+// vertex positions and faces at build time; rebuild it after editing them.
+type BVH struct {
+	mesh  *Mesh
+	nodes []bvhNode
+	faces []int // Face indices, ordered so each leaf owns a contiguous run
+}
+
+// bvhNode is a box around a run of faces (a leaf) or two child nodes.
+type bvhNode struct {
+	min, max math3d.Vec3
+	left     int // First of two adjacent children; unused for leaves
+	start    int // First entry in BVH.faces for leaves
+	count    int // Number of faces for leaves, 0 for inner nodes
+}
+
+// NewBVH builds a BVH over mesh's faces, splitting each node at the
+// median face centroid along its longest axis.
+func NewBVH(mesh *Mesh) *BVH {
+	b := &BVH{
+		mesh:  mesh,
+		faces: make([]int, len(mesh.Faces)),
+	}
+	if len(mesh.Faces) == 0 {
+		return b
+	}
+
+	centroids := make([]math3d.Vec3, len(mesh.Faces))
+	for i, f := range mesh.Faces {
+		b.faces[i] = i
+		centroids[i] = mesh.Vertices[f.V[0]].Position.
+			Add(mesh.Vertices[f.V[1]].Position).
+			Add(mesh.Vertices[f.V[2]].Position).
+			Scale(1.0 / 3)
+	}
+
+	// Sized for a full tree of full leaves; append grows it otherwise
+	b.nodes = make([]bvhNode, 1, 2*len(mesh.Faces)/bvhLeafSize+1)
+	b.build(0, 0, len(b.faces), centroids)
+	return b
+}
+
+// build fills node idx with faces[start:end], splitting it if too large.
+func (b *BVH) build(idx, start, end int, centroids []math3d.Vec3) {
+	node := bvhNode{start: start, count: end - start}
+	node.min, node.max = b.faceBounds(b.faces[start])
+	cmin, cmax := centroids[b.faces[start]], centroids[b.faces[start]]
+	for _, fi := range b.faces[start+1 : end] {
+		fmin, fmax := b.faceBounds(fi)
+		node.min = node.min.Min(fmin)
+		node.max = node.max.Max(fmax)
+		cmin = cmin.Min(centroids[fi])
+		cmax = cmax.Max(centroids[fi])
+	}
+
+	// Faces sharing one centroid can't be separated, so leave them in a leaf
+	if end-start <= bvhLeafSize || cmin == cmax {
+		b.nodes[idx] = node
+		return
+	}
+
+	ext := cmax.Sub(cmin)
+	axis := func(v math3d.Vec3) float64 { return v.X }
+	if ext.Y > ext.X && ext.Y >= ext.Z {
+		axis = func(v math3d.Vec3) float64 { return v.Y }
+	} else if ext.Z > ext.X && ext.Z > ext.Y {
+		axis = func(v math3d.Vec3) float64 { return v.Z }
+	}
+	run := b.faces[start:end]
+	slices.SortFunc(run, func(i, j int) int {
+		ci, cj := axis(centroids[i]), axis(centroids[j])
+		switch {
+		case ci < cj:
+			return -1
+		case ci > cj:
+			return 1
+		}
+		return i - j
+	})
+
+	mid := start + (end-start)/2
+	node.left = len(b.nodes)
+	node.count = 0
+	b.nodes[idx] = node
+	b.nodes = append(b.nodes, bvhNode{}, bvhNode{})
+	b.build(node.left, start, mid, centroids)
+	b.build(node.left+1, mid, end, centroids)
+}
+
+// faceBounds returns the bounding box of face i.
+func (b *BVH) faceBounds(i int) (min, max math3d.Vec3) {
+	f := b.mesh.Faces[i]
+	p0 := b.mesh.Vertices[f.V[0]].Position
+	p1 := b.mesh.Vertices[f.V[1]].Position
+	p2 := b.mesh.Vertices[f.V[2]].Position
+	return p0.Min(p1).Min(p2), p0.Max(p1).Max(p2)
+}
+
+// Raycast finds the nearest face hit by the ray, with the same results
+// as Mesh.Raycast but testing only faces in boxes the ray passes through.
+func (b *BVH) Raycast(origin, dir math3d.Vec3) (hit bool, t float64, faceIndex int, bary math3d.Vec3) {
+	hit, t, faceIndex, bary, _ = b.raycast(origin, dir)
+	return hit, t, faceIndex, bary
+}
+
+// raycast is Raycast that also returns how many triangles it tested.
+func (b *BVH) raycast(origin, dir math3d.Vec3) (hit bool, t float64, faceIndex int, bary math3d.Vec3, tested int) {
+	faceIndex = -1
+	if len(b.nodes) == 0 {
+		return false, 0, -1, math3d.Vec3{}, 0
+	}
+
+	t = math.Inf(1)
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if _, ok := rayBox(origin, dir, node.min, node.max, t); !ok {
+			continue
+		}
+
+		if node.count > 0 {
+			for _, fi := range b.faces[node.start : node.start+node.count] {
+				f := b.mesh.Faces[fi]
+				tested++
+				ht, u, v, ok := rayTriangle(origin, dir,
+					b.mesh.Vertices[f.V[0]].Position, b.mesh.Vertices[f.V[1]].Position, b.mesh.Vertices[f.V[2]].Position)
+				// Ties go to the lower index, matching Mesh.Raycast
+				if ok && (ht < t || (ht == t && fi < faceIndex)) {
+					hit, t, faceIndex = true, ht, fi
+					bary = math3d.V3(1-u-v, u, v)
+				}
+			}
+			continue
+		}
+
+		// Visit the nearer child first so the farther one is often pruned
+		l, r := node.left, node.left+1
+		tl, okl := rayBox(origin, dir, b.nodes[l].min, b.nodes[l].max, t)
+		tr, okr := rayBox(origin, dir, b.nodes[r].min, b.nodes[r].max, t)
+		switch {
+		case okl && okr && tl <= tr:
+			stack = append(stack, r, l)
+		case okl && okr:
+			stack = append(stack, l, r)
+		case okl:
+			stack = append(stack, l)
+		case okr:
+			stack = append(stack, r)
+		}
+	}
+
+	if !hit {
+		return false, 0, -1, math3d.Vec3{}, tested
+	}
+	return hit, t, faceIndex, bary, tested
+}
+
+// FrustumCull returns the indices of faces in boxes that c may see, in
+// no particular order. The culler must be in the mesh's own space, e.g.
+// a frustum built from the view-projection times the model matrix.
+func (b *BVH) FrustumCull(c Culler) []int {
+	var visible []int
+	if len(b.nodes) == 0 {
+		return visible
+	}
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		node := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !c.IntersectsBox(node.min, node.max) {
+			continue
+		}
+		if node.count > 0 {
+			visible = append(visible, b.faces[node.start:node.start+node.count]...)
+			continue
+		}
+		stack = append(stack, node.left, node.left+1)
+	}
+	return visible
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// randomTriangleMesh returns n small triangles scattered through a 20-unit cube.
+func randomTriangleMesh(rng *rand.Rand, n int) *Mesh {
+	randVec := func(scale float64) math3d.Vec3 {
+		return math3d.V3(rng.Float64()-0.5, rng.Float64()-0.5, rng.Float64()-0.5).Scale(scale)
+	}
+
+	mesh := NewMesh("random")
+	for i := range n {
+		center := randVec(20)
+		for range 3 {
+			mesh.Vertices = append(mesh.Vertices, MeshVertex{Position: center.Add(randVec(2))})
+		}
+		mesh.Faces = append(mesh.Faces, Face{V: [3]int{3 * i, 3*i + 1, 3*i + 2}})
+	}
+	mesh.CalculateBounds()
+	return mesh
+}
+
+func TestBVHRaycastMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	mesh := randomTriangleMesh(rng, 2000)
+	bvh := NewBVH(mesh)
+
+	const rays = 500
+	hits, tested := 0, 0
+	for range rays {
+		origin := math3d.V3(rng.Float64()-0.5, rng.Float64()-0.5, rng.Float64()-0.5).Scale(40)
+		target := math3d.V3(rng.Float64()-0.5, rng.Float64()-0.5, rng.Float64()-0.5).Scale(10)
+		dir := target.Sub(origin).Normalize()
+
+		wantHit, wantT, wantFace, wantBary := mesh.Raycast(origin, dir)
+		hit, tHit, face, bary, n := bvh.raycast(origin, dir)
+		tested += n
+		if hit != wantHit || face != wantFace || math.Abs(tHit-wantT) > 1e-9 || bary.Sub(wantBary).Len() > 1e-9 {
+			t.Fatalf("ray %v %v: BVH = (%v, %v, %d, %v), brute force = (%v, %v, %d, %v)",
+				origin, dir, hit, tHit, face, bary, wantHit, wantT, wantFace, wantBary)
+		}
+		if hit {
+			hits++
+		}
+	}
+
+	if hits == 0 {
+		t.Fatal("no rays hit; test is not exercising the BVH")
+	}
+	if brute := rays * len(mesh.Faces); tested*10 > brute {
+		t.Errorf("BVH tested %d triangles, want well under brute force's %d", tested, brute)
+	}
+}
+
+func TestBVHEmptyMesh(t *testing.T) {
+	bvh := NewBVH(NewMesh("empty"))
+	if hit, _, face, _ := bvh.Raycast(math3d.V3(0, 0, 5), math3d.V3(0, 0, -1)); hit || face != -1 {
+		t.Errorf("Raycast on empty BVH = (%v, %d), want (false, -1)", hit, face)
+	}
+	if got := bvh.FrustumCull(halfSpace{math3d.V3(1, 0, 0), 0}); len(got) != 0 {
+		t.Errorf("FrustumCull on empty BVH = %v, want none", got)
+	}
+}
+
+// halfSpace keeps boxes reaching the side of the plane n·p + d = 0 that n points to.
+type halfSpace struct {
+	n math3d.Vec3
+	d float64
+}
+
+func (h halfSpace) IntersectsBox(min, max math3d.Vec3) bool {
+	p := min
+	if h.n.X >= 0 {
+		p.X = max.X
+	}
+	if h.n.Y >= 0 {
+		p.Y = max.Y
+	}
+	if h.n.Z >= 0 {
+		p.Z = max.Z
+	}
+	return h.n.Dot(p)+h.d >= 0
+}
+
+func TestBVHFrustumCull(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	mesh := randomTriangleMesh(rng, 1000)
+	bvh := NewBVH(mesh)
+
+	tests := []struct {
+		name string
+		c    halfSpace
+		keep string // "all", "none", or "some" of the faces
+	}{
+		{"x > 5", halfSpace{math3d.V3(1, 0, 0), -5}, "some"},
+		{"y < 0", halfSpace{math3d.V3(0, -1, 0), 0}, "some"},
+		{"diagonal", halfSpace{math3d.V3(1, 1, 1).Normalize(), -3}, "some"},
+		{"everything", halfSpace{math3d.V3(0, 0, 1), 100}, "all"},
+		{"nothing", halfSpace{math3d.V3(0, 0, 1), -100}, "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bvh.FrustumCull(tt.c)
+			slices.Sort(got)
+			if len(slices.Compact(slices.Clone(got))) != len(got) {
+				t.Fatal("FrustumCull returned a face twice")
+			}
+
+			// Culling is conservative: every face that reaches the
+			// visible side must be kept
+			for i := range mesh.Faces {
+				fmin, fmax := bvh.faceBounds(i)
+				if tt.c.IntersectsBox(fmin, fmax) {
+					if _, ok := slices.BinarySearch(got, i); !ok {
+						t.Fatalf("visible face %d was culled", i)
+					}
+				}
+			}
+
+			switch tt.keep {
+			case "all":
+				if len(got) != len(mesh.Faces) {
+					t.Errorf("kept %d faces, want all %d", len(got), len(mesh.Faces))
+				}
+			case "none":
+				if len(got) != 0 {
+					t.Errorf("kept %d faces, want none", len(got))
+				}
+			default:
+				if len(got) == 0 || len(got) == len(mesh.Faces) {
+					t.Errorf("kept %d of %d faces, want some but not all", len(got), len(mesh.Faces))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import "github.com/taigrr/trophy/pkg/math3d"
+
+// VertexCurvature estimates how sharply the surface bends at each vertex
+// from how much the normals of the faces around it disagree: 0 where they
+// all point the same way, rising toward 1 at creases and pinched points.
+// Vertices sharing a position get the same value, so seams don't show.
+func (m *Mesh) VertexCurvature() []float64 {
+	cluster, pos := m.positionClusters()
+	sums := make([]math3d.Vec3, len(pos))
+	counts := make([]int, len(pos))
+	for _, f := range m.Faces {
+		p0 := m.Vertices[f.V[0]].Position
+		n := m.Vertices[f.V[1]].Position.Sub(p0).Cross(m.Vertices[f.V[2]].Position.Sub(p0))
+		if n.LenSq() == 0 {
+			continue
+		}
+		n = n.Normalize()
+		for _, vi := range f.V {
+			sums[cluster[vi]] = sums[cluster[vi]].Add(n)
+			counts[cluster[vi]]++
+		}
+	}
+
+	out := make([]float64, len(m.Vertices))
+	for i := range out {
+		if c := cluster[i]; counts[c] > 0 {
+			// The mean of unit normals shortens as they spread apart
+			out[i] = 1 - sums[c].Len()/float64(counts[c])
+		}
+	}
+	return out
+}
+
+// VertexFaceArea returns the mean area of the faces around each vertex,
+// which shows how evenly the mesh is tessellated.
+func (m *Mesh) VertexFaceArea() []float64 {
+	cluster, pos := m.positionClusters()
+	sums := make([]float64, len(pos))
+	counts := make([]int, len(pos))
+	for _, f := range m.Faces {
+		p0 := m.Vertices[f.V[0]].Position
+		area := m.Vertices[f.V[1]].Position.Sub(p0).Cross(m.Vertices[f.V[2]].Position.Sub(p0)).Len() / 2
+		for _, vi := range f.V {
+			sums[cluster[vi]] += area
+			counts[cluster[vi]]++
+		}
+	}
+
+	out := make([]float64, len(m.Vertices))
+	for i := range out {
+		if c := cluster[i]; counts[c] > 0 {
+			out[i] = sums[c] / float64(counts[c])
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestVertexCurvature(t *testing.T) {
+	flat := grid(4)
+	for i, c := range flat.VertexCurvature() {
+		if c > 1e-9 {
+			t.Fatalf("flat grid vertex %d: curvature %v, want 0", i, c)
+		}
+	}
+
+	// Fold the grid's right half up 90° along x = 0.5: the fold line bends,
+	// the rest stays flat
+	folded := grid(4)
+	for i, v := range folded.Vertices {
+		if p := v.Position; p.X > 0.5 {
+			folded.Vertices[i].Position = math3d.V3(0.5, p.Y, p.X-0.5)
+		}
+	}
+	curvature := folded.VertexCurvature()
+	for i, v := range folded.Vertices {
+		onFold := v.Position.X == 0.5 && v.Position.Z == 0
+		if bent := curvature[i] > 0.01; bent != onFold {
+			t.Errorf("vertex %v: curvature %v, on fold %v", v.Position, curvature[i], onFold)
+		}
+	}
+}
+
+func TestVertexFaceArea(t *testing.T) {
+	mesh := grid(2)
+	// Each triangle is half of a 0.5×0.5 cell
+	for i, a := range mesh.VertexFaceArea() {
+		if math.Abs(a-0.125) > 1e-9 {
+			t.Fatalf("vertex %d: mean face area %v, want 0.125", i, a)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package models
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// boundaryWeight scales the constraint planes that hold open edges in place,
+// so a decimated scan keeps its outline instead of shrinking inward.
+const boundaryWeight = 10.0
+
+// Decimate reduces the mesh to about targetRatio of its triangles by
+// collapsing the edges whose removal changes the shape least (quadric
+// error metrics). Vertices sharing a position collapse together, so UV and
+// normal seams stay closed. Returns the number of faces removed.
+func (m *Mesh) Decimate(targetRatio float64) int {
+	if targetRatio >= 1 || len(m.Faces) == 0 {
+		return 0
+	}
+	target := int(float64(len(m.Faces)) * max(targetRatio, 0))
+
+	// Collapse positions, not vertices, so seams don't tear apart
+	cluster, pos := m.positionClusters()
+
+	d := &decimator{
+		mesh:     m,
+		cluster:  cluster,
+		pos:      pos,
+		parent:   make([]int, len(pos)),
+		quadrics: make([]quadric, len(pos)),
+		version:  make([]int, len(pos)),
+		faceOf:   make([][]int, len(pos)),
+		alive:    make([]bool, len(m.Faces)),
+	}
+	for i := range d.parent {
+		d.parent[i] = i
+	}
+	d.init()
+
+	for d.live > target && d.heap.Len() > 0 {
+		c := heap.Pop(&d.heap).(collapse)
+		if d.parent[c.a] != c.a || d.parent[c.b] != c.b ||
+			d.version[c.a] != c.va || d.version[c.b] != c.vb {
+			continue // An endpoint moved since this was queued
+		}
+		if d.flips(c) {
+			continue
+		}
+		d.collapse(c)
+	}
+
+	for i := range m.Vertices {
+		m.Vertices[i].Position = d.pos[d.find(cluster[i])]
+	}
+	kept := make([]Face, 0, d.live)
+	for i, f := range m.Faces {
+		if d.alive[i] {
+			kept = append(kept, f)
+		}
+	}
+	removed := len(m.Faces) - len(kept)
+	m.Faces = kept
+	m.RemoveUnreferencedVertices()
+	m.CalculateBounds()
+	return removed
+}
+
+// decimator holds edge-collapse state over position clusters. Collapsed
+// clusters point at the one they merged into through parent.
+type decimator struct {
+	mesh     *Mesh
+	cluster  []int // Vertex index to position cluster
+	pos      []math3d.Vec3
+	parent   []int
+	quadrics []quadric
+	version  []int   // Bumped when a cluster moves, invalidating queued collapses
+	faceOf   [][]int // Live faces touching each cluster
+	alive    []bool
+	live     int
+	heap     collapseHeap
+}
+
+// find returns the cluster c has been merged into.
+func (d *decimator) find(c int) int {
+	for d.parent[c] != c {
+		d.parent[c] = d.parent[d.parent[c]]
+		c = d.parent[c]
+	}
+	return c
+}
+
+// corners returns the current clusters of face fi's vertices.
+func (d *decimator) corners(fi int) [3]int {
+	f := d.mesh.Faces[fi]
+	return [3]int{d.find(d.cluster[f.V[0]]), d.find(d.cluster[f.V[1]]), d.find(d.cluster[f.V[2]])}
+}
+
+// init accumulates face and boundary quadrics and queues every edge.
+func (d *decimator) init() {
+	type edge struct{ a, b int }
+	edgeFaces := make(map[edge]int)
+	edgeNormal := make(map[edge]math3d.Vec3)
+	var edges []edge // In first-seen order, so results don't vary run to run
+
+	for fi := range d.mesh.Faces {
+		c := d.corners(fi)
+		if c[0] == c[1] || c[1] == c[2] || c[0] == c[2] {
+			continue // Already degenerate, dropped
+		}
+		d.alive[fi] = true
+		d.live++
+
+		p0, p1, p2 := d.pos[c[0]], d.pos[c[1]], d.pos[c[2]]
+		n := p1.Sub(p0).Cross(p2.Sub(p0)).Normalize()
+		q := planeQuadric(n, -n.Dot(p0), 1)
+		for _, ci := range c {
+			d.quadrics[ci].add(q)
+			d.faceOf[ci] = append(d.faceOf[ci], fi)
+		}
+		for i := range 3 {
+			e := edge{min(c[i], c[(i+1)%3]), max(c[i], c[(i+1)%3])}
+			if edgeFaces[e] == 0 {
+				edges = append(edges, e)
+			}
+			edgeFaces[e]++
+			edgeNormal[e] = n
+		}
+	}
+
+	for _, e := range edges {
+		if edgeFaces[e] == 1 {
+			// Penalize moving off the plane through the edge, perpendicular to its face
+			pa, pb := d.pos[e.a], d.pos[e.b]
+			n := pb.Sub(pa).Cross(edgeNormal[e]).Normalize()
+			q := planeQuadric(n, -n.Dot(pa), boundaryWeight)
+			d.quadrics[e.a].add(q)
+			d.quadrics[e.b].add(q)
+		}
+	}
+	for _, e := range edges {
+		d.push(e.a, e.b)
+	}
+}
+
+// push queues collapsing cluster b into a at the position of least error.
+func (d *decimator) push(a, b int) {
+	q := d.quadrics[a]
+	q.add(d.quadrics[b])
+	p, ok := q.optimal()
+	if !ok {
+		// Flat or straight regions have no single best point; pick the
+		// better of the endpoints and their midpoint
+		p = d.pos[a]
+		for _, cand := range []math3d.Vec3{d.pos[b], d.pos[a].Add(d.pos[b]).Scale(0.5)} {
+			if q.error(cand) < q.error(p) {
+				p = cand
+			}
+		}
+	}
+	heap.Push(&d.heap, collapse{cost: q.error(p), a: a, b: b, pos: p, va: d.version[a], vb: d.version[b]})
+}
+
+// flips reports whether c would turn a surviving face over.
+func (d *decimator) flips(c collapse) bool {
+	for _, list := range [][]int{d.faceOf[c.a], d.faceOf[c.b]} {
+		for _, fi := range list {
+			if !d.alive[fi] {
+				continue
+			}
+			cs := d.corners(fi)
+			var before, after [3]math3d.Vec3
+			touchesA, touchesB := false, false
+			for i, ci := range cs {
+				before[i] = d.pos[ci]
+				after[i] = d.pos[ci]
+				switch ci {
+				case c.a:
+					touchesA = true
+					after[i] = c.pos
+				case c.b:
+					touchesB = true
+					after[i] = c.pos
+				}
+			}
+			if touchesA && touchesB {
+				continue // Shares the edge, so it goes away
+			}
+			nb := before[1].Sub(before[0]).Cross(before[2].Sub(before[0]))
+			na := after[1].Sub(after[0]).Cross(after[2].Sub(after[0]))
+			if nb.Dot(na) < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collapse merges cluster c.b into c.a, drops the faces that degenerate,
+// and requeues the edges around the merged cluster.
+func (d *decimator) collapse(c collapse) {
+	d.parent[c.b] = c.a
+	d.pos[c.a] = c.pos
+	d.quadrics[c.a].add(d.quadrics[c.b])
+	d.version[c.a]++
+
+	faces := append(d.faceOf[c.a], d.faceOf[c.b]...)
+	d.faceOf[c.b] = nil
+	kept := faces[:0]
+	var neighbors []int
+	for _, fi := range faces {
+		if !d.alive[fi] {
+			continue
+		}
+		cs := d.corners(fi)
+		if cs[0] == cs[1] || cs[1] == cs[2] || cs[0] == cs[2] {
+			d.alive[fi] = false
+			d.live--
+			continue
+		}
+		kept = append(kept, fi)
+		for _, ci := range cs {
+			if ci != c.a && !slices.Contains(neighbors, ci) {
+				neighbors = append(neighbors, ci)
+			}
+		}
+	}
+	d.faceOf[c.a] = kept
+
+	for _, n := range neighbors {
+		d.push(c.a, n)
+	}
+}
+
+// quadric is a symmetric 4x4 error matrix stored as its upper triangle:
+// aa, ab, ac, ad, bb, bc, bd, cc, cd, dd for planes ax+by+cz+d = 0.
+type quadric [10]float64
+
+// planeQuadric returns the squared-distance quadric of plane n·p + d = 0.
+func planeQuadric(n math3d.Vec3, d, weight float64) quadric {
+	a, b, c := n.X, n.Y, n.Z
+	return quadric{
+		a * a * weight, a * b * weight, a * c * weight, a * d * weight,
+		b * b * weight, b * c * weight, b * d * weight,
+		c * c * weight, c * d * weight,
+		d * d * weight,
+	}
+}
+
+// add accumulates o into q.
+func (q *quadric) add(o quadric) {
+	for i := range q {
+		q[i] += o[i]
+	}
+}
+
+// error returns the summed squared distance of v from q's planes.
+func (q quadric) error(v math3d.Vec3) float64 {
+	x, y, z := v.X, v.Y, v.Z
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z +
+		q[9]
+}
+
+// optimal returns the point minimizing q's error, or false when the
+// planes don't pin down a single point.
+func (q quadric) optimal() (math3d.Vec3, bool) {
+	r0 := math3d.V3(q[0], q[1], q[2])
+	r1 := math3d.V3(q[1], q[4], q[5])
+	r2 := math3d.V3(q[2], q[5], q[7])
+	c0, c1, c2 := r1.Cross(r2), r2.Cross(r0), r0.Cross(r1)
+	det := r0.Dot(c0)
+	if math.Abs(det) < 1e-10 {
+		return math3d.Vec3{}, false
+	}
+	// The matrix is symmetric, so the cofactor columns are its inverse's rows
+	return c0.Scale(q[3]).Add(c1.Scale(q[6])).Add(c2.Scale(q[8])).Scale(-1 / det), true
+}
+
+// collapse is a queued edge collapse of cluster b into a at pos.
+type collapse struct {
+	cost   float64
+	a, b   int
+	pos    math3d.Vec3
+	va, vb int // Endpoint versions when queued
+}
+
+// collapseHeap is a min-heap of collapses by cost.
+type collapseHeap []collapse
+
+func (h collapseHeap) Len() int           { return len(h) }
+func (h collapseHeap) Less(i, j int) bool { return h[i].cost < h[j].cost }
+func (h collapseHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *collapseHeap) Push(x any)        { *h = append(*h, x.(collapse)) }
+func (h *collapseHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
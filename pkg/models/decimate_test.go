@@ -0,0 +1,161 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// uvSphere returns a unit sphere with a duplicated seam column, like
+// loaders produce for textured models.
+func uvSphere(rings, segments int) *Mesh {
+	mesh := NewMesh("sphere")
+	for r := 0; r <= rings; r++ {
+		theta := math.Pi * float64(r) / float64(rings)
+		for s := 0; s <= segments; s++ {
+			phi := 2 * math.Pi * float64(s) / float64(segments)
+			p := math3d.V3(math.Sin(theta)*math.Cos(phi), math.Cos(theta), math.Sin(theta)*math.Sin(phi))
+			// Pin the seam and poles so duplicates share exact positions
+			if s == segments {
+				p = mesh.Vertices[r*(segments+1)].Position
+			}
+			if r == 0 || r == rings {
+				p = math3d.V3(0, p.Y, 0)
+			}
+			mesh.Vertices = append(mesh.Vertices, MeshVertex{
+				Position: p,
+				Normal:   p,
+				UV:       math3d.V2(float64(s)/float64(segments), float64(r)/float64(rings)),
+			})
+		}
+	}
+	for r := range rings {
+		for s := range segments {
+			a := r*(segments+1) + s
+			b := a + segments + 1
+			if r != 0 {
+				mesh.Faces = append(mesh.Faces, Face{V: [3]int{a, a + 1, b}})
+			}
+			if r != rings-1 {
+				mesh.Faces = append(mesh.Faces, Face{V: [3]int{a + 1, b + 1, b}})
+			}
+		}
+	}
+	mesh.CalculateBounds()
+	return mesh
+}
+
+// grid returns an n×n quad grid in the z=0 plane spanning [0, 1].
+func grid(n int) *Mesh {
+	mesh := NewMesh("grid")
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			mesh.Vertices = append(mesh.Vertices, MeshVertex{
+				Position: math3d.V3(float64(x)/float64(n), float64(y)/float64(n), 0),
+			})
+		}
+	}
+	for y := range n {
+		for x := range n {
+			a := y*(n+1) + x
+			mesh.Faces = append(mesh.Faces,
+				Face{V: [3]int{a, a + n + 1, a + 1}},
+				Face{V: [3]int{a + 1, a + n + 1, a + n + 2}})
+		}
+	}
+	mesh.CalculateBounds()
+	return mesh
+}
+
+func TestDecimateSphere(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio float64
+	}{
+		{"half", 0.5},
+		{"quarter", 0.25},
+		{"tenth", 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := uvSphere(24, 48)
+			before := mesh.TriangleCount()
+			removed := mesh.Decimate(tt.ratio)
+
+			after := mesh.TriangleCount()
+			if removed != before-after {
+				t.Errorf("Decimate() = %d, but triangle count went %d -> %d", removed, before, after)
+			}
+			if target := int(float64(before) * tt.ratio); after > target || after < target-2 {
+				t.Errorf("triangle count = %d, want about %d", after, target)
+			}
+
+			// The shape survives: vertices stay near the surface
+			for i, v := range mesh.Vertices {
+				if r := v.Position.Len(); math.Abs(r-1) > 0.1 {
+					t.Fatalf("vertex %d at radius %.3f, want about 1", i, r)
+				}
+			}
+			for i, f := range mesh.Faces {
+				for _, vi := range f.V {
+					if vi < 0 || vi >= len(mesh.Vertices) {
+						t.Fatalf("face %d references vertex %d of %d", i, vi, len(mesh.Vertices))
+					}
+				}
+			}
+
+			// Seams stay closed: every edge between positions is shared by two faces
+			type edge [2]math3d.Vec3
+			key := func(a, b math3d.Vec3) edge {
+				if a.X < b.X || (a.X == b.X && (a.Y < b.Y || (a.Y == b.Y && a.Z < b.Z))) {
+					return edge{a, b}
+				}
+				return edge{b, a}
+			}
+			counts := map[edge]int{}
+			for _, f := range mesh.Faces {
+				for i := range 3 {
+					counts[key(mesh.Vertices[f.V[i]].Position, mesh.Vertices[f.V[(i+1)%3]].Position)]++
+				}
+			}
+			for e, n := range counts {
+				if n != 2 {
+					t.Fatalf("edge %v used by %d faces, want 2", e, n)
+				}
+			}
+		})
+	}
+}
+
+func TestDecimateKeepsBoundary(t *testing.T) {
+	mesh := grid(16)
+	mesh.Decimate(0.1)
+
+	if mesh.TriangleCount() == 0 {
+		t.Fatal("decimated away every triangle")
+	}
+	if mesh.BoundsMin != math3d.V3(0, 0, 0) || mesh.BoundsMax != math3d.V3(1, 1, 0) {
+		t.Errorf("bounds = %v..%v, want (0,0,0)..(1,1,0)", mesh.BoundsMin, mesh.BoundsMax)
+	}
+
+	// No face turned over
+	for i, f := range mesh.Faces {
+		p0, p1, p2 := mesh.Vertices[f.V[0]].Position, mesh.Vertices[f.V[1]].Position, mesh.Vertices[f.V[2]].Position
+		if n := p1.Sub(p0).Cross(p2.Sub(p0)); n.Z > 0 {
+			t.Errorf("face %d flipped, normal %v", i, n)
+		}
+	}
+}
+
+func TestDecimateNoop(t *testing.T) {
+	mesh := uvSphere(8, 16)
+	before := mesh.TriangleCount()
+	if removed := mesh.Decimate(1); removed != 0 || mesh.TriangleCount() != before {
+		t.Errorf("Decimate(1) removed %d faces, want none", removed)
+	}
+	if removed := NewMesh("empty").Decimate(0.5); removed != 0 {
+		t.Errorf("Decimate on empty mesh removed %d faces", removed)
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotGLTF is returned when a .glb or .gltf file holds something else,
+// like an error page saved under the model's name.
+var ErrNotGLTF = errors.New("not a valid GLB/glTF file")
+
+// ErrLFSPointer is returned for a model file that is a Git LFS pointer,
+// left in place of the model when a repository is cloned without LFS.
+var ErrLFSPointer = errors.New("file is a Git LFS pointer, not the model; run `git lfs pull` to fetch it")
+
+// isLFSPointer reports whether data is a Git LFS pointer file.
+func isLFSPointer(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("version https://git-lfs.github.com/spec/"))
+}
+
+// DetectFormat guesses a model's format from its contents, for data that
+// has no file name to go by. Returns the format's usual extension: ".glb",
+// ".gltf", ".stl", ".obj", or ".ply", or "" if it's none of them.
+func DetectFormat(data []byte) string {
+	text := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	switch {
+	case bytes.HasPrefix(data, []byte("glTF")):
+		return ".glb"
+	case len(data) >= 84 && uint64(len(data)) == 84+50*uint64(binary.LittleEndian.Uint32(data[80:84])):
+		// Binary STL has no magic; its triangle count matching its size will do
+		return ".stl"
+	case bytes.HasPrefix(text, []byte("{")):
+		return ".gltf"
+	case bytes.HasPrefix(data, []byte("ply\n")), bytes.HasPrefix(data, []byte("ply\r\n")):
+		return ".ply"
+	case bytes.HasPrefix(text, []byte("solid")):
+		return ".stl"
+	case isOBJ(text):
+		return ".obj"
+	}
+	return ""
+}
+
+// isOBJ reports whether data starts with OBJ statements, up to a vertex
+// or face.
+func isOBJ(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "v", "f":
+			return true
+		case "vt", "vn", "l", "p", "o", "g", "s", "mtllib", "usemtl":
+			continue
+		}
+		return false
+	}
+	return false
+}
+
+// checkGLTF returns nil if head, the start of a file, starts like a GLB or
+// glTF JSON document. Otherwise it returns an error wrapping ErrNotGLTF
+// that says what the file looks like instead.
+func checkGLTF(head []byte) error {
+	text := bytes.TrimLeft(head, " \t\r\n\ufeff")
+	switch {
+	case bytes.HasPrefix(head, []byte("glTF")), bytes.HasPrefix(text, []byte("{")):
+		return nil
+	case len(text) == 0:
+		return fmt.Errorf("%w: the file is empty", ErrNotGLTF)
+	case isLFSPointer(head):
+		return fmt.Errorf("%w: %w", ErrNotGLTF, ErrLFSPointer)
+	case !isText(head):
+		return fmt.Errorf("%w: unrecognized binary data", ErrNotGLTF)
+	case bytes.HasPrefix(text, []byte("<")):
+		return fmt.Errorf("%w: looks like HTML or XML, maybe an error page saved in place of the model", ErrNotGLTF)
+	}
+	if format := DetectFormat(head); format != "" {
+		return fmt.Errorf("%w: looks like %s, despite the name", ErrNotGLTF, strings.ToUpper(format[1:]))
+	}
+	return fmt.Errorf("%w: looks like text, maybe an error page", ErrNotGLTF)
+}
+
+// isText reports whether data has no control characters besides
+// whitespace, as binary formats nearly always do.
+func isText(data []byte) bool {
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' || b == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	binarySTL := make([]byte, 84+50)
+	copy(binarySTL, "solid but actually binary")
+	binary.LittleEndian.PutUint32(binarySTL[80:], 1)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"glb", "glTF\x02\x00\x00\x00", ".glb"},
+		{"gltf json", "\n  {\"asset\": {\"version\": \"2.0\"}}", ".gltf"},
+		{"ascii stl", "solid cube\n  facet normal 0 0 1\n", ".stl"},
+		{"binary stl", string(binarySTL), ".stl"},
+		{"obj", "# exported\nmtllib cube.mtl\no Cube\nv 0 0 0\n", ".obj"},
+		{"obj faces first", "f 1 2 3\n", ".obj"},
+		{"ply", "ply\nformat ascii 1.0\n", ".ply"},
+		{"html", "<!DOCTYPE html>\n<html>", ""},
+		{"text", "hello\n", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+const lfsPointer = `version https://git-lfs.github.com/spec/v1
+oid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393
+size 12345
+`
+
+func TestLoadLFSPointer(t *testing.T) {
+	if _, err := NewOBJLoader().Load(strings.NewReader(lfsPointer), "model.obj"); !errors.Is(err, ErrLFSPointer) {
+		t.Errorf("OBJ error = %v, want ErrLFSPointer", err)
+	}
+	if _, err := NewSTLLoader().LoadBytes([]byte(lfsPointer), "model.stl"); !errors.Is(err, ErrLFSPointer) {
+		t.Errorf("STL error = %v, want ErrLFSPointer", err)
+	}
+}
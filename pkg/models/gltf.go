@@ -1,17 +1,24 @@
 package models
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"unsafe"
 
 	"github.com/qmuntal/gltf"
 	"github.com/taigrr/trophy/pkg/math3d"
+	_ "golang.org/x/image/webp"
 )
 
 // GLTFLoader loads GLTF/GLB files into Mesh format.
@@ -19,6 +26,26 @@ type GLTFLoader struct {
 	// Options
 	CalculateNormals bool
 	SmoothNormals    bool
+
+	// LazyTextures skips decoding every material's BaseMap on load. Only the
+	// texture returned by LoadWithTextureContext is decoded; materials still
+	// report HasTexture.
+	LazyTextures bool
+
+	// Progress, if set, is called as the file is read and meshes are processed
+	Progress func(LoadProgress)
+
+	// Warn, if set, is called for problems that don't stop the load, like a
+	// texture image that can't be decoded
+	Warn func(error)
+}
+
+// LoadProgress reports how far a GLTF load has got.
+type LoadProgress struct {
+	BytesRead   int64
+	BytesTotal  int64
+	MeshesDone  int
+	MeshesTotal int
 }
 
 // NewGLTFLoader creates a new GLTF loader with default options.
@@ -37,19 +64,115 @@ func LoadGLB(path string) (*Mesh, error) {
 
 // Load loads a GLTF or GLB file and returns a Mesh.
 func (l *GLTFLoader) Load(path string) (*Mesh, error) {
-	doc, err := gltf.Open(path)
+	return l.LoadContext(context.Background(), path)
+}
+
+// LoadContext is like Load but stops early with ctx's error if ctx is
+// canceled while reading the file or between meshes.
+func (l *GLTFLoader) LoadContext(ctx context.Context, path string) (*Mesh, error) {
+	doc, fsys, err := l.open(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return l.buildMesh(ctx, doc, filepath.Base(path), l.images(doc, fsys))
+}
+
+// open decodes the document, reporting read progress and honoring ctx.
+// Also returns the file system its external resources are read from.
+func (l *GLTFLoader) open(ctx context.Context, path string) (*gltf.Document, fs.FS, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open gltf: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
 	if err != nil {
+		return nil, nil, fmt.Errorf("open gltf: %w", err)
+	}
+
+	fsys := os.DirFS(filepath.Dir(path))
+	doc, err := l.decode(ctx, f, info.Size(), fsys)
+	return doc, fsys, err
+}
+
+// decode reads a GLTF or GLB document of size bytes from r, reading
+// external buffers from fsys.
+func (l *GLTFLoader) decode(ctx context.Context, r io.Reader, size int64, fsys fs.FS) (*gltf.Document, error) {
+	br := bufio.NewReader(&progressReader{r: r, ctx: ctx, total: size, progress: l.Progress})
+
+	// A file that isn't glTF at all would fail with a cryptic JSON error
+	head, _ := br.Peek(512)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("open gltf: %w", ctx.Err())
+	}
+	if err := checkGLTF(head); err != nil {
 		return nil, fmt.Errorf("open gltf: %w", err)
 	}
 
-	mesh := NewMesh(filepath.Base(path))
+	doc := new(gltf.Document)
+	if err := gltf.NewDecoderFS(br, fsys).Decode(doc); err != nil {
+		// The decoder may wrap or replace the read error; report cancellation plainly
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("open gltf: %w", ctx.Err())
+		}
+		return nil, fmt.Errorf("open gltf: %w", err)
+	}
+	return doc, nil
+}
+
+// errNoExternal is returned for external resources of a document read
+// from memory without a file system to find them in.
+var errNoExternal = errors.New("external resources can't be resolved")
+
+// noFS is the file system of a document that must be self-contained.
+type noFS struct{}
+
+func (noFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: errNoExternal}
+}
+
+// progressReader counts bytes read and fails once ctx is canceled.
+type progressReader struct {
+	r        io.Reader
+	ctx      context.Context
+	read     int64
+	total    int64
+	progress func(LoadProgress)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if p.progress != nil && n > 0 {
+		p.progress(LoadProgress{BytesRead: p.read, BytesTotal: p.total})
+	}
+	return n, err
+}
+
+// buildMesh converts a decoded document into a Mesh.
+func (l *GLTFLoader) buildMesh(ctx context.Context, doc *gltf.Document, name string, images *gltfImages) (*Mesh, error) {
+	mesh := NewMesh(name)
 
 	// Extract materials first
-	mesh.Materials = extractMaterials(doc, path)
+	mesh.Materials = extractMaterials(doc, images, !l.LazyTextures)
 
-	// Process scene nodes with transforms (handles node hierarchy)
-	processedMeshes := make(map[int]bool)
+	w := &meshWalk{
+		ctx:       ctx,
+		doc:       doc,
+		mesh:      mesh,
+		processed: make(map[int]bool),
+	}
+	for _, node := range doc.Nodes {
+		if node.Mesh != nil {
+			w.total++
+		}
+	}
 
+	// Process scene nodes with transforms (handles node hierarchy)
 	if len(doc.Scenes) > 0 {
 		sceneIdx := 0
 		if doc.Scene != nil {
@@ -57,7 +180,9 @@ func (l *GLTFLoader) Load(path string) (*Mesh, error) {
 		}
 		scene := doc.Scenes[sceneIdx]
 		for _, nodeIdx := range scene.Nodes {
-			l.processNode(doc, int(nodeIdx), math3d.Identity(), mesh, processedMeshes)
+			if err := l.processNode(w, int(nodeIdx), math3d.Identity()); err != nil {
+				return nil, err
+			}
 		}
 	} else {
 		// No scenes defined, process all root nodes
@@ -75,7 +200,9 @@ func (l *GLTFLoader) Load(path string) (*Mesh, error) {
 				}
 			}
 			if isRoot {
-				l.processNode(doc, i, math3d.Identity(), mesh, processedMeshes)
+				if err := l.processNode(w, i, math3d.Identity()); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
@@ -91,20 +218,42 @@ func (l *GLTFLoader) Load(path string) (*Mesh, error) {
 
 	if l.CalculateNormals && !hasNormals {
 		if l.SmoothNormals {
+			// Primitives and mesh instances each bring their own vertices;
+			// share the identical ones so the normals average across them
+			mesh.Reindex()
 			mesh.CalculateSmoothNormals()
 		} else {
-			mesh.CalculateNormals()
+			// Indexed primitives share vertices between faces too, so
+			// split them for each face to keep its own normal
+			mesh.CalculateNormalsWithAngle(0)
 		}
+	} else {
+		mesh.Reindex()
 	}
 
+	mesh.Winding = WindingCW // appendPrimitive reverses glTF's CCW triangles
 	mesh.CalculateBounds()
 
 	return mesh, nil
 }
 
+// meshWalk holds the state shared across a node hierarchy traversal.
+type meshWalk struct {
+	ctx         context.Context
+	doc         *gltf.Document
+	mesh        *Mesh
+	processed   map[int]bool
+	done, total int
+}
+
 // processNode recursively processes a node and its children, accumulating transforms.
-func (l *GLTFLoader) processNode(doc *gltf.Document, nodeIdx int, parentTransform math3d.Mat4, mesh *Mesh, processedMeshes map[int]bool) {
-	node := doc.Nodes[nodeIdx]
+// Returns the context's error if the load was canceled.
+func (l *GLTFLoader) processNode(w *meshWalk, nodeIdx int, parentTransform math3d.Mat4) error {
+	if err := w.ctx.Err(); err != nil {
+		return fmt.Errorf("load gltf: %w", err)
+	}
+
+	node := w.doc.Nodes[nodeIdx]
 
 	// Build this node's local transform
 	localTransform := math3d.Identity()
@@ -142,20 +291,42 @@ func (l *GLTFLoader) processNode(doc *gltf.Document, nodeIdx int, parentTransfor
 
 	if node.Mesh != nil {
 		meshIdx := int(*node.Mesh)
-		gltfMesh := doc.Meshes[meshIdx]
-		l.processMeshWithTransform(doc, gltfMesh, mesh, worldTransform)
-		processedMeshes[meshIdx] = true
+		gltfMesh := w.doc.Meshes[meshIdx]
+		// Each node is a part, named for the node or else its mesh
+		part := node.Name
+		if part == "" {
+			part = gltfMesh.Name
+		}
+		if part == "" {
+			part = fmt.Sprintf("node %d", nodeIdx)
+		}
+		if err := l.processMeshWithTransform(w.doc, gltfMesh, w.mesh, worldTransform, part); err != nil {
+			return fmt.Errorf("load gltf: %s: %w", part, err)
+		}
+		w.processed[meshIdx] = true
+
+		w.done++
+		if l.Progress != nil {
+			l.Progress(LoadProgress{MeshesDone: w.done, MeshesTotal: w.total})
+		}
 	}
 
 	for _, childIdx := range node.Children {
-		l.processNode(doc, int(childIdx), worldTransform, mesh, processedMeshes)
+		if err := l.processNode(w, int(childIdx), worldTransform); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // processMeshWithTransform extracts geometry from a GLTF mesh, applying the given transform.
-func (l *GLTFLoader) processMeshWithTransform(doc *gltf.Document, m *gltf.Mesh, mesh *Mesh, transform math3d.Mat4) error {
-	for _, prim := range m.Primitives {
-		if prim.Mode != gltf.PrimitiveTriangles && prim.Mode != 0 {
+// Its faces join the group named part, split by primitive if it has several.
+func (l *GLTFLoader) processMeshWithTransform(doc *gltf.Document, m *gltf.Mesh, mesh *Mesh, transform math3d.Mat4, part string) error {
+	for primIdx, prim := range m.Primitives {
+		switch prim.Mode {
+		case gltf.PrimitiveTriangles, gltf.PrimitiveTriangleStrip, gltf.PrimitiveTriangleFan,
+			gltf.PrimitiveLines, gltf.PrimitiveLineLoop, gltf.PrimitiveLineStrip, gltf.PrimitivePoints:
+		default:
 			continue
 		}
 
@@ -177,13 +348,19 @@ func (l *GLTFLoader) processMeshWithTransform(doc *gltf.Document, m *gltf.Mesh,
 			}
 		}
 
-		var uvs []math3d.Vec2
+		var uvs, uvs1 []math3d.Vec2
 		if uvIdx, ok := prim.Attributes[gltf.TEXCOORD_0]; ok {
 			uvs, err = readVec2Accessor(doc, uvIdx)
 			if err != nil {
 				return fmt.Errorf("read uvs: %w", err)
 			}
 		}
+		if uvIdx, ok := prim.Attributes[gltf.TEXCOORD_1]; ok {
+			uvs1, err = readVec2Accessor(doc, uvIdx)
+			if err != nil {
+				return fmt.Errorf("read second uv set: %w", err)
+			}
+		}
 
 		materialIdx := -1
 		if prim.Material != nil {
@@ -191,6 +368,7 @@ func (l *GLTFLoader) processMeshWithTransform(doc *gltf.Document, m *gltf.Mesh,
 		}
 
 		baseVertex := len(mesh.Vertices)
+		normalMat := transform.NormalMatrix()
 
 		for i := range positions {
 			worldPos := transform.MulVec3(positions[i])
@@ -200,40 +378,38 @@ func (l *GLTFLoader) processMeshWithTransform(doc *gltf.Document, m *gltf.Mesh,
 			}
 
 			if i < len(normals) {
-				v.Normal = transform.MulVec3Dir(normals[i]).Normalize()
+				v.Normal = normalMat.MulVec3Dir(normals[i]).Normalize()
 			}
 			if i < len(uvs) {
+				// GLTF uses top-left origin (V=0 at top), flip V for bottom-left origin
 				v.UV = math3d.V2(uvs[i].X, 1.0-uvs[i].Y)
 			}
+			if i < len(uvs1) {
+				v.UV1 = math3d.V2(uvs1[i].X, 1.0-uvs1[i].Y)
+			}
 			mesh.Vertices = append(mesh.Vertices, v)
 		}
 
+		// Without indices, vertices are used in order
+		var indices []int
 		if prim.Indices != nil {
-			indices, err := readIndices(doc, *prim.Indices)
+			indices, err = readIndices(doc, *prim.Indices)
 			if err != nil {
 				return fmt.Errorf("read indices: %w", err)
 			}
-
-			for i := 0; i+2 < len(indices); i += 3 {
-				mesh.Faces = append(mesh.Faces, Face{
-					V: [3]int{
-						baseVertex + indices[i],
-						baseVertex + indices[i+2],
-						baseVertex + indices[i+1],
-					},
-					Material: materialIdx,
-				})
-			}
 		} else {
-			for i := 0; i+2 < len(positions); i += 3 {
-				mesh.Faces = append(mesh.Faces, Face{
-					V: [3]int{
-						baseVertex + i,
-						baseVertex + i + 2,
-						baseVertex + i + 1,
-					},
-					Material: materialIdx,
-				})
+			indices = make([]int, len(positions))
+			for i := range indices {
+				indices[i] = i
+			}
+		}
+
+		firstFace := len(mesh.Faces)
+		appendPrimitive(mesh, prim.Mode, indices, baseVertex, materialIdx)
+		if len(mesh.Faces) > firstFace {
+			group := mesh.groupIndex(primitiveGroup(doc, m, primIdx, part))
+			for i := firstFace; i < len(mesh.Faces); i++ {
+				mesh.Faces[i].Group = group
 			}
 		}
 	}
@@ -241,108 +417,78 @@ func (l *GLTFLoader) processMeshWithTransform(doc *gltf.Document, m *gltf.Mesh,
 	return nil
 }
 
-// processMesh extracts geometry from a GLTF mesh.
-func (l *GLTFLoader) processMesh(doc *gltf.Document, m *gltf.Mesh, mesh *Mesh) error {
-	for _, prim := range m.Primitives {
-		if prim.Mode != gltf.PrimitiveTriangles && prim.Mode != 0 {
-			// Skip non-triangle primitives (lines, points, etc)
-			continue
-		}
-
-		// Get position accessor
-		posIdx, ok := prim.Attributes[gltf.POSITION]
-		if !ok {
-			continue
+// appendPrimitive adds the faces, lines, or points described by indices
+// (relative to baseVertex) to the mesh.
+// Strips and fans are expanded into triangles as the spec orders them.
+func appendPrimitive(mesh *Mesh, mode gltf.PrimitiveMode, indices []int, baseVertex, materialIdx int) {
+	// Note: GLTF uses CCW winding for front-facing, but our engine uses CW
+	// (due to Y-flip in screen space), so we reverse the winding here
+	addTriangle := func(a, b, c int) {
+		if a == b || b == c || a == c {
+			return // Degenerate; strips use these to join runs
 		}
+		mesh.Faces = append(mesh.Faces, Face{
+			V:        [3]int{baseVertex + a, baseVertex + c, baseVertex + b},
+			Material: materialIdx,
+		})
+	}
 
-		positions, err := readVec3Accessor(doc, posIdx)
-		if err != nil {
-			return fmt.Errorf("read positions: %w", err)
+	switch mode {
+	case gltf.PrimitiveTriangles:
+		for i := 0; i+2 < len(indices); i += 3 {
+			addTriangle(indices[i], indices[i+1], indices[i+2])
 		}
-
-		// Get normals if available
-		var normals []math3d.Vec3
-		if normIdx, ok := prim.Attributes[gltf.NORMAL]; ok {
-			normals, err = readVec3Accessor(doc, normIdx)
-			if err != nil {
-				return fmt.Errorf("read normals: %w", err)
+	case gltf.PrimitiveTriangleStrip:
+		// Every other triangle swaps its last two vertices to keep the winding
+		for i := 0; i+2 < len(indices); i++ {
+			if i%2 == 0 {
+				addTriangle(indices[i], indices[i+1], indices[i+2])
+			} else {
+				addTriangle(indices[i], indices[i+2], indices[i+1])
 			}
 		}
-
-		// Get UVs if available
-		var uvs []math3d.Vec2
-		if uvIdx, ok := prim.Attributes[gltf.TEXCOORD_0]; ok {
-			uvs, err = readVec2Accessor(doc, uvIdx)
-			if err != nil {
-				return fmt.Errorf("read uvs: %w", err)
-			}
+	case gltf.PrimitiveTriangleFan:
+		for i := 1; i+1 < len(indices); i++ {
+			addTriangle(indices[i], indices[i+1], indices[0])
 		}
-
-		// Base vertex index for this primitive
-		baseVertex := len(mesh.Vertices)
-
-		// Add vertices
-		for i := range positions {
-			v := MeshVertex{
-				Position: positions[i],
-			}
-			if i < len(normals) {
-				v.Normal = normals[i]
-			}
-			if i < len(uvs) {
-				// GLTF uses top-left origin (V=0 at top), flip V for bottom-left origin
-				v.UV = math3d.V2(uvs[i].X, 1.0-uvs[i].Y)
-			}
-			mesh.Vertices = append(mesh.Vertices, v)
+	case gltf.PrimitiveLines:
+		for i := 0; i+1 < len(indices); i += 2 {
+			mesh.Lines = append(mesh.Lines, [2]int{baseVertex + indices[i], baseVertex + indices[i+1]})
 		}
-
-		// Get material index for this primitive
-		materialIdx := -1
-		if prim.Material != nil {
-			materialIdx = int(*prim.Material)
+	case gltf.PrimitiveLineStrip, gltf.PrimitiveLineLoop:
+		for i := 0; i+1 < len(indices); i++ {
+			mesh.Lines = append(mesh.Lines, [2]int{baseVertex + indices[i], baseVertex + indices[i+1]})
 		}
-
-		// Process indices
-		if prim.Indices != nil {
-			indices, err := readIndices(doc, *prim.Indices)
-			if err != nil {
-				return fmt.Errorf("read indices: %w", err)
-			}
-
-			// Create faces from indices
-			// Note: GLTF uses CCW winding for front-facing, but our engine uses CW
-			// (due to Y-flip in screen space), so we reverse the winding here
-			for i := 0; i+2 < len(indices); i += 3 {
-				mesh.Faces = append(mesh.Faces, Face{
-					V: [3]int{
-						baseVertex + indices[i],
-						baseVertex + indices[i+2], // swapped
-						baseVertex + indices[i+1], // swapped
-					},
-					Material: materialIdx,
-				})
-			}
-		} else {
-			// No indices, assume sequential triangles
-			// Also reverse winding: CCW -> CW
-			for i := 0; i+2 < len(positions); i += 3 {
-				mesh.Faces = append(mesh.Faces, Face{
-					V: [3]int{
-						baseVertex + i,
-						baseVertex + i + 2, // swapped
-						baseVertex + i + 1, // swapped
-					},
-					Material: materialIdx,
-				})
-			}
+		if mode == gltf.PrimitiveLineLoop && len(indices) > 2 {
+			mesh.Lines = append(mesh.Lines, [2]int{baseVertex + indices[len(indices)-1], baseVertex + indices[0]})
+		}
+	case gltf.PrimitivePoints:
+		for _, idx := range indices {
+			mesh.Points = append(mesh.Points, baseVertex+idx)
 		}
 	}
+}
 
-	return nil
+// primitiveGroup names the group for a mesh's primitive: part, followed by
+// the primitive's material name or number when the mesh has several.
+func primitiveGroup(doc *gltf.Document, m *gltf.Mesh, primIdx int, part string) string {
+	if len(m.Primitives) < 2 {
+		return part
+	}
+	if mat := m.Primitives[primIdx].Material; mat != nil && int(*mat) < len(doc.Materials) && doc.Materials[*mat].Name != "" {
+		return part + "/" + doc.Materials[*mat].Name
+	}
+	return fmt.Sprintf("%s/%d", part, primIdx)
+}
+
+// processMesh extracts geometry from a GLTF mesh without a node transform.
+func (l *GLTFLoader) processMesh(doc *gltf.Document, m *gltf.Mesh, mesh *Mesh) error {
+	return l.processMeshWithTransform(doc, m, mesh, math3d.Identity(), m.Name)
 }
 
 // extractMaterials extracts all materials from a GLTF document.
-func extractMaterials(doc *gltf.Document, basePath string) []Material {
+// Base color textures are only decoded if decodeTextures is set.
+func extractMaterials(doc *gltf.Document, images *gltfImages, decodeTextures bool) []Material {
 	materials := make([]Material, len(doc.Materials))
 
 	for i, mat := range doc.Materials {
@@ -375,55 +521,187 @@ func extractMaterials(doc *gltf.Document, basePath string) []Material {
 			}
 
 			// Extract base color texture if present
-			if pbr.BaseColorTexture != nil {
-				texIdx := pbr.BaseColorTexture.Index
-				if int(texIdx) < len(doc.Textures) {
-					tex := doc.Textures[texIdx]
-					if tex.Source != nil && int(*tex.Source) < len(doc.Images) {
-						img := doc.Images[*tex.Source]
-						texImg := loadGLTFImage(doc, img, basePath)
-						if texImg != nil {
-							m.BaseMap = texImg
-							m.HasTexture = true
-						}
-					}
+			if imgIdx, ok := baseColorImage(doc, mat); ok {
+				m.BaseMapUV = pbr.BaseColorTexture.TexCoord
+				m.BaseMapWrap = textureWrap(doc, int(pbr.BaseColorTexture.Index))
+				if !decodeTextures {
+					m.HasTexture = true
+				} else if texImg := images.load(imgIdx); texImg != nil {
+					m.BaseMap = texImg
+					m.HasTexture = true
 				}
 			}
 		}
 
+		if imgIdx, ok := occlusionImage(doc, mat); ok {
+			m.OcclusionUV = mat.OcclusionTexture.TexCoord
+			m.OcclusionStrength = mat.OcclusionTexture.StrengthOrDefault()
+			m.HasOcclusion = true
+			if decodeTextures {
+				m.OcclusionMap = images.load(imgIdx)
+			}
+		}
+
 		materials[i] = m
 	}
 
 	return materials
 }
 
+// baseColorImage returns the index of the image used as mat's base color texture.
+func baseColorImage(doc *gltf.Document, mat *gltf.Material) (int, bool) {
+	pbr := mat.PBRMetallicRoughness
+	if pbr == nil || pbr.BaseColorTexture == nil {
+		return 0, false
+	}
+	return textureImage(doc, int(pbr.BaseColorTexture.Index))
+}
+
+// occlusionImage returns the index of the image used as mat's occlusion map.
+func occlusionImage(doc *gltf.Document, mat *gltf.Material) (int, bool) {
+	if mat.OcclusionTexture == nil || mat.OcclusionTexture.Index == nil {
+		return 0, false
+	}
+	return textureImage(doc, *mat.OcclusionTexture.Index)
+}
+
+// textureImage returns the index of the image texture texIdx samples.
+func textureImage(doc *gltf.Document, texIdx int) (int, bool) {
+	if texIdx < 0 || texIdx >= len(doc.Textures) {
+		return 0, false
+	}
+	tex := doc.Textures[texIdx]
+	// EXT_texture_webp names the preferred image, with source as a fallback
+	src, ok := extensionSource(tex, "EXT_texture_webp")
+	if !ok && tex.Source != nil {
+		src, ok = *tex.Source, true
+	}
+	if !ok {
+		// Basis Universal seldom decodes, so it's only used without a fallback
+		src, ok = extensionSource(tex, "KHR_texture_basisu")
+	}
+	if !ok || src < 0 || src >= len(doc.Images) {
+		return 0, false
+	}
+	return src, true
+}
+
+// textureWrap returns how texture texIdx tiles along U and V, from its
+// sampler's wrapS and wrapT.
+func textureWrap(doc *gltf.Document, texIdx int) [2]TextureWrap {
+	var wrap [2]TextureWrap
+	if texIdx < 0 || texIdx >= len(doc.Textures) {
+		return wrap
+	}
+	s := doc.Textures[texIdx].Sampler
+	if s == nil || *s < 0 || *s >= len(doc.Samplers) {
+		return wrap
+	}
+	sampler := doc.Samplers[*s]
+	for i, mode := range []gltf.WrappingMode{sampler.WrapS, sampler.WrapT} {
+		switch mode {
+		case gltf.WrapClampToEdge:
+			wrap[i] = WrapClamp
+		case gltf.WrapMirroredRepeat:
+			wrap[i] = WrapMirror
+		}
+	}
+	return wrap
+}
+
+// extensionSource returns the image a texture extension, like
+// KHR_texture_basisu, names in place of the texture's own source.
+func extensionSource(tex *gltf.Texture, name string) (int, bool) {
+	raw, ok := tex.Extensions[name].(json.RawMessage)
+	if !ok {
+		return 0, false
+	}
+	var ext struct {
+		Source *int `json:"source"`
+	}
+	if err := json.Unmarshal(raw, &ext); err != nil || ext.Source == nil {
+		return 0, false
+	}
+	return *ext.Source, true
+}
+
+// gltfImages decodes a document's images on demand, each at most once,
+// passing decode failures to warn.
+type gltfImages struct {
+	doc     *gltf.Document
+	fsys    fs.FS // Where external images are read from
+	warn    func(error)
+	decoded map[int]image.Image // nil for images that failed
+}
+
+// images returns an image decoder for doc that reads external images from
+// fsys and warns through l.Warn.
+func (l *GLTFLoader) images(doc *gltf.Document, fsys fs.FS) *gltfImages {
+	return &gltfImages{doc: doc, fsys: fsys, warn: l.Warn, decoded: make(map[int]image.Image)}
+}
+
+// load returns image i decoded, or nil if it can't be.
+func (g *gltfImages) load(i int) image.Image {
+	if img, ok := g.decoded[i]; ok {
+		return img
+	}
+	img, err := loadGLTFImage(g.doc, g.doc.Images[i], g.fsys)
+	if err != nil && g.warn != nil {
+		name := g.doc.Images[i].Name
+		if name == "" {
+			name = g.doc.Images[i].URI
+		}
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		g.warn(fmt.Errorf("texture image %s: %w", name, err))
+	}
+	g.decoded[i] = img
+	return img
+}
+
 // loadGLTFImage loads an image from GLTF (embedded or external).
-func loadGLTFImage(doc *gltf.Document, img *gltf.Image, basePath string) image.Image {
-	if img.BufferView != nil {
+func loadGLTFImage(doc *gltf.Document, img *gltf.Image, fsys fs.FS) (image.Image, error) {
+	var data []byte
+	switch {
+	case img.BufferView != nil:
 		// Embedded image
 		bv := doc.BufferViews[*img.BufferView]
 		buf := doc.Buffers[bv.Buffer]
-		if buf.Data != nil {
-			start := bv.ByteOffset
-			end := start + bv.ByteLength
-			decoded, _, err := image.Decode(bytes.NewReader(buf.Data[start:end]))
-			if err == nil {
-				return decoded
-			}
+		if buf.Data == nil {
+			return nil, errors.New("buffer not loaded")
 		}
-	} else if img.URI != "" {
+		data = buf.Data[bv.ByteOffset : bv.ByteOffset+bv.ByteLength]
+	case img.URI != "":
 		// External image file
-		dir := filepath.Dir(basePath)
-		imgPath := filepath.Join(dir, img.URI)
-		data, err := os.ReadFile(imgPath)
-		if err == nil {
-			decoded, _, err := image.Decode(bytes.NewReader(data))
-			if err == nil {
-				return decoded
-			}
+		var err error
+		data, err = fs.ReadFile(fsys, img.URI)
+		if err != nil {
+			return nil, err
 		}
+	default:
+		return nil, errors.New("no image data")
 	}
-	return nil
+	decoded, err := decodeImage(data)
+	if errors.Is(err, ErrUnsupportedTexture) && img.MimeType != "" {
+		return nil, fmt.Errorf("%s: %w", img.MimeType, err)
+	}
+	return decoded, err
+}
+
+// decodeImage decodes PNG, JPEG, WebP, or KTX2 data.
+func decodeImage(data []byte) (image.Image, error) {
+	if isKTX2(data) {
+		return decodeKTX2(data)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrUnsupportedTexture
+	}
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
 }
 
 // readVec3Accessor reads Vec3 data from a GLTF accessor.
@@ -432,20 +710,20 @@ func readVec3Accessor(doc *gltf.Document, accessorIdx int) ([]math3d.Vec3, error
 	if accessor.Type != gltf.AccessorVec3 {
 		return nil, fmt.Errorf("expected VEC3, got %v", accessor.Type)
 	}
+	if accessor.ComponentType != gltf.ComponentFloat {
+		return nil, fmt.Errorf("unsupported VEC3 component type: %v", accessor.ComponentType)
+	}
 
-	data, err := readAccessorData(doc, accessor)
+	data, start, stride, err := accessorBytes(doc, accessor, 12)
 	if err != nil {
 		return nil, err
 	}
 
-	floats, ok := data.([][3]float32)
-	if !ok {
-		return nil, fmt.Errorf("unexpected data type for VEC3")
-	}
-
-	result := make([]math3d.Vec3, len(floats))
-	for i, f := range floats {
-		result[i] = math3d.V3(float64(f[0]), float64(f[1]), float64(f[2]))
+	// Decode straight from the buffer, no intermediate float32 slice
+	result := make([]math3d.Vec3, accessor.Count)
+	for i := range result {
+		b := data[start+i*stride:]
+		result[i] = math3d.V3(float64(readFloat32(b)), float64(readFloat32(b[4:])), float64(readFloat32(b[8:])))
 	}
 
 	return result, nil
@@ -457,20 +735,19 @@ func readVec2Accessor(doc *gltf.Document, accessorIdx int) ([]math3d.Vec2, error
 	if accessor.Type != gltf.AccessorVec2 {
 		return nil, fmt.Errorf("expected VEC2, got %v", accessor.Type)
 	}
+	if accessor.ComponentType != gltf.ComponentFloat {
+		return nil, fmt.Errorf("unsupported VEC2 component type: %v", accessor.ComponentType)
+	}
 
-	data, err := readAccessorData(doc, accessor)
+	data, start, stride, err := accessorBytes(doc, accessor, 8)
 	if err != nil {
 		return nil, err
 	}
 
-	floats, ok := data.([][2]float32)
-	if !ok {
-		return nil, fmt.Errorf("unexpected data type for VEC2")
-	}
-
-	result := make([]math3d.Vec2, len(floats))
-	for i, f := range floats {
-		result[i] = math3d.V2(float64(f[0]), float64(f[1]))
+	result := make([]math3d.Vec2, accessor.Count)
+	for i := range result {
+		b := data[start+i*stride:]
+		result[i] = math3d.V2(float64(readFloat32(b)), float64(readFloat32(b[4:])))
 	}
 
 	return result, nil
@@ -479,132 +756,74 @@ func readVec2Accessor(doc *gltf.Document, accessorIdx int) ([]math3d.Vec2, error
 // readIndices reads index data from a GLTF accessor.
 func readIndices(doc *gltf.Document, accessorIdx int) ([]int, error) {
 	accessor := doc.Accessors[accessorIdx]
+	if accessor.Type != gltf.AccessorScalar {
+		return nil, fmt.Errorf("expected SCALAR indices, got %v", accessor.Type)
+	}
+
+	var size int
+	switch accessor.ComponentType {
+	case gltf.ComponentUbyte:
+		size = 1
+	case gltf.ComponentUshort:
+		size = 2
+	case gltf.ComponentUint:
+		size = 4
+	default:
+		return nil, fmt.Errorf("unexpected index type: %v", accessor.ComponentType)
+	}
 
-	data, err := readAccessorData(doc, accessor)
+	data, start, stride, err := accessorBytes(doc, accessor, size)
 	if err != nil {
 		return nil, err
 	}
 
-	switch v := data.(type) {
-	case []uint8:
-		result := make([]int, len(v))
-		for i, x := range v {
-			result[i] = int(x)
-		}
-		return result, nil
-	case []uint16:
-		result := make([]int, len(v))
-		for i, x := range v {
-			result[i] = int(x)
-		}
-		return result, nil
-	case []uint32:
-		result := make([]int, len(v))
-		for i, x := range v {
-			result[i] = int(x)
+	result := make([]int, accessor.Count)
+	for i := range result {
+		b := data[start+i*stride:]
+		switch size {
+		case 1:
+			result[i] = int(b[0])
+		case 2:
+			result[i] = int(uint16(b[0]) | uint16(b[1])<<8)
+		case 4:
+			result[i] = int(uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24)
 		}
-		return result, nil
-	default:
-		return nil, fmt.Errorf("unexpected index type: %T", data)
 	}
+
+	return result, nil
 }
 
-// readAccessorData reads raw data from a GLTF accessor.
-func readAccessorData(doc *gltf.Document, accessor *gltf.Accessor) (any, error) {
+// accessorBytes returns the buffer backing an accessor along with the offset
+// of its first element and the distance between elements of elemSize bytes.
+// The buffer is the decoder's own, not a copy. Bounds are checked so a
+// malformed file fails to load instead of panicking.
+func accessorBytes(doc *gltf.Document, accessor *gltf.Accessor, elemSize int) (data []byte, start, stride int, err error) {
 	if accessor.BufferView == nil {
-		return nil, fmt.Errorf("accessor has no buffer view")
+		return nil, 0, 0, fmt.Errorf("accessor has no buffer view")
 	}
 
 	bufferView := doc.BufferViews[*accessor.BufferView]
 	buffer := doc.Buffers[bufferView.Buffer]
 
-	// Get buffer data
-	var bufData []byte
-	if buffer.URI == "" {
-		// Embedded data (GLB)
-		bufData = buffer.Data
-	} else {
-		// External file - need to load relative to document
-		return nil, fmt.Errorf("external buffers not supported yet")
+	// The decoder loads embedded (GLB and data URI) and external buffers alike
+	if buffer.Data == nil {
+		return nil, 0, 0, fmt.Errorf("buffer has no data")
 	}
 
-	if bufData == nil {
-		return nil, fmt.Errorf("buffer has no data")
+	start = bufferView.ByteOffset + accessor.ByteOffset
+	stride = bufferView.ByteStride
+	if stride == 0 {
+		stride = elemSize
 	}
 
-	// Calculate data bounds
-	start := bufferView.ByteOffset + accessor.ByteOffset
-	stride := bufferView.ByteStride
-	count := accessor.Count
-
-	// Read based on component type and accessor type
-	switch accessor.Type {
-	case gltf.AccessorVec3:
-		if stride == 0 {
-			stride = 12 // 3 floats * 4 bytes
-		}
-		result := make([][3]float32, count)
-		for i := range count {
-			offset := start + i*stride
-			for j := range 3 {
-				result[i][j] = readFloat32(bufData[offset+j*4:])
-			}
-		}
-		return result, nil
-
-	case gltf.AccessorVec2:
-		if stride == 0 {
-			stride = 8 // 2 floats * 4 bytes
-		}
-		result := make([][2]float32, count)
-		for i := range count {
-			offset := start + i*stride
-			for j := range 2 {
-				result[i][j] = readFloat32(bufData[offset+j*4:])
-			}
-		}
-		return result, nil
-
-	case gltf.AccessorScalar:
-		if stride == 0 {
-			switch accessor.ComponentType {
-			case gltf.ComponentUbyte:
-				stride = 1
-			case gltf.ComponentUshort:
-				stride = 2
-			case gltf.ComponentUint:
-				stride = 4
-			}
-		}
-
-		switch accessor.ComponentType {
-		case gltf.ComponentUbyte:
-			result := make([]uint8, count)
-			for i := range count {
-				result[i] = bufData[start+i*stride]
-			}
-			return result, nil
-		case gltf.ComponentUshort:
-			result := make([]uint16, count)
-			for i := range count {
-				offset := start + i*stride
-				result[i] = uint16(bufData[offset]) | uint16(bufData[offset+1])<<8
-			}
-			return result, nil
-		case gltf.ComponentUint:
-			result := make([]uint32, count)
-			for i := range count {
-				offset := start + i*stride
-				result[i] = uint32(bufData[offset]) |
-					uint32(bufData[offset+1])<<8 |
-					uint32(bufData[offset+2])<<16 |
-					uint32(bufData[offset+3])<<24
-			}
-			return result, nil
+	if accessor.Count > 0 {
+		end := start + (accessor.Count-1)*stride + elemSize
+		if end > bufferView.ByteOffset+bufferView.ByteLength || end > len(buffer.Data) {
+			return nil, 0, 0, fmt.Errorf("accessor runs past end of buffer")
 		}
 	}
 
-	return nil, fmt.Errorf("unsupported accessor type: %v / %v", accessor.Type, accessor.ComponentType)
+	return buffer.Data, start, stride, nil
 }
 
 // readFloat32 reads a little-endian float32.
@@ -621,13 +840,13 @@ func float32frombits(b uint32) float32 {
 // LoadGLTFWithTextures loads a GLTF file and extracts embedded textures.
 // Returns the mesh and a map of image index to texture data.
 func LoadGLTFWithTextures(path string) (*Mesh, map[int][]byte, error) {
-	doc, err := gltf.Open(path)
+	loader := NewGLTFLoader()
+	doc, fsys, err := loader.open(context.Background(), path)
 	if err != nil {
-		return nil, nil, fmt.Errorf("open gltf: %w", err)
+		return nil, nil, err
 	}
 
-	loader := NewGLTFLoader()
-	mesh, err := loader.Load(path)
+	mesh, err := loader.buildMesh(context.Background(), doc, filepath.Base(path), loader.images(doc, fsys))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -657,25 +876,79 @@ func LoadGLTFWithTextures(path string) (*Mesh, map[int][]byte, error) {
 	return mesh, textures, nil
 }
 
-// LoadGLBWithTexture loads a GLB file and returns the mesh plus the first embedded texture.
+// LoadGLBWithTexture loads a GLB file and returns the mesh plus its main texture.
 // Returns (mesh, texture image, error). Texture may be nil if none embedded.
 func LoadGLBWithTexture(path string) (*Mesh, image.Image, error) {
-	mesh, textures, err := LoadGLTFWithTextures(path)
+	return NewGLTFLoader().LoadWithTextureContext(context.Background(), path)
+}
+
+// LoadWithTextureContext is like LoadGLBWithTexture but uses the loader's
+// options and progress callback, and stops early if ctx is canceled.
+// Only the returned texture is read and decoded: the base color texture of
+// the first textured material, or else the first image that decodes.
+func (l *GLTFLoader) LoadWithTextureContext(ctx context.Context, path string) (*Mesh, image.Image, error) {
+	doc, fsys, err := l.open(ctx, path)
 	if err != nil {
 		return nil, nil, err
 	}
+	return l.withTexture(ctx, doc, filepath.Base(path), l.images(doc, fsys))
+}
 
-	// Find the first texture
-	var textureImg image.Image
-	for _, data := range textures {
-		if len(data) > 0 {
-			img, _, err := image.Decode(bytes.NewReader(data))
-			if err == nil {
-				textureImg = img
-				break
+// LoadBytesWithTexture is like LoadWithTextureContext but decodes a GLTF
+// or GLB document held in data, naming the mesh name. External buffers and
+// images are read from fsys; if it's nil, the document must embed them.
+func (l *GLTFLoader) LoadBytesWithTexture(ctx context.Context, data []byte, name string, fsys fs.FS) (*Mesh, image.Image, error) {
+	if fsys == nil {
+		fsys = noFS{}
+	}
+	doc, err := l.decode(ctx, bytes.NewReader(data), int64(len(data)), fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l.withTexture(ctx, doc, name, l.images(doc, fsys))
+}
+
+// withTexture builds the mesh for a decoded document and picks its texture.
+func (l *GLTFLoader) withTexture(ctx context.Context, doc *gltf.Document, name string, images *gltfImages) (*Mesh, image.Image, error) {
+	mesh, err := l.buildMesh(ctx, doc, name, images)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ambient occlusion comes from the first material with a map that loads
+	for i, mat := range doc.Materials {
+		m := &mesh.Materials[i]
+		if m.HasOcclusion && m.OcclusionMap == nil {
+			if imgIdx, ok := occlusionImage(doc, mat); ok {
+				m.OcclusionMap = images.load(imgIdx)
+			}
+		}
+		if m.OcclusionMap != nil {
+			mesh.OcclusionUVSet = m.OcclusionUV
+			break
+		}
+	}
+
+	// The renderer samples the texture with the UV set its material asks for
+	for i, mat := range doc.Materials {
+		mesh.TextureUVSet = mesh.Materials[i].BaseMapUV
+		mesh.TextureWrap = mesh.Materials[i].BaseMapWrap
+		if mesh.Materials[i].BaseMap != nil {
+			return mesh, mesh.Materials[i].BaseMap, nil
+		}
+		if imgIdx, ok := baseColorImage(doc, mat); ok {
+			if img := images.load(imgIdx); img != nil {
+				return mesh, img, nil
 			}
 		}
 	}
+	mesh.TextureUVSet = 0
+	mesh.TextureWrap = [2]TextureWrap{}
+	for i := range doc.Images {
+		if decoded := images.load(i); decoded != nil {
+			return mesh, decoded, nil
+		}
+	}
 
-	return mesh, textureImg, nil
+	return mesh, nil, nil
 }
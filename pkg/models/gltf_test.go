@@ -1,9 +1,45 @@
 package models
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+	"github.com/taigrr/trophy/pkg/math3d"
 )
 
+// writeTestGLB saves a GLB with one triangle mesh instanced by n nodes.
+func writeTestGLB(t *testing.T, n int) string {
+	t.Helper()
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	doc.Meshes = []*gltf.Mesh{{
+		Primitives: []*gltf.Primitive{{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos}}},
+	}}
+	for i := range n {
+		doc.Nodes = append(doc.Nodes, &gltf.Node{Mesh: gltf.Index(0), Translation: [3]float64{float64(i), 0, 0}})
+		doc.Scenes[0].Nodes = append(doc.Scenes[0].Nodes, i)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatalf("save glb: %v", err)
+	}
+	return path
+}
+
 func TestLoadGLBInvalidPath(t *testing.T) {
 	_, err := LoadGLB("/nonexistent/path.glb")
 	if err == nil {
@@ -11,6 +47,34 @@ func TestLoadGLBInvalidPath(t *testing.T) {
 	}
 }
 
+func TestLoadNotGLTF(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string // In the error message
+	}{
+		{"html", "<!DOCTYPE html>\n<html><body>404</body></html>", "HTML"},
+		{"obj", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\n", "OBJ"},
+		{"text", "Access denied\n", "looks like text"},
+		{"lfs pointer", lfsPointer, "git lfs pull"},
+		{"binary", "\x00\x01\x02\x03", "binary"},
+		{"empty", "", "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model.glb")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadGLB(path)
+			if !errors.Is(err, ErrNotGLTF) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadGLB() error = %v, want ErrNotGLTF mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestGLTFLoaderCreation(t *testing.T) {
 	loader := NewGLTFLoader()
 	if loader == nil {
@@ -24,3 +88,651 @@ func TestGLTFLoaderCreation(t *testing.T) {
 		t.Error("SmoothNormals should default to true")
 	}
 }
+
+// writeTexturedGLB saves a GLB with one triangle whose material uses an
+// embedded 4x2 PNG, plus a second unused 2x2 image.
+func writeTexturedGLB(t *testing.T) string {
+	t.Helper()
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+
+	// The unused image comes first so picking "the first image" would be wrong
+	for _, size := range [][2]int{{2, 2}, {4, 2}} {
+		var buf bytes.Buffer
+		img := image.NewRGBA(image.Rect(0, 0, size[0], size[1]))
+		img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := modeler.WriteImage(doc, "tex", "image/png", &buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc.Textures = []*gltf.Texture{{Source: gltf.Index(1)}}
+	doc.Materials = []*gltf.Material{{
+		PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0}},
+	}}
+	doc.Meshes = []*gltf.Mesh{{
+		Primitives: []*gltf.Primitive{{
+			Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos},
+			Material:   gltf.Index(0),
+		}},
+	}}
+	doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+	doc.Scenes[0].Nodes = []int{0}
+
+	path := filepath.Join(t.TempDir(), "textured.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatalf("save glb: %v", err)
+	}
+	return path
+}
+
+func TestLoadWithTextureContext(t *testing.T) {
+	path := writeTexturedGLB(t)
+
+	for _, lazy := range []bool{false, true} {
+		loader := NewGLTFLoader()
+		loader.LazyTextures = lazy
+
+		mesh, img, err := loader.LoadWithTextureContext(context.Background(), path)
+		if err != nil {
+			t.Fatalf("lazy=%v: %v", lazy, err)
+		}
+		if img == nil || img.Bounds().Dx() != 4 {
+			t.Errorf("lazy=%v: expected the material's 4x2 texture, got %v", lazy, img)
+		}
+
+		mat := mesh.GetMaterial(0)
+		if !mat.HasTexture {
+			t.Errorf("lazy=%v: material should report HasTexture", lazy)
+		}
+		if lazy && mat.BaseMap != nil {
+			t.Error("lazy load should not decode material BaseMap")
+		}
+		if !lazy && mat.BaseMap == nil {
+			t.Error("eager load should decode material BaseMap")
+		}
+	}
+}
+
+func TestLoadBytesWithTexture(t *testing.T) {
+	data, err := os.ReadFile(writeTexturedGLB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mesh, img, err := NewGLTFLoader().LoadBytesWithTexture(context.Background(), data, "stdin", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mesh.Name != "stdin" || mesh.TriangleCount() == 0 {
+		t.Errorf("mesh %q has %d triangles, want a named, non-empty mesh", mesh.Name, mesh.TriangleCount())
+	}
+	if img == nil || img.Bounds().Dx() != 4 {
+		t.Errorf("expected the embedded 4x2 texture, got %v", img)
+	}
+}
+
+func TestLoadSecondUVSet(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	uv0 := modeler.WriteTextureCoord(doc, [][2]float32{{0, 0}, {0, 0}, {0, 0}})
+	uv1 := modeler.WriteTextureCoord(doc, [][2]float32{{0, 0}, {1, 0}, {0, 1}})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := modeler.WriteImage(doc, "lightmap", "image/png", &buf); err != nil {
+		t.Fatal(err)
+	}
+	doc.Textures = []*gltf.Texture{{Source: gltf.Index(0)}}
+	doc.Materials = []*gltf.Material{{
+		PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0, TexCoord: 1}},
+	}}
+	doc.Meshes = []*gltf.Mesh{{
+		Primitives: []*gltf.Primitive{{
+			Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos, gltf.TEXCOORD_0: uv0, gltf.TEXCOORD_1: uv1},
+			Material:   gltf.Index(0),
+		}},
+	}}
+	doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+	doc.Scenes[0].Nodes = []int{0}
+	path := filepath.Join(t.TempDir(), "uv1.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatalf("save glb: %v", err)
+	}
+
+	mesh, img, err := LoadGLBWithTexture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img == nil {
+		t.Fatal("expected the base color texture")
+	}
+	if mesh.Materials[0].BaseMapUV != 1 || mesh.TextureUVSet != 1 {
+		t.Errorf("BaseMapUV = %d, TextureUVSet = %d; want 1, 1", mesh.Materials[0].BaseMapUV, mesh.TextureUVSet)
+	}
+
+	// V is flipped like the first set, and renderers get the second set
+	want := map[math3d.Vec2]bool{math3d.V2(0, 1): true, math3d.V2(1, 1): true, math3d.V2(0, 0): true}
+	for i, v := range mesh.Vertices {
+		if v.UV != math3d.V2(0, 1) {
+			t.Errorf("vertex %d UV = %v, want (0, 1)", i, v.UV)
+		}
+		if !want[v.UV1] {
+			t.Errorf("vertex %d UV1 = %v, want one of %v", i, v.UV1, want)
+		}
+		if _, _, uv := mesh.GetVertex(i); uv != v.UV1 {
+			t.Errorf("GetVertex(%d) uv = %v, want UV1 %v", i, uv, v.UV1)
+		}
+	}
+}
+
+func TestLoadSamplerWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampler *gltf.Sampler
+		want    [2]TextureWrap
+	}{
+		{"no sampler", nil, [2]TextureWrap{WrapRepeat, WrapRepeat}},
+		{"default", &gltf.Sampler{}, [2]TextureWrap{WrapRepeat, WrapRepeat}},
+		{"mirror and clamp", &gltf.Sampler{WrapS: gltf.WrapMirroredRepeat, WrapT: gltf.WrapClampToEdge}, [2]TextureWrap{WrapMirror, WrapClamp}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+			uv := modeler.WriteTextureCoord(doc, [][2]float32{{0, 0}, {4, 0}, {0, 4}})
+
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := modeler.WriteImage(doc, "tiles", "image/png", &buf); err != nil {
+				t.Fatal(err)
+			}
+			doc.Textures = []*gltf.Texture{{Source: gltf.Index(0)}}
+			if tt.sampler != nil {
+				doc.Samplers = []*gltf.Sampler{tt.sampler}
+				doc.Textures[0].Sampler = gltf.Index(0)
+			}
+			doc.Materials = []*gltf.Material{{
+				PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0}},
+			}}
+			doc.Meshes = []*gltf.Mesh{{
+				Primitives: []*gltf.Primitive{{
+					Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos, gltf.TEXCOORD_0: uv},
+					Material:   gltf.Index(0),
+				}},
+			}}
+			doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+			doc.Scenes[0].Nodes = []int{0}
+			path := filepath.Join(t.TempDir(), "tiles.glb")
+			if err := gltf.SaveBinary(doc, path); err != nil {
+				t.Fatalf("save glb: %v", err)
+			}
+
+			mesh, _, err := LoadGLBWithTexture(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mesh.Materials[0].BaseMapWrap != tt.want || mesh.TextureWrap != tt.want {
+				t.Errorf("BaseMapWrap = %v, TextureWrap = %v; want %v", mesh.Materials[0].BaseMapWrap, mesh.TextureWrap, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadOcclusionMap(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	uv0 := modeler.WriteTextureCoord(doc, [][2]float32{{0, 0}, {1, 0}, {0, 1}})
+	uv1 := modeler.WriteTextureCoord(doc, [][2]float32{{0.5, 0.5}, {0.5, 0.5}, {0.5, 0.5}})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 4))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := modeler.WriteImage(doc, "ao", "image/png", &buf); err != nil {
+		t.Fatal(err)
+	}
+	doc.Textures = []*gltf.Texture{{Source: gltf.Index(0)}}
+	doc.Materials = []*gltf.Material{{
+		OcclusionTexture: &gltf.OcclusionTexture{Index: gltf.Index(0), TexCoord: 1, Strength: gltf.Float(0.5)},
+	}}
+	doc.Meshes = []*gltf.Mesh{{
+		Primitives: []*gltf.Primitive{{
+			Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos, gltf.TEXCOORD_0: uv0, gltf.TEXCOORD_1: uv1},
+			Material:   gltf.Index(0),
+		}},
+	}}
+	doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+	doc.Scenes[0].Nodes = []int{0}
+	path := filepath.Join(t.TempDir(), "ao.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatalf("save glb: %v", err)
+	}
+
+	for _, lazy := range []bool{false, true} {
+		loader := NewGLTFLoader()
+		loader.LazyTextures = lazy
+		mesh, _, err := loader.LoadWithTextureContext(context.Background(), path)
+		if err != nil {
+			t.Fatalf("lazy=%v: %v", lazy, err)
+		}
+
+		mat := mesh.Materials[0]
+		if !mat.HasOcclusion || mat.OcclusionUV != 1 || mat.OcclusionStrength != 0.5 {
+			t.Errorf("lazy=%v: occlusion = %v, UV set %d, strength %v; want true, 1, 0.5",
+				lazy, mat.HasOcclusion, mat.OcclusionUV, mat.OcclusionStrength)
+		}
+		img, strength := mesh.OcclusionMap()
+		if img == nil || img.Bounds().Dx() != 8 || strength != 0.5 {
+			t.Errorf("lazy=%v: OcclusionMap() = %v, %v; want the 8x4 map, 0.5", lazy, img, strength)
+		}
+		if mesh.OcclusionUVSet != 1 {
+			t.Errorf("lazy=%v: OcclusionUVSet = %d, want 1", lazy, mesh.OcclusionUVSet)
+		}
+		for i := range mesh.Vertices {
+			if uv := mesh.GetOcclusionUV(i); uv != math3d.V2(0.5, 0.5) {
+				t.Errorf("lazy=%v: GetOcclusionUV(%d) = %v, want (0.5, 0.5)", lazy, i, uv)
+			}
+		}
+	}
+}
+
+func TestLoadKTX2Texture(t *testing.T) {
+	tests := []struct {
+		name     string
+		ktx2     []byte
+		wantWarn bool
+	}{
+		{"uncompressed", buildKTX2(vkFormatR8G8B8A8Unorm, ktx2SuperNone, 1, 1, []byte{255, 0, 0, 255}), false},
+		{"basis", buildKTX2(vkFormatUndefined, ktx2SuperBasisLZ, 1, 1, nil), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gltf.NewDocument()
+			pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+			if _, err := modeler.WriteImage(doc, "basecolor", "image/ktx2", bytes.NewReader(tt.ktx2)); err != nil {
+				t.Fatal(err)
+			}
+			// KTX2-only textures name their image in the extension, not in source
+			doc.Textures = []*gltf.Texture{{
+				Extensions: gltf.Extensions{"KHR_texture_basisu": json.RawMessage(`{"source":0}`)},
+			}}
+			doc.Materials = []*gltf.Material{{
+				PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0}},
+			}}
+			doc.Meshes = []*gltf.Mesh{{
+				Primitives: []*gltf.Primitive{{
+					Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos},
+					Material:   gltf.Index(0),
+				}},
+			}}
+			doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+			doc.Scenes[0].Nodes = []int{0}
+			path := filepath.Join(t.TempDir(), "ktx2.glb")
+			if err := gltf.SaveBinary(doc, path); err != nil {
+				t.Fatalf("save glb: %v", err)
+			}
+
+			var warnings []error
+			loader := NewGLTFLoader()
+			loader.Warn = func(err error) { warnings = append(warnings, err) }
+			_, img, err := loader.LoadWithTextureContext(context.Background(), path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantWarn {
+				if img != nil {
+					t.Error("expected no texture")
+				}
+				// Warned once, though both the material and the image fallback tried it
+				if len(warnings) != 1 || !errors.Is(warnings[0], ErrUnsupportedTexture) {
+					t.Errorf("warnings = %v, want one ErrUnsupportedTexture", warnings)
+				}
+				return
+			}
+			if img == nil || len(warnings) != 0 {
+				t.Errorf("texture = %v, warnings = %v; want the decoded texture and no warnings", img, warnings)
+			}
+		})
+	}
+}
+
+// webp1x1 is a 1x1 lossless WebP image.
+const webp1x1 = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func TestLoadWebPTexture(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+
+	// A 2x2 PNG fallback, then the 1x1 WebP the extension prefers
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := modeler.WriteImage(doc, "fallback", "image/png", &buf); err != nil {
+		t.Fatal(err)
+	}
+	webp, err := base64.StdEncoding.DecodeString(webp1x1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := modeler.WriteImage(doc, "webp", "image/webp", bytes.NewReader(webp)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := modeler.WriteImage(doc, "bogus", "image/avif", bytes.NewReader([]byte("not an image"))); err != nil {
+		t.Fatal(err)
+	}
+	doc.Textures = []*gltf.Texture{{
+		Source:     gltf.Index(0),
+		Extensions: gltf.Extensions{"EXT_texture_webp": json.RawMessage(`{"source":1}`)},
+	}}
+	doc.Materials = []*gltf.Material{{
+		PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0}},
+	}}
+	doc.Meshes = []*gltf.Mesh{{
+		Primitives: []*gltf.Primitive{{
+			Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos},
+			Material:   gltf.Index(0),
+		}},
+	}}
+	doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+	doc.Scenes[0].Nodes = []int{0}
+	path := filepath.Join(t.TempDir(), "webp.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatalf("save glb: %v", err)
+	}
+
+	_, img, err := LoadGLBWithTexture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img == nil || img.Bounds().Dx() != 1 {
+		t.Errorf("texture = %v, want the 1x1 WebP", img)
+	}
+
+	// Formats nothing can decode name their MIME type
+	images := NewGLTFLoader().images(doc, os.DirFS(filepath.Dir(path)))
+	var warned error
+	images.warn = func(err error) { warned = err }
+	if images.load(2) != nil || !errors.Is(warned, ErrUnsupportedTexture) || !strings.Contains(warned.Error(), "image/avif") {
+		t.Errorf("warning = %v, want ErrUnsupportedTexture naming image/avif", warned)
+	}
+}
+
+func TestAccessorOutOfBounds(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	doc.Accessors[pos].Count = 4
+
+	if _, err := readVec3Accessor(doc, pos); err == nil {
+		t.Error("expected error reading past end of buffer")
+	}
+}
+
+func TestLoadGLTFBadAccessor(t *testing.T) {
+	doc := gltf.NewDocument()
+	pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+	doc.Accessors[pos].Count = 4
+	doc.Meshes = []*gltf.Mesh{{
+		Name:       "broken",
+		Primitives: []*gltf.Primitive{{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos}}},
+	}}
+	doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+	doc.Scenes[0].Nodes = []int{0}
+	path := filepath.Join(t.TempDir(), "broken.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatalf("save glb: %v", err)
+	}
+
+	if _, err := LoadGLB(path); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("error = %v, want one naming the broken mesh", err)
+	}
+}
+
+func TestLoadContextProgress(t *testing.T) {
+	path := writeTestGLB(t, 3)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last LoadProgress
+	var bytesRead int64
+	loader := NewGLTFLoader()
+	loader.Progress = func(p LoadProgress) {
+		if p.BytesTotal > 0 {
+			bytesRead = p.BytesRead
+		}
+		last = p
+	}
+
+	mesh, err := loader.LoadContext(context.Background(), path)
+	if err != nil {
+		t.Fatalf("LoadContext: %v", err)
+	}
+	if mesh.TriangleCount() != 3 {
+		t.Errorf("TriangleCount = %d, want 3", mesh.TriangleCount())
+	}
+	if bytesRead != info.Size() {
+		t.Errorf("BytesRead = %d, want %d", bytesRead, info.Size())
+	}
+	if last.MeshesDone != 3 || last.MeshesTotal != 3 {
+		t.Errorf("final progress = %d/%d meshes, want 3/3", last.MeshesDone, last.MeshesTotal)
+	}
+}
+
+func TestLoadContextCanceled(t *testing.T) {
+	path := writeTestGLB(t, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewGLTFLoader().LoadContext(ctx, path)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadContext error = %v, want context.Canceled", err)
+	}
+
+	// Cancel after the file is read, during mesh processing
+	ctx, cancel = context.WithCancel(context.Background())
+	loader := NewGLTFLoader()
+	loader.Progress = func(p LoadProgress) {
+		if p.MeshesDone > 0 {
+			cancel()
+		}
+	}
+	path = writeTestGLB(t, 2)
+	if _, err := loader.LoadContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadContext error = %v, want context.Canceled", err)
+	}
+}
+
+func TestAppendPrimitive(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       gltf.PrimitiveMode
+		indices    []int
+		wantFaces  int
+		wantLines  [][2]int
+		wantPoints []int
+	}{
+		{"triangles", gltf.PrimitiveTriangles, []int{0, 1, 2, 2, 1, 3}, 2, nil, nil},
+		{"triangle strip", gltf.PrimitiveTriangleStrip, []int{0, 1, 2, 3, 4}, 3, nil, nil},
+		{"degenerate strip", gltf.PrimitiveTriangleStrip, []int{0, 1, 2, 2, 3, 4}, 2, nil, nil},
+		{"triangle fan", gltf.PrimitiveTriangleFan, []int{0, 1, 2, 3}, 2, nil, nil},
+		{"lines", gltf.PrimitiveLines, []int{0, 1, 2, 3, 4}, 0, [][2]int{{10, 11}, {12, 13}}, nil},
+		{"line strip", gltf.PrimitiveLineStrip, []int{0, 1, 2}, 0, [][2]int{{10, 11}, {11, 12}}, nil},
+		{"line loop", gltf.PrimitiveLineLoop, []int{0, 1, 2}, 0, [][2]int{{10, 11}, {11, 12}, {12, 10}}, nil},
+		{"points", gltf.PrimitivePoints, []int{0, 2}, 0, nil, []int{10, 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := NewMesh("test")
+			appendPrimitive(mesh, tt.mode, tt.indices, 10, -1)
+
+			if len(mesh.Faces) != tt.wantFaces {
+				t.Errorf("faces = %d, want %d", len(mesh.Faces), tt.wantFaces)
+			}
+			if !slices.Equal(mesh.Lines, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", mesh.Lines, tt.wantLines)
+			}
+			if !slices.Equal(mesh.Points, tt.wantPoints) {
+				t.Errorf("points = %v, want %v", mesh.Points, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestAppendPrimitiveWinding(t *testing.T) {
+	// A unit quad whose glTF triangles face +Z (CCW seen from +Z)
+	quad := []math3d.Vec3{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	tests := []struct {
+		name    string
+		mode    gltf.PrimitiveMode
+		indices []int
+	}{
+		{"triangles", gltf.PrimitiveTriangles, []int{0, 1, 2, 2, 1, 3}},
+		{"triangle strip", gltf.PrimitiveTriangleStrip, []int{0, 1, 2, 3}},
+		{"triangle fan", gltf.PrimitiveTriangleFan, []int{0, 1, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := NewMesh("test")
+			for _, p := range quad {
+				mesh.Vertices = append(mesh.Vertices, MeshVertex{Position: p})
+			}
+			appendPrimitive(mesh, tt.mode, tt.indices, 0, -1)
+			if len(mesh.Faces) != 2 {
+				t.Fatalf("faces = %d, want 2", len(mesh.Faces))
+			}
+			// Reversed to CW, every face points down -Z
+			for _, f := range mesh.Faces {
+				a, b, c := quad[f.V[0]], quad[f.V[1]], quad[f.V[2]]
+				if n := b.Sub(a).Cross(c.Sub(a)); n.Z >= 0 {
+					t.Errorf("face %v normal %v, want -Z", f.V, n)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadGLTFGroups(t *testing.T) {
+	mesh, err := LoadGLB(writeTestGLB(t, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Unnamed nodes instancing an unnamed mesh are numbered
+	if want := []string{"node 0", "node 1", "node 2"}; !slices.Equal(mesh.Groups, want) {
+		t.Errorf("groups = %q, want %q", mesh.Groups, want)
+	}
+	if got := mesh.GroupSizes(); !slices.Equal(got, []int{1, 1, 1}) {
+		t.Errorf("group sizes = %v, want one face each", got)
+	}
+}
+
+func TestLoadGLTFFlatNormals(t *testing.T) {
+	// Two primitives folded along a shared edge, each with its own copy of
+	// the edge's vertices
+	doc := gltf.NewDocument()
+	edge := [][3]float32{{0, 0, 0}, {0, 1, 0}}
+	doc.Meshes = []*gltf.Mesh{{Primitives: []*gltf.Primitive{
+		{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: modeler.WritePosition(doc, append(slices.Clone(edge), [3]float32{1, 0, 0}))}},
+		{Attributes: gltf.PrimitiveAttributes{gltf.POSITION: modeler.WritePosition(doc, append(slices.Clone(edge), [3]float32{-1, 0, 1}))}},
+	}}}
+	doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+	doc.Scenes[0].Nodes = []int{0}
+	path := filepath.Join(t.TempDir(), "fold.glb")
+	if err := gltf.SaveBinary(doc, path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, smooth := range []bool{false, true} {
+		loader := NewGLTFLoader()
+		loader.SmoothNormals = smooth
+		mesh, err := loader.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		flat := true
+		for _, f := range mesh.Faces {
+			a, b, c := mesh.Vertices[f.V[0]].Position, mesh.Vertices[f.V[1]].Position, mesh.Vertices[f.V[2]].Position
+			n := b.Sub(a).Cross(c.Sub(a)).Normalize()
+			for _, vi := range f.V {
+				if mesh.Vertices[vi].Normal.Dot(n) < 0.999 {
+					flat = false
+				}
+			}
+		}
+		if flat == smooth {
+			t.Errorf("SmoothNormals %v: normals flat = %v", smooth, flat)
+		}
+	}
+}
+
+func TestLoadGLTFExternalImage(t *testing.T) {
+	// Files beside the model are readable; files outside its directory aren't
+	tests := []struct {
+		uri  string
+		want bool
+	}{
+		{"tex.png", true},
+		{"textures/tex.png", true},
+		{"../tex.png", false},
+		{"/tex.png", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, "model")
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range []string{root, dir, filepath.Join(dir, "textures")} {
+				if err := os.MkdirAll(p, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(p, "tex.png"), buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			doc := gltf.NewDocument()
+			pos := modeler.WritePosition(doc, [][3]float32{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}})
+			doc.Images = []*gltf.Image{{URI: tt.uri, MimeType: "image/png"}}
+			doc.Textures = []*gltf.Texture{{Source: gltf.Index(0)}}
+			doc.Materials = []*gltf.Material{{
+				PBRMetallicRoughness: &gltf.PBRMetallicRoughness{BaseColorTexture: &gltf.TextureInfo{Index: 0}},
+			}}
+			doc.Meshes = []*gltf.Mesh{{
+				Primitives: []*gltf.Primitive{{
+					Attributes: gltf.PrimitiveAttributes{gltf.POSITION: pos},
+					Material:   gltf.Index(0),
+				}},
+			}}
+			doc.Nodes = []*gltf.Node{{Mesh: gltf.Index(0)}}
+			doc.Scenes[0].Nodes = []int{0}
+			path := filepath.Join(dir, "model.glb")
+			if err := gltf.SaveBinary(doc, path); err != nil {
+				t.Fatalf("save glb: %v", err)
+			}
+
+			_, img, err := LoadGLBWithTexture(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := img != nil; got != tt.want {
+				t.Errorf("texture loaded = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import "github.com/taigrr/trophy/pkg/math3d"
+
+// groupIndex returns the index of the named group, adding it if needed.
+func (m *Mesh) groupIndex(name string) int {
+	for i, g := range m.Groups {
+		if g == name {
+			return i
+		}
+	}
+	m.Groups = append(m.Groups, name)
+	return len(m.Groups) - 1
+}
+
+// GroupSizes returns how many faces each of Groups holds.
+func (m *Mesh) GroupSizes() []int {
+	sizes := make([]int, len(m.Groups))
+	for _, f := range m.Faces {
+		if f.Group >= 0 && f.Group < len(sizes) {
+			sizes[f.Group]++
+		}
+	}
+	return sizes
+}
+
+// FilterGroups returns a copy of the mesh keeping only the faces of groups
+// visible reports true for, or m itself if it keeps them all. The copy
+// shares m's vertices, lines, points, and materials, so treat it as read-only.
+func (m *Mesh) FilterGroups(visible func(group int) bool) *Mesh {
+	all := true
+	for g := range m.Groups {
+		if !visible(g) {
+			all = false
+			break
+		}
+	}
+	if all {
+		return m
+	}
+
+	filtered := *m
+	filtered.Faces = make([]Face, 0, len(m.Faces))
+	for _, f := range m.Faces {
+		if visible(f.Group) {
+			filtered.Faces = append(filtered.Faces, f)
+		}
+	}
+	return &filtered
+}
+
+// Explode returns a copy of the mesh with each group moved away from the
+// mesh's center by amount times its own center's offset from it, so parts
+// that overlap come apart. Vertices shared between groups are split. A
+// mesh with fewer than two groups, or amount 0, is returned as is.
+func (m *Mesh) Explode(amount float64) *Mesh {
+	if amount == 0 || len(m.Groups) < 2 {
+		return m
+	}
+
+	// Each group's offset, from the center of its bounding box
+	lo := make([]math3d.Vec3, len(m.Groups))
+	hi := make([]math3d.Vec3, len(m.Groups))
+	seen := make([]bool, len(m.Groups))
+	for _, f := range m.Faces {
+		for _, vi := range f.V {
+			p := m.Vertices[vi].Position
+			if !seen[f.Group] {
+				lo[f.Group], hi[f.Group], seen[f.Group] = p, p, true
+			}
+			lo[f.Group], hi[f.Group] = lo[f.Group].Min(p), hi[f.Group].Max(p)
+		}
+	}
+	center := m.Center()
+	offsets := make([]math3d.Vec3, len(m.Groups))
+	for g := range offsets {
+		offsets[g] = lo[g].Add(hi[g]).Scale(0.5).Sub(center).Scale(amount)
+	}
+
+	// Faces get moved copies of their vertices; lines and points belong to
+	// no group, so keep the originals where they are
+	exploded := *m
+	exploded.Vertices = append(make([]MeshVertex, 0, 2*len(m.Vertices)), m.Vertices...)
+	exploded.Faces = make([]Face, len(m.Faces))
+	type key struct{ vertex, group int }
+	moved := make(map[key]int, len(m.Vertices))
+	for i, f := range m.Faces {
+		for j, vi := range f.V {
+			k := key{vi, f.Group}
+			idx, ok := moved[k]
+			if !ok {
+				v := m.Vertices[vi]
+				v.Position = v.Position.Add(offsets[f.Group])
+				idx = len(exploded.Vertices)
+				exploded.Vertices = append(exploded.Vertices, v)
+				moved[k] = idx
+			}
+			f.V[j] = idx
+		}
+		exploded.Faces[i] = f
+	}
+	exploded.CalculateBounds()
+	return &exploded
+}
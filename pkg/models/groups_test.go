@@ -0,0 +1,94 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestOBJGroups(t *testing.T) {
+	obj := `v 0 0 0
+v 1 0 0
+v 0 1 0
+v 1 1 0
+f 1 2 3
+o body
+f 2 4 3
+g glass pane
+f 1 2 4
+f 1 4 3
+o body
+f 1 3 4
+`
+	mesh, err := NewOBJLoader().Load(strings.NewReader(obj), "car.obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"default", "body", "glass pane"}; !slices.Equal(mesh.Groups, want) {
+		t.Fatalf("groups = %q, want %q", mesh.Groups, want)
+	}
+	if got, want := mesh.GroupSizes(), []int{1, 2, 2}; !slices.Equal(got, want) {
+		t.Errorf("group sizes = %v, want %v", got, want)
+	}
+
+	// Subdivision keeps each face's part
+	sub := mesh.Clone()
+	sub.Subdivide(1)
+	if got, want := sub.GroupSizes(), []int{4, 8, 8}; !slices.Equal(got, want) {
+		t.Errorf("subdivided group sizes = %v, want %v", got, want)
+	}
+}
+
+func TestFilterGroups(t *testing.T) {
+	mesh := NewMesh("test")
+	mesh.Groups = []string{"a", "b"}
+	mesh.Faces = []Face{{Group: 0}, {Group: 1}, {Group: 0}}
+
+	if got := mesh.FilterGroups(func(int) bool { return true }); got != mesh {
+		t.Error("showing every group should return the mesh itself")
+	}
+
+	hidden := mesh.FilterGroups(func(g int) bool { return g != 0 })
+	if len(hidden.Faces) != 1 || hidden.Faces[0].Group != 1 {
+		t.Errorf("faces = %v, want only group 1's", hidden.Faces)
+	}
+	if len(mesh.Faces) != 3 {
+		t.Errorf("filtering changed the original to %d faces", len(mesh.Faces))
+	}
+}
+
+func TestExplode(t *testing.T) {
+	// Two triangles sharing a vertex at the origin, one on each side of it
+	mesh := NewMesh("test")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0)},
+		{Position: math3d.V3(2, 0, 0)},
+		{Position: math3d.V3(2, 1, 0)},
+		{Position: math3d.V3(-2, 0, 0)},
+		{Position: math3d.V3(-2, 1, 0)},
+	}
+	mesh.Faces = []Face{{V: [3]int{0, 1, 2}, Group: 0}, {V: [3]int{0, 3, 4}, Group: 1}}
+	mesh.Groups = []string{"right", "left"}
+	mesh.Points = []int{0}
+	mesh.CalculateBounds()
+
+	if mesh.Explode(0) != mesh {
+		t.Error("Explode(0) should return the mesh itself")
+	}
+
+	exploded := mesh.Explode(1)
+	right := exploded.Vertices[exploded.Faces[0].V[0]].Position
+	left := exploded.Vertices[exploded.Faces[1].V[0]].Position
+	// Group centers are (1, 0.5) and (-1, 0.5) from the mesh center (0, 0.5)
+	if right != math3d.V3(1, 0, 0) || left != math3d.V3(-1, 0, 0) {
+		t.Errorf("shared vertex moved to %v and %v, want (1,0,0) and (-1,0,0)", right, left)
+	}
+	if p := exploded.Vertices[exploded.Points[0]].Position; p != math3d.V3(0, 0, 0) {
+		t.Errorf("point moved to %v, want it left in place", p)
+	}
+	if mesh.Vertices[0].Position != math3d.V3(0, 0, 0) || exploded.BoundsMax.X != 3 {
+		t.Errorf("original changed or bounds not updated: max %v", exploded.BoundsMax)
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+)
+
+// ErrUnsupportedTexture is returned for texture images in a format that
+// can't be decoded, like Basis Universal compressed KTX2.
+var ErrUnsupportedTexture = errors.New("unsupported texture format")
+
+// ktx2Magic starts every KTX 2.0 file.
+var ktx2Magic = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// Vulkan formats decodeKTX2 understands
+const (
+	vkFormatUndefined     = 0 // Basis Universal stores its own format
+	vkFormatR8G8B8Unorm   = 23
+	vkFormatR8G8B8SRGB    = 29
+	vkFormatR8G8B8A8Unorm = 37
+	vkFormatR8G8B8A8SRGB  = 43
+)
+
+// KTX2 supercompression schemes
+const (
+	ktx2SuperNone    = 0
+	ktx2SuperBasisLZ = 1
+	ktx2SuperZstd    = 2
+	ktx2SuperZlib    = 3
+)
+
+// isKTX2 reports whether data is a KTX 2.0 file.
+func isKTX2(data []byte) bool {
+	return bytes.HasPrefix(data, ktx2Magic)
+}
+
+// decodeKTX2 decodes the largest mip level of an uncompressed 8-bit RGB or
+// RGBA KTX 2.0 texture. Basis Universal and other block-compressed textures
+// return an error wrapping ErrUnsupportedTexture.
+func decodeKTX2(data []byte) (image.Image, error) {
+	const headerSize = 80 // Identifier, header, and index, up to the level index
+	if len(data) < headerSize+24 || !isKTX2(data) {
+		return nil, errors.New("ktx2: truncated header")
+	}
+	le := binary.LittleEndian
+	vkFormat := le.Uint32(data[12:])
+	width := int(le.Uint32(data[20:]))
+	height := int(le.Uint32(data[24:]))
+	scheme := le.Uint32(data[44:])
+
+	if vkFormat == vkFormatUndefined {
+		kind := "UASTC"
+		if scheme == ktx2SuperBasisLZ {
+			kind = "ETC1S"
+		}
+		return nil, fmt.Errorf("ktx2: Basis Universal (%s) compression: %w", kind, ErrUnsupportedTexture)
+	}
+
+	var channels int
+	switch vkFormat {
+	case vkFormatR8G8B8Unorm, vkFormatR8G8B8SRGB:
+		channels = 3
+	case vkFormatR8G8B8A8Unorm, vkFormatR8G8B8A8SRGB:
+		channels = 4
+	default:
+		return nil, fmt.Errorf("ktx2: Vulkan format %d: %w", vkFormat, ErrUnsupportedTexture)
+	}
+
+	// Level 0, the full-size image, is the first level index entry
+	offset := le.Uint64(data[headerSize:])
+	length := le.Uint64(data[headerSize+8:])
+	if offset > uint64(len(data)) || length > uint64(len(data))-offset {
+		return nil, errors.New("ktx2: level 0 out of bounds")
+	}
+	level := data[offset : offset+length]
+
+	switch scheme {
+	case ktx2SuperNone:
+	case ktx2SuperZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(level))
+		if err != nil {
+			return nil, fmt.Errorf("ktx2: %w", err)
+		}
+		if level, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("ktx2: %w", err)
+		}
+	case ktx2SuperZstd:
+		return nil, fmt.Errorf("ktx2: Zstandard supercompression: %w", ErrUnsupportedTexture)
+	default:
+		return nil, fmt.Errorf("ktx2: supercompression scheme %d: %w", scheme, ErrUnsupportedTexture)
+	}
+
+	if width <= 0 || height <= 0 || len(level) < width*height*channels {
+		return nil, fmt.Errorf("ktx2: %dx%d level 0 has %d bytes", width, height, len(level))
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := range width * height {
+		px := level[i*channels:]
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = px[0], px[1], px[2], 255
+		if channels == 4 {
+			img.Pix[i*4+3] = px[3]
+		}
+	}
+	return img, nil
+}
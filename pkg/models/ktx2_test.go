@@ -0,0 +1,74 @@
+package models
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"testing"
+)
+
+// buildKTX2 returns a single-level KTX2 file holding level as its image.
+func buildKTX2(vkFormat, scheme uint32, width, height int, level []byte) []byte {
+	le := binary.LittleEndian
+	data := append([]byte{}, ktx2Magic...)
+	for _, v := range []uint32{vkFormat, 1, uint32(width), uint32(height), 0, 0, 1, 1, scheme} {
+		data = le.AppendUint32(data, v)
+	}
+	data = append(data, make([]byte, 32)...) // DFD, KVD, and SGD index, unused
+	offset := uint64(len(data) + 24)
+	data = le.AppendUint64(data, offset)
+	data = le.AppendUint64(data, uint64(len(level)))
+	data = le.AppendUint64(data, uint64(len(level)))
+	return append(data, level...)
+}
+
+func TestDecodeKTX2(t *testing.T) {
+	rgba := []byte{255, 0, 0, 255, 0, 255, 0, 128}
+	rgb := []byte{255, 0, 0, 0, 255, 0}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(rgba)
+	zw.Close()
+
+	tests := []struct {
+		name        string
+		data        []byte
+		unsupported bool
+		wantErr     bool
+	}{
+		{"rgba", buildKTX2(vkFormatR8G8B8A8SRGB, ktx2SuperNone, 2, 1, rgba), false, false},
+		{"rgb", buildKTX2(vkFormatR8G8B8Unorm, ktx2SuperNone, 2, 1, rgb), false, false},
+		{"zlib", buildKTX2(vkFormatR8G8B8A8Unorm, ktx2SuperZlib, 2, 1, compressed.Bytes()), false, false},
+		{"basis etc1s", buildKTX2(vkFormatUndefined, ktx2SuperBasisLZ, 2, 1, nil), true, true},
+		{"basis uastc", buildKTX2(vkFormatUndefined, ktx2SuperZstd, 2, 1, nil), true, true},
+		{"zstd", buildKTX2(vkFormatR8G8B8A8Unorm, ktx2SuperZstd, 2, 1, rgba), true, true},
+		{"truncated level", buildKTX2(vkFormatR8G8B8A8Unorm, ktx2SuperNone, 4, 4, rgba), false, true},
+		{"truncated header", ktx2Magic, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := decodeImage(tt.data)
+			if (err != nil) != tt.wantErr || errors.Is(err, ErrUnsupportedTexture) != tt.unsupported {
+				t.Fatalf("decodeImage() error = %v, want error %v, unsupported %v", err, tt.wantErr, tt.unsupported)
+			}
+			if err != nil {
+				return
+			}
+			if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+				t.Fatalf("bounds = %v, want 2x1", b)
+			}
+			if got := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); got != (color.NRGBA{255, 0, 0, 255}) {
+				t.Errorf("pixel 0 = %v, want opaque red", got)
+			}
+		})
+	}
+}
+
+func TestDecodeImageUnknownFormat(t *testing.T) {
+	if _, err := decodeImage([]byte("not an image")); !errors.Is(err, ErrUnsupportedTexture) {
+		t.Errorf("decodeImage() error = %v, want ErrUnsupportedTexture", err)
+	}
+}
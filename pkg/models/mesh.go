@@ -2,7 +2,10 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"image"
+	"strings"
 
 	"github.com/taigrr/trophy/pkg/math3d"
 )
@@ -12,11 +15,43 @@ type Mesh struct {
 	Name      string
 	Vertices  []MeshVertex
 	Faces     []Face
+	Lines     [][2]int // Line segments as indices into Vertices
+	Points    []int    // Point primitives as indices into Vertices
 	Materials []Material
 
+	// Groups names the parts faces belong to, like glTF nodes or OBJ
+	// objects, indexed by Face.Group. Empty if the file has no parts.
+	Groups []string
+
 	// Bounding box (calculated on load)
 	BoundsMin math3d.Vec3
 	BoundsMax math3d.Vec3
+
+	// Winding is the order front faces list their vertices in
+	Winding Winding
+
+	// TextureUVSet selects the UV set GetVertex hands to renderers, which
+	// sample a single texture: 0 for MeshVertex.UV, 1 for UV1
+	TextureUVSet int
+
+	// TextureWrap is how that texture tiles past the 0-1 UV range, along U
+	// and V
+	TextureWrap [2]TextureWrap
+
+	// OcclusionUVSet selects the UV set GetOcclusionUV returns
+	OcclusionUVSet int
+
+	// ApproxTangents is set when CalculateTangents had to guess some
+	// tangents from geometry alone, for lack of UVs
+	ApproxTangents bool
+
+	// SourceScale is the length, in the source file's units, of one unit
+	// of the mesh's coordinates: 1 as loaded, changed by FitToSize
+	SourceScale float64
+
+	// Bounding sphere, computed by CalculateBounds with the box
+	sphereCenter math3d.Vec3
+	sphereRadius float64
 }
 
 // MeshVertex holds all vertex attributes.
@@ -24,24 +59,58 @@ type MeshVertex struct {
 	Position math3d.Vec3
 	Normal   math3d.Vec3
 	UV       math3d.Vec2
+	UV1      math3d.Vec2 // Second UV set (glTF TEXCOORD_1), often for lightmaps or occlusion
+	Tangent  math3d.Vec4 // XYZ along +U, W the bitangent's handedness (±1); zero until CalculateTangents
 }
 
 // Face represents a triangle face with vertex indices and material reference.
 type Face struct {
-	V        [3]int // Indices into Mesh.Vertices
-	Material int    // Index into Mesh.Materials (-1 for no material)
+	V         [3]int // Indices into Mesh.Vertices
+	Material  int    // Index into Mesh.Materials (-1 for no material)
+	Group     int    // Index into Mesh.Groups
+	Smoothing int    // OBJ smoothing group, whose faces share normals; 0 for none
 }
 
 // Material represents a PBR material from GLTF.
 type Material struct {
-	Name       string
-	BaseColor  [4]float64  // RGBA in 0-1 range
-	Metallic   float64     // 0 = dielectric, 1 = metal
-	Roughness  float64     // 0 = smooth, 1 = rough
-	BaseMap    image.Image // Optional base color texture
-	HasTexture bool
+	Name        string
+	BaseColor   [4]float64     // RGBA in 0-1 range
+	Metallic    float64        // 0 = dielectric, 1 = metal
+	Roughness   float64        // 0 = smooth, 1 = rough
+	BaseMap     image.Image    // Optional base color texture
+	BaseMapUV   int            // UV set BaseMap samples: 0 for MeshVertex.UV, 1 for UV1
+	BaseMapWrap [2]TextureWrap // How BaseMap tiles along U and V
+	HasTexture  bool
+
+	// Ambient occlusion: the map's red channel darkens ambient light,
+	// blended toward none by strength
+	OcclusionMap      image.Image
+	OcclusionUV       int // UV set OcclusionMap samples
+	OcclusionStrength float64
+	HasOcclusion      bool
 }
 
+// TextureWrap is how a texture tiles past the 0-1 UV range along one axis.
+type TextureWrap int
+
+const (
+	WrapRepeat TextureWrap = iota // Tile the texture, the glTF default
+	WrapClamp                     // Stretch the edge texels
+	WrapMirror                    // Tile, flipping every other copy
+)
+
+// Winding is the order, seen from the front, in which faces list their
+// vertices.
+type Winding int
+
+const (
+	WindingCW  Winding = iota // Clockwise, as the renderer expects by default
+	WindingCCW                // Counter-clockwise, as OBJ, STL, and glTF files store faces
+)
+
+// ErrEmptyMesh reports a model with nothing to draw.
+var ErrEmptyMesh = errors.New("model contains no triangles, lines, or points")
+
 // NewMesh creates an empty mesh.
 func NewMesh(name string) *Mesh {
 	return &Mesh{
@@ -50,22 +119,36 @@ func NewMesh(name string) *Mesh {
 		Faces:     make([]Face, 0),
 		BoundsMin: math3d.V3(0, 0, 0),
 		BoundsMax: math3d.V3(0, 0, 0),
+
+		SourceScale: 1,
 	}
 }
 
-// CalculateBounds computes the axis-aligned bounding box.
+// CalculateBounds computes the axis-aligned bounding box, storing it in
+// BoundsMin and BoundsMax, and the sphere BoundingSphere returns. An empty
+// mesh keeps its old bounds.
 func (m *Mesh) CalculateBounds() {
 	if len(m.Vertices) == 0 {
 		return
 	}
+	m.BoundsMin, m.BoundsMax = m.ComputeBounds()
+	m.sphereCenter, m.sphereRadius = m.computeSphere()
+}
 
-	m.BoundsMin = m.Vertices[0].Position
-	m.BoundsMax = m.Vertices[0].Position
+// ComputeBounds returns the axis-aligned bounding box of the vertices,
+// zero for an empty mesh. Unlike CalculateBounds it changes nothing, so
+// it's safe to call from several goroutines at once.
+func (m *Mesh) ComputeBounds() (min, max math3d.Vec3) {
+	if len(m.Vertices) == 0 {
+		return math3d.Vec3{}, math3d.Vec3{}
+	}
 
+	min, max = m.Vertices[0].Position, m.Vertices[0].Position
 	for _, v := range m.Vertices[1:] {
-		m.BoundsMin = m.BoundsMin.Min(v.Position)
-		m.BoundsMax = m.BoundsMax.Max(v.Position)
+		min = min.Min(v.Position)
+		max = max.Max(v.Position)
 	}
+	return min, max
 }
 
 // Center returns the center of the bounding box.
@@ -78,11 +161,104 @@ func (m *Mesh) Size() math3d.Vec3 {
 	return m.BoundsMax.Sub(m.BoundsMin)
 }
 
+// SourceSize returns the bounding box size in the source file's units,
+// however the mesh has since been fitted.
+func (m *Mesh) SourceSize() math3d.Vec3 {
+	return m.Size().Scale(m.SourceScale)
+}
+
+// FitToSize scales the mesh uniformly about the origin so its largest
+// dimension is size, and returns the factor applied. SourceScale keeps
+// track, so real dimensions survive the fit.
+func (m *Mesh) FitToSize(size float64) float64 {
+	s := m.Size()
+	maxDim := max(s.X, s.Y, s.Z)
+	if maxDim <= 0 {
+		return 1
+	}
+	f := size / maxDim
+	m.Transform(math3d.Scale(math3d.V3(f, f, f)))
+	m.SourceScale /= f
+	return f
+}
+
+// Anchor selects the point of a model that RecenterTo moves to the origin.
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota // Center of the bounding box
+	AnchorBottom               // Center of the bounding box's bottom face, e.g. a character's feet
+	AnchorOrigin               // The model's own origin, as authored
+)
+
+// anchorNames maps ParseAnchor names to anchors.
+var anchorNames = map[string]Anchor{
+	"center": AnchorCenter,
+	"bottom": AnchorBottom,
+	"origin": AnchorOrigin,
+}
+
+// ParseAnchor parses an anchor name: center, bottom, or origin.
+func ParseAnchor(name string) (Anchor, error) {
+	a, ok := anchorNames[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown anchor %q (use center, bottom, or origin)", name)
+	}
+	return a, nil
+}
+
+// AnchorPoint returns the position of anchor a in the mesh's bounds.
+func (m *Mesh) AnchorPoint(a Anchor) math3d.Vec3 {
+	switch a {
+	case AnchorBottom:
+		c := m.Center()
+		return math3d.V3(c.X, m.BoundsMin.Y, c.Z)
+	case AnchorOrigin:
+		return math3d.Zero3()
+	default:
+		return m.Center()
+	}
+}
+
+// RecenterTo translates the mesh so anchor a sits at the origin.
+func (m *Mesh) RecenterTo(a Anchor) {
+	if p := m.AnchorPoint(a); p != math3d.Zero3() {
+		m.Transform(math3d.Translate(p.Scale(-1)))
+	}
+}
+
 // TriangleCount returns the number of triangles.
 func (m *Mesh) TriangleCount() int {
 	return len(m.Faces)
 }
 
+// IsEmpty reports whether the mesh has no triangles, lines, or points to draw.
+func (m *Mesh) IsEmpty() bool {
+	return len(m.Faces) == 0 && len(m.Lines) == 0 && len(m.Points) == 0
+}
+
+// LineCount returns the number of line segments.
+func (m *Mesh) LineCount() int {
+	return len(m.Lines)
+}
+
+// GetLine returns the vertex indices for line segment i.
+// Implements render.LineMeshRenderer interface.
+func (m *Mesh) GetLine(i int) [2]int {
+	return m.Lines[i]
+}
+
+// PointCount returns the number of point primitives.
+func (m *Mesh) PointCount() int {
+	return len(m.Points)
+}
+
+// GetPoint returns the vertex index for point i.
+// Implements render.LineMeshRenderer interface.
+func (m *Mesh) GetPoint(i int) int {
+	return m.Points[i]
+}
+
 // VertexCount returns the number of vertices.
 func (m *Mesh) VertexCount() int {
 	return len(m.Vertices)
@@ -139,11 +315,14 @@ func (m *Mesh) CalculateSmoothNormals() {
 
 // Transform applies a transformation matrix to all vertices.
 func (m *Mesh) Transform(mat math3d.Mat4) {
+	// Transform normals with inverse transpose (for non-uniform scaling)
+	normalMat := mat.NormalMatrix()
 	for i := range m.Vertices {
 		m.Vertices[i].Position = mat.MulVec3(m.Vertices[i].Position)
-		// Transform normals with inverse transpose (for non-uniform scaling)
-		// For now, just use the rotation part
-		m.Vertices[i].Normal = mat.MulVec3Dir(m.Vertices[i].Normal).Normalize()
+		m.Vertices[i].Normal = normalMat.MulVec3Dir(m.Vertices[i].Normal).Normalize()
+		if t := m.Vertices[i].Tangent; t.W != 0 {
+			m.Vertices[i].Tangent = math3d.V4FromV3(mat.MulVec3Dir(t.Vec3()).Normalize(), t.W)
+		}
 	}
 	m.CalculateBounds()
 }
@@ -154,13 +333,25 @@ func (m *Mesh) Clone() *Mesh {
 		Name:      m.Name,
 		Vertices:  make([]MeshVertex, len(m.Vertices)),
 		Faces:     make([]Face, len(m.Faces)),
+		Lines:     make([][2]int, len(m.Lines)),
+		Points:    make([]int, len(m.Points)),
 		Materials: make([]Material, len(m.Materials)),
 		BoundsMin: m.BoundsMin,
 		BoundsMax: m.BoundsMax,
+
+		Winding:        m.Winding,
+		TextureUVSet:   m.TextureUVSet,
+		TextureWrap:    m.TextureWrap,
+		OcclusionUVSet: m.OcclusionUVSet,
+		ApproxTangents: m.ApproxTangents,
+		SourceScale:    m.SourceScale,
 	}
 	copy(clone.Vertices, m.Vertices)
 	copy(clone.Faces, m.Faces)
+	copy(clone.Lines, m.Lines)
+	copy(clone.Points, m.Points)
 	copy(clone.Materials, m.Materials)
+	clone.Groups = append([]string(nil), m.Groups...)
 	return clone
 }
 
@@ -168,7 +359,32 @@ func (m *Mesh) Clone() *Mesh {
 // Implements render.MeshRenderer interface.
 func (m *Mesh) GetVertex(i int) (pos, normal math3d.Vec3, uv math3d.Vec2) {
 	v := m.Vertices[i]
-	return v.Position, v.Normal, v.UV
+	return v.Position, v.Normal, v.TexCoord(m.TextureUVSet)
+}
+
+// GetOcclusionUV returns the ambient occlusion map coordinates for vertex i.
+// Implements render.OcclusionMeshRenderer interface.
+func (m *Mesh) GetOcclusionUV(i int) math3d.Vec2 {
+	return m.Vertices[i].TexCoord(m.OcclusionUVSet)
+}
+
+// OcclusionMap returns the first material's ambient occlusion map and its
+// strength, or nil if no material has one loaded.
+func (m *Mesh) OcclusionMap() (image.Image, float64) {
+	for _, mat := range m.Materials {
+		if mat.OcclusionMap != nil {
+			return mat.OcclusionMap, mat.OcclusionStrength
+		}
+	}
+	return nil, 0
+}
+
+// TexCoord returns the vertex's UV set: 0 for UV, 1 for UV1.
+func (v MeshVertex) TexCoord(set int) math3d.Vec2 {
+	if set == 1 {
+		return v.UV1
+	}
+	return v.UV
 }
 
 // GetFace returns the vertex indices for face i.
@@ -203,6 +419,48 @@ func (m *Mesh) GetBounds() (min, max math3d.Vec3) {
 	return m.BoundsMin, m.BoundsMax
 }
 
+// BoundingSphere returns a sphere enclosing every vertex as of the last
+// CalculateBounds, like the bounding box, or zero if it hasn't run.
+// Implements render.SphereBoundedMeshRenderer interface.
+func (m *Mesh) BoundingSphere() (center math3d.Vec3, radius float64) {
+	return m.sphereCenter, m.sphereRadius
+}
+
+// computeSphere returns a sphere enclosing every vertex, using Ritter's
+// algorithm, which is within a few percent of the smallest.
+func (m *Mesh) computeSphere() (center math3d.Vec3, radius float64) {
+	if len(m.Vertices) == 0 {
+		return math3d.Vec3{}, 0
+	}
+
+	// Start from two far-apart points: the farthest from any vertex,
+	// then the farthest from that
+	farthest := func(from math3d.Vec3) math3d.Vec3 {
+		best, bestDist := from, -1.0
+		for _, v := range m.Vertices {
+			if d := v.Position.Sub(from).LenSq(); d > bestDist {
+				best, bestDist = v.Position, d
+			}
+		}
+		return best
+	}
+	a := farthest(m.Vertices[0].Position)
+	b := farthest(a)
+	center = a.Add(b).Scale(0.5)
+	radius = a.Distance(b) / 2
+
+	// Grow the sphere just enough to take in each point left outside
+	for _, v := range m.Vertices {
+		d := v.Position.Distance(center)
+		if d > radius {
+			grow := (d - radius) / 2
+			center = center.Add(v.Position.Sub(center).Scale(grow / d))
+			radius += grow
+		}
+	}
+	return center, radius
+}
+
 // faceKey creates a canonical key for a face by sorting vertex indices.
 // Two faces with the same vertices (in any order) will have the same key.
 func faceKey(v0, v1, v2 int) [3]int {
@@ -381,10 +639,11 @@ func (m *Mesh) RemoveDegenerateFaces() int {
 	return removed
 }
 
-// RemoveUnreferencedVertices removes vertices that are not referenced by any face.
-// This compacts the vertex array and updates face indices accordingly.
+// RemoveUnreferencedVertices removes vertices that are not referenced by any
+// face, line, or point. This compacts the vertex array and updates indices
+// accordingly.
 func (m *Mesh) RemoveUnreferencedVertices() {
-	if len(m.Faces) == 0 || len(m.Vertices) == 0 {
+	if m.IsEmpty() || len(m.Vertices) == 0 {
 		return
 	}
 
@@ -395,6 +654,13 @@ func (m *Mesh) RemoveUnreferencedVertices() {
 		referenced[f.V[1]] = true
 		referenced[f.V[2]] = true
 	}
+	for _, l := range m.Lines {
+		referenced[l[0]] = true
+		referenced[l[1]] = true
+	}
+	for _, p := range m.Points {
+		referenced[p] = true
+	}
 
 	// Build compacted vertex list and index mapping
 	newIndex := make([]int, len(m.Vertices))
@@ -406,12 +672,57 @@ func (m *Mesh) RemoveUnreferencedVertices() {
 		}
 	}
 
-	// Update face indices
+	// Update face, line, and point indices
 	for i := range m.Faces {
 		m.Faces[i].V[0] = newIndex[m.Faces[i].V[0]]
 		m.Faces[i].V[1] = newIndex[m.Faces[i].V[1]]
 		m.Faces[i].V[2] = newIndex[m.Faces[i].V[2]]
 	}
+	for i := range m.Lines {
+		m.Lines[i][0] = newIndex[m.Lines[i][0]]
+		m.Lines[i][1] = newIndex[m.Lines[i][1]]
+	}
+	for i := range m.Points {
+		m.Points[i] = newIndex[m.Points[i]]
+	}
 
 	m.Vertices = newVertices
 }
+
+// Reindex merges vertices whose position, normal, and UV are all identical,
+// so faces that share a corner share its index, and returns how many
+// vertices were removed. Faces, lines, and points are remapped; first
+// occurrences keep their relative order.
+func (m *Mesh) Reindex() int {
+	index := make(map[MeshVertex]int, len(m.Vertices))
+	remap := make([]int, len(m.Vertices))
+	unique := m.Vertices[:0]
+	for i, v := range m.Vertices {
+		j, ok := index[v]
+		if !ok {
+			j = len(unique)
+			index[v] = j
+			unique = append(unique, v)
+		}
+		remap[i] = j
+	}
+	removed := len(m.Vertices) - len(unique)
+	if removed == 0 {
+		return 0
+	}
+
+	for i := range m.Faces {
+		for k := range 3 {
+			m.Faces[i].V[k] = remap[m.Faces[i].V[k]]
+		}
+	}
+	for i := range m.Lines {
+		m.Lines[i][0] = remap[m.Lines[i][0]]
+		m.Lines[i][1] = remap[m.Lines[i][1]]
+	}
+	for i := range m.Points {
+		m.Points[i] = remap[m.Points[i]]
+	}
+	m.Vertices = unique
+	return removed
+}
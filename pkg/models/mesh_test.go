@@ -1,6 +1,9 @@
 package models
 
 import (
+	"math"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/taigrr/trophy/pkg/math3d"
@@ -201,3 +204,237 @@ func TestCleanMeshWithInternalFaces(t *testing.T) {
 		t.Errorf("After clean: TriangleCount = %d, want 1", mesh.TriangleCount())
 	}
 }
+
+func TestTransformNonUniformScaleKeepsNormalsPerpendicular(t *testing.T) {
+	// A sloped triangle whose normal is not aligned with any axis
+	mesh := NewMesh("test")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0)},
+		{Position: math3d.V3(1, 0, 1)},
+		{Position: math3d.V3(0, 1, 1)},
+	}
+	mesh.Faces = []Face{{V: [3]int{0, 1, 2}}}
+	mesh.CalculateNormals()
+
+	mesh.Transform(math3d.Scale(math3d.V3(1, 1, 3)))
+
+	p0 := mesh.Vertices[0].Position
+	edge1 := mesh.Vertices[1].Position.Sub(p0)
+	edge2 := mesh.Vertices[2].Position.Sub(p0)
+	n := mesh.Vertices[0].Normal
+
+	if d := n.Dot(edge1); d > 1e-9 || d < -1e-9 {
+		t.Errorf("normal not perpendicular to edge1 after scale: dot = %v", d)
+	}
+	if d := n.Dot(edge2); d > 1e-9 || d < -1e-9 {
+		t.Errorf("normal not perpendicular to edge2 after scale: dot = %v", d)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	mesh := NewMesh("test")
+	if !mesh.IsEmpty() {
+		t.Error("new mesh should be empty")
+	}
+
+	// Vertices alone (e.g. a point cloud) are still nothing to draw
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0)},
+		{Position: math3d.V3(1, 0, 0)},
+		{Position: math3d.V3(0, 1, 0)},
+	}
+	if !mesh.IsEmpty() {
+		t.Error("mesh with vertices but no faces should be empty")
+	}
+
+	mesh.Faces = []Face{{V: [3]int{0, 1, 2}}}
+	if mesh.IsEmpty() {
+		t.Error("mesh with a face should not be empty")
+	}
+}
+
+func TestRemoveUnreferencedVerticesKeepsLinesAndPoints(t *testing.T) {
+	mesh := NewMesh("test")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0)},
+		{Position: math3d.V3(1, 0, 0)},
+		{Position: math3d.V3(0, 1, 0)},
+		{Position: math3d.V3(9, 9, 9)}, // unreferenced
+		{Position: math3d.V3(2, 0, 0)},
+		{Position: math3d.V3(3, 0, 0)},
+	}
+	mesh.Faces = []Face{{V: [3]int{0, 1, 2}}}
+	mesh.Lines = [][2]int{{2, 4}}
+	mesh.Points = []int{5}
+
+	mesh.RemoveUnreferencedVertices()
+
+	if len(mesh.Vertices) != 5 {
+		t.Fatalf("expected 5 vertices, got %d", len(mesh.Vertices))
+	}
+	if got := mesh.Vertices[mesh.Lines[0][1]].Position; got != math3d.V3(2, 0, 0) {
+		t.Errorf("line endpoint moved to %v", got)
+	}
+	if got := mesh.Vertices[mesh.Points[0]].Position; got != math3d.V3(3, 0, 0) {
+		t.Errorf("point moved to %v", got)
+	}
+}
+
+func TestBoundingSphere(t *testing.T) {
+	mesh := uvSphere(12, 24)
+	mesh.Transform(math3d.Translate(math3d.V3(3, -2, 1)))
+
+	center, radius := mesh.BoundingSphere()
+	if center.Distance(math3d.V3(3, -2, 1)) > 0.05 || radius < 1 || radius > 1.05 {
+		t.Errorf("BoundingSphere() = %v, %.3f; want about (3,-2,1), 1", center, radius)
+	}
+	for i, v := range mesh.Vertices {
+		if d := v.Position.Distance(center); d > radius+1e-9 {
+			t.Fatalf("vertex %d is %.4f from center, outside radius %.4f", i, d, radius)
+		}
+	}
+
+	// CalculateBounds, which Transform calls, recomputes the sphere
+	mesh.Transform(math3d.Scale(math3d.V3(2, 2, 2)))
+	if _, r := mesh.BoundingSphere(); math.Abs(r-2*radius) > 1e-9 {
+		t.Errorf("radius after scaling by 2 = %.4f, want %.4f", r, 2*radius)
+	}
+
+	// Reading it changes nothing, so parallel draws can share the mesh
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() { mesh.BoundingSphere() })
+	}
+	wg.Wait()
+
+	if c, r := NewMesh("empty").BoundingSphere(); c != (math3d.Vec3{}) || r != 0 {
+		t.Errorf("empty mesh sphere = %v, %v; want zero", c, r)
+	}
+}
+
+func TestRecenterTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		anchor    string
+		wantMin   math3d.Vec3
+		wantMax   math3d.Vec3
+		wantError bool
+	}{
+		{"center", "center", math3d.V3(-1, -2, -0.5), math3d.V3(1, 2, 0.5), false},
+		{"bottom", "Bottom", math3d.V3(-1, 0, -0.5), math3d.V3(1, 4, 0.5), false},
+		{"origin", "origin", math3d.V3(1, 1, 1), math3d.V3(3, 5, 2), false},
+		{"unknown", "feet", math3d.Vec3{}, math3d.Vec3{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anchor, err := ParseAnchor(tt.anchor)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseAnchor(%q) error = %v, wantError %v", tt.anchor, err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+
+			mesh := NewMesh("box")
+			mesh.Vertices = []MeshVertex{
+				{Position: math3d.V3(1, 1, 1)},
+				{Position: math3d.V3(3, 5, 2)},
+				{Position: math3d.V3(2, 3, 1)},
+			}
+			mesh.Faces = []Face{{V: [3]int{0, 1, 2}}}
+			mesh.CalculateBounds()
+
+			mesh.RecenterTo(anchor)
+			if mesh.BoundsMin != tt.wantMin || mesh.BoundsMax != tt.wantMax {
+				t.Errorf("bounds = %v..%v, want %v..%v", mesh.BoundsMin, mesh.BoundsMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestFitToSize(t *testing.T) {
+	mesh := NewMesh("box")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(-0.2, 0, -0.1)},
+		{Position: math3d.V3(0.2, 1.8, 0.1)},
+	}
+	mesh.CalculateBounds()
+
+	if f := mesh.FitToSize(2); math.Abs(f-2/1.8) > 1e-12 {
+		t.Errorf("FitToSize(2) = %v, want %v", f, 2/1.8)
+	}
+	if got := mesh.Size(); math.Abs(got.Y-2) > 1e-12 {
+		t.Errorf("fitted size = %v, want height 2", got)
+	}
+	if got := mesh.SourceSize(); got.Sub(math3d.V3(0.4, 1.8, 0.2)).Len() > 1e-12 {
+		t.Errorf("SourceSize() = %v, want (0.4, 1.8, 0.2)", got)
+	}
+
+	// Fitting again compounds, and clones carry the scale along
+	mesh.FitToSize(1)
+	if got := mesh.Clone().SourceSize(); got.Sub(math3d.V3(0.4, 1.8, 0.2)).Len() > 1e-12 {
+		t.Errorf("SourceSize() after refit = %v, want (0.4, 1.8, 0.2)", got)
+	}
+
+	empty := NewMesh("empty")
+	if f := empty.FitToSize(2); f != 1 || empty.SourceScale != 1 {
+		t.Errorf("FitToSize on empty mesh = %v, SourceScale %v; want 1, 1", f, empty.SourceScale)
+	}
+}
+
+func TestComputeBounds(t *testing.T) {
+	mesh := uvSphere(8, 16)
+	mesh.Transform(math3d.Translate(math3d.V3(3, -2, 1)))
+	wantMin, wantMax := mesh.BoundsMin, mesh.BoundsMax
+	mesh.BoundsMin, mesh.BoundsMax = math3d.Vec3{}, math3d.Vec3{}
+
+	// Run with -race: computing bounds concurrently must not write the mesh
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if min, max := mesh.ComputeBounds(); min != wantMin || max != wantMax {
+				t.Errorf("ComputeBounds() = %v, %v; want %v, %v", min, max, wantMin, wantMax)
+			}
+		})
+	}
+	wg.Wait()
+	if mesh.BoundsMin != (math3d.Vec3{}) || mesh.BoundsMax != (math3d.Vec3{}) {
+		t.Errorf("ComputeBounds changed the stored bounds to %v, %v", mesh.BoundsMin, mesh.BoundsMax)
+	}
+
+	if min, max := NewMesh("empty").ComputeBounds(); min != (math3d.Vec3{}) || max != (math3d.Vec3{}) {
+		t.Errorf("empty mesh bounds = %v, %v; want zero", min, max)
+	}
+}
+
+func TestReindex(t *testing.T) {
+	a := MeshVertex{Position: math3d.V3(0, 0, 0)}
+	b := MeshVertex{Position: math3d.V3(1, 0, 0)}
+	c := MeshVertex{Position: math3d.V3(1, 1, 0)}
+	d := MeshVertex{Position: math3d.V3(0, 1, 0)}
+	seam := MeshVertex{Position: math3d.V3(0, 0, 0), UV: math3d.V2(1, 0)} // Same place as a, different UV
+
+	mesh := NewMesh("quad")
+	mesh.Vertices = []MeshVertex{a, b, c, a, c, d, seam, b}
+	mesh.Faces = []Face{{V: [3]int{0, 1, 2}}, {V: [3]int{3, 4, 5}}}
+	mesh.Lines = [][2]int{{6, 7}}
+	mesh.Points = []int{5}
+
+	if removed := mesh.Reindex(); removed != 3 {
+		t.Errorf("Reindex() = %d, want 3", removed)
+	}
+	if want := []MeshVertex{a, b, c, d, seam}; !slices.Equal(mesh.Vertices, want) {
+		t.Errorf("vertices = %v, want %v", mesh.Vertices, want)
+	}
+	if want := []Face{{V: [3]int{0, 1, 2}}, {V: [3]int{0, 2, 3}}}; !slices.Equal(mesh.Faces, want) {
+		t.Errorf("faces = %v, want %v", mesh.Faces, want)
+	}
+	if mesh.Lines[0] != [2]int{4, 1} || mesh.Points[0] != 3 {
+		t.Errorf("lines = %v, points = %v; want [[4 1]], [3]", mesh.Lines, mesh.Points)
+	}
+
+	if removed := mesh.Reindex(); removed != 0 {
+		t.Errorf("second Reindex() = %d, want 0", removed)
+	}
+}
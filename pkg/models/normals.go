@@ -0,0 +1,102 @@
+package models
+
+import (
+	"math"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// CalculateNormalsWithAngle computes smooth normals that stay sharp at
+// creases: each face corner averages, weighted by area, only the faces
+// around its position whose normals are within degrees of its own face's.
+// Vertices where a crease passes are split so each side shades on its own.
+// 0 gives flat shading and 180 smooths everything, like
+// CalculateSmoothNormals but also across UV and normal seams.
+func (m *Mesh) CalculateNormalsWithAngle(degrees float64) {
+	cosLimit := math.Cos(degrees * math.Pi / 180)
+	m.calculateSplitNormals(func(i, j int, unit []math3d.Vec3) bool {
+		return unit[j].Dot(unit[i]) >= cosLimit
+	})
+}
+
+// CalculateNormalsBySmoothingGroup computes normals the way OBJ smoothing
+// groups ask for: each face corner averages, weighted by area, the faces
+// around its position in the same Face.Smoothing group, and faces in group
+// 0 are flat. Vertices on a group's border are split like in
+// CalculateNormalsWithAngle.
+func (m *Mesh) CalculateNormalsBySmoothingGroup() {
+	m.calculateSplitNormals(func(i, j int, _ []math3d.Vec3) bool {
+		return m.Faces[i].Smoothing != 0 && m.Faces[i].Smoothing == m.Faces[j].Smoothing
+	})
+}
+
+// calculateSplitNormals gives each face corner the area-weighted average
+// normal of the faces j around its position that face i's corner smooths
+// with, by smooths(i, j, unit) given the unit face normals, splitting
+// vertices whose corners end up with different normals.
+func (m *Mesh) calculateSplitNormals(smooths func(i, j int, unit []math3d.Vec3) bool) {
+	if len(m.Faces) == 0 {
+		return
+	}
+
+	// Area-weighted and unit face normals
+	weighted := make([]math3d.Vec3, len(m.Faces))
+	unit := make([]math3d.Vec3, len(m.Faces))
+	for i, f := range m.Faces {
+		p0 := m.Vertices[f.V[0]].Position
+		weighted[i] = m.Vertices[f.V[1]].Position.Sub(p0).Cross(m.Vertices[f.V[2]].Position.Sub(p0))
+		if weighted[i].LenSq() > 0 {
+			unit[i] = weighted[i].Normalize()
+		}
+	}
+
+	cluster, pos := m.positionClusters()
+	around := make([][]int, len(pos)) // Faces touching each position
+	for i, f := range m.Faces {
+		for _, vi := range f.V {
+			around[cluster[vi]] = append(around[cluster[vi]], i)
+		}
+	}
+
+	// The first corner to use a vertex sets its normal; corners needing a
+	// different one get a copy of the vertex, shared with any others that
+	// need the same normal
+	type split struct {
+		vertex int
+		normal math3d.Vec3
+	}
+	assigned := make([]bool, len(m.Vertices))
+	copies := make(map[split]int)
+	for i := range m.Faces {
+		for k, vi := range m.Faces[i].V {
+			n := math3d.Zero3()
+			for _, j := range around[cluster[vi]] {
+				if j == i || smooths(i, j, unit) {
+					n = n.Add(weighted[j])
+				}
+			}
+			if n.LenSq() == 0 {
+				n = unit[i]
+			} else {
+				n = n.Normalize()
+			}
+
+			switch {
+			case !assigned[vi]:
+				assigned[vi] = true
+				m.Vertices[vi].Normal = n
+			case m.Vertices[vi].Normal != n:
+				key := split{vi, n}
+				c, ok := copies[key]
+				if !ok {
+					v := m.Vertices[vi]
+					v.Normal = n
+					m.Vertices = append(m.Vertices, v)
+					c = len(m.Vertices) - 1
+					copies[key] = c
+				}
+				m.Faces[i].V[k] = c
+			}
+		}
+	}
+}
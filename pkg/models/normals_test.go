@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// sharedCube returns a unit cube whose 8 corners are shared by its 12 faces.
+func sharedCube() *Mesh {
+	mesh := NewMesh("cube")
+	for i := range 8 {
+		mesh.Vertices = append(mesh.Vertices, MeshVertex{
+			Position: math3d.V3(float64(i&1), float64(i>>1&1), float64(i>>2&1)),
+		})
+	}
+	quads := [][4]int{
+		{0, 2, 3, 1}, {4, 5, 7, 6}, // -z, +z
+		{0, 1, 5, 4}, {2, 6, 7, 3}, // -y, +y
+		{0, 4, 6, 2}, {1, 3, 7, 5}, // -x, +x
+	}
+	for _, q := range quads {
+		mesh.Faces = append(mesh.Faces, Face{V: [3]int{q[0], q[1], q[2]}}, Face{V: [3]int{q[0], q[2], q[3]}})
+	}
+	mesh.CalculateBounds()
+	return mesh
+}
+
+func TestCalculateNormalsWithAngle(t *testing.T) {
+	tests := []struct {
+		name     string
+		degrees  float64
+		vertices int
+		axis     bool // Whether every normal points along an axis
+	}{
+		{"sharp", 30, 24, true},
+		{"flat", 0, 24, true},
+		{"smooth", 180, 8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := sharedCube()
+			mesh.CalculateNormalsWithAngle(tt.degrees)
+			if len(mesh.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(mesh.Vertices), tt.vertices)
+			}
+			for i, f := range mesh.Faces {
+				p0 := mesh.Vertices[f.V[0]].Position
+				face := mesh.Vertices[f.V[1]].Position.Sub(p0).Cross(mesh.Vertices[f.V[2]].Position.Sub(p0)).Normalize()
+				for _, vi := range f.V {
+					n := mesh.Vertices[vi].Normal
+					if math.Abs(n.Len()-1) > 1e-9 {
+						t.Fatalf("face %d vertex %d: normal %v isn't unit length", i, vi, n)
+					}
+					if onAxis := math.Abs(n.Dot(face)-1) < 1e-9; onAxis != tt.axis {
+						t.Errorf("face %d vertex %d: normal %v, face normal %v", i, vi, n, face)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateNormalsWithAngleSmoothSurface(t *testing.T) {
+	// A finely tessellated sphere bends less than the limit everywhere, so
+	// nothing splits and the normals point outward
+	mesh := uvSphere(16, 32)
+	before := len(mesh.Vertices)
+	mesh.CalculateNormalsWithAngle(45)
+	if len(mesh.Vertices) != before {
+		t.Errorf("vertices went from %d to %d", before, len(mesh.Vertices))
+	}
+	for i, v := range mesh.Vertices {
+		if d := v.Normal.Dot(v.Position.Normalize()); d < 0.95 {
+			t.Fatalf("vertex %d: normal %v strays from the outward radius %v", i, v.Normal, v.Position)
+		}
+	}
+}
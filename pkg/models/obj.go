@@ -16,6 +16,7 @@ type OBJLoader struct {
 	// Options
 	CalculateNormals bool // If true, calculate normals if not provided
 	SmoothNormals    bool // If true, use smooth shading (averaged normals)
+	SmoothingGroups  bool // If true, calculated normals follow the file's s statements when it has any
 }
 
 // NewOBJLoader creates a new OBJ loader with default settings.
@@ -23,6 +24,7 @@ func NewOBJLoader() *OBJLoader {
 	return &OBJLoader{
 		CalculateNormals: true,
 		SmoothNormals:    false,
+		SmoothingGroups:  true,
 	}
 }
 
@@ -54,9 +56,15 @@ func (l *OBJLoader) Load(r io.Reader, name string) (*Mesh, error) {
 
 	scanner := bufio.NewScanner(r)
 	lineNum := 0
+	group := -1           // Index into mesh.Groups of the current o or g
+	smoothing := 0        // Current s group, 0 for off
+	hasSmoothing := false // Whether any face is in a smoothing group
 
 	for scanner.Scan() {
 		lineNum++
+		if lineNum == 1 && isLFSPointer(scanner.Bytes()) {
+			return nil, ErrLFSPointer
+		}
 		line := strings.TrimSpace(scanner.Text())
 
 		// Skip empty lines and comments
@@ -166,18 +174,39 @@ func (l *OBJLoader) Load(r io.Reader, name string) (*Mesh, error) {
 			// Triangulate (fan triangulation for convex polygons)
 			// Note: OBJ uses CCW winding for front-facing, but our engine uses CW
 			// (due to Y-flip in screen space), so we reverse the winding here
+			if group < 0 {
+				group = mesh.groupIndex("default") // Faces before any o or g
+			}
 			for i := 1; i < len(faceVerts)-1; i++ {
 				mesh.Faces = append(mesh.Faces, Face{
-					V: [3]int{faceVerts[0], faceVerts[i+1], faceVerts[i]}, // swapped i and i+1
+					V:         [3]int{faceVerts[0], faceVerts[i+1], faceVerts[i]}, // swapped i and i+1
+					Group:     group,
+					Smoothing: smoothing,
 				})
 			}
+			hasSmoothing = hasSmoothing || smoothing != 0
 
-		case "o", "g": // Object/group name (use as mesh name)
+		case "o", "g": // Object/group name (use as mesh name and part)
 			if len(fields) > 1 {
 				mesh.Name = fields[1]
+				group = mesh.groupIndex(strings.Join(fields[1:], " "))
+			}
+
+		case "s": // Smoothing group, applying to the faces that follow
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: smoothing group needs a number or off", lineNum)
+			}
+			if strings.EqualFold(fields[1], "off") {
+				smoothing = 0
+				break
+			}
+			n, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid smoothing group: %w", lineNum, err)
 			}
+			smoothing = n
 
-		case "mtllib", "usemtl", "s": // Material library, material use, smoothing - ignore for now
+		case "mtllib", "usemtl": // Material library, material use - ignore for now
 
 		default:
 			// Ignore unknown directives
@@ -188,14 +217,18 @@ func (l *OBJLoader) Load(r io.Reader, name string) (*Mesh, error) {
 		return nil, fmt.Errorf("error reading OBJ: %w", err)
 	}
 
-	// Calculate bounds
+	// Faces were reversed from OBJ's CCW order as they were read
+	mesh.Winding = WindingCW
 	mesh.CalculateBounds()
 
 	// Calculate normals if needed
 	if l.CalculateNormals && len(normals) == 0 {
-		if l.SmoothNormals {
+		switch {
+		case l.SmoothingGroups && hasSmoothing:
+			mesh.CalculateNormalsBySmoothingGroup()
+		case l.SmoothNormals:
 			mesh.CalculateSmoothNormals()
-		} else {
+		default:
 			mesh.CalculateNormals()
 		}
 	}
@@ -256,3 +289,49 @@ func LoadOBJSmooth(path string) (*Mesh, error) {
 	loader.SmoothNormals = true
 	return loader.LoadFile(path)
 }
+
+// WriteOBJ writes the mesh as Wavefront OBJ, with one position, texture
+// coordinate, and normal per vertex. Faces are written counter-clockwise,
+// undoing the winding flip LoadOBJ applies. Materials are not written.
+func WriteOBJ(w io.Writer, m *Mesh) error {
+	bw := bufio.NewWriter(w)
+	if m.Name != "" {
+		fmt.Fprintf(bw, "o %s\n", strings.Join(strings.Fields(m.Name), "_"))
+	}
+	for _, v := range m.Vertices {
+		fmt.Fprintf(bw, "v %g %g %g\n", v.Position.X, v.Position.Y, v.Position.Z)
+	}
+	for _, v := range m.Vertices {
+		fmt.Fprintf(bw, "vt %g %g\n", v.UV.X, v.UV.Y)
+	}
+	for _, v := range m.Vertices {
+		fmt.Fprintf(bw, "vn %g %g %g\n", v.Normal.X, v.Normal.Y, v.Normal.Z)
+	}
+	for _, f := range m.Faces {
+		a, b, c := f.V[0]+1, f.V[2]+1, f.V[1]+1
+		fmt.Fprintf(bw, "f %d/%d/%d %d/%d/%d %d/%d/%d\n", a, a, a, b, b, b, c, c, c)
+	}
+	for _, l := range m.Lines {
+		fmt.Fprintf(bw, "l %d %d\n", l[0]+1, l[1]+1)
+	}
+	for _, p := range m.Points {
+		fmt.Fprintf(bw, "p %d\n", p+1)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write OBJ: %w", err)
+	}
+	return nil
+}
+
+// SaveOBJ writes the mesh to an OBJ file at path.
+func SaveOBJ(path string, m *Mesh) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create OBJ file: %w", err)
+	}
+	if err := WriteOBJ(f, m); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -112,6 +114,54 @@ f 1/1/1 2/2/1 3/3/1
 	_ = pos0
 }
 
+func TestLoadOBJSmoothingGroups(t *testing.T) {
+	// Two quads folded along the edge from v1 to v2
+	const hinge = `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 1 0 1
+v 0 0 1
+%s
+f 1 2 3 4
+%s
+f 1 6 5 2
+`
+	tests := []struct {
+		name     string
+		first    string
+		second   string
+		vertices int
+		smooth   bool // Whether the fold's normals are averaged across it
+	}{
+		{"shared group", "s 1", "", 6, true},
+		{"separate groups", "s 1", "s 2", 8, false},
+		{"off", "s 1", "s off", 8, false},
+		{"no statements", "", "", 6, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := fmt.Sprintf(hinge, tt.first, tt.second)
+			mesh, err := NewOBJLoader().Load(strings.NewReader(obj), "hinge")
+			if err != nil {
+				t.Fatalf("failed to load OBJ: %v", err)
+			}
+			if len(mesh.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(mesh.Vertices), tt.vertices)
+			}
+			n := mesh.Vertices[0].Normal
+			if smooth := math.Abs(n.Y) > 1e-9 && math.Abs(n.Z) > 1e-9; smooth != tt.smooth {
+				t.Errorf("fold normal %v, want averaged %v", n, tt.smooth)
+			}
+		})
+	}
+
+	if _, err := NewOBJLoader().Load(strings.NewReader("s x\n"), "bad"); err == nil {
+		t.Error("invalid smoothing group loaded without error")
+	}
+}
+
 func TestNegativeIndices(t *testing.T) {
 	// OBJ allows negative indices (counting from end)
 	objData := `
@@ -149,3 +199,34 @@ func TestMeshClone(t *testing.T) {
 		t.Error("clone was affected by original modification")
 	}
 }
+
+func TestWriteOBJRoundTrip(t *testing.T) {
+	mesh := NewMesh("round trip")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0), Normal: math3d.V3(0, 0, 1), UV: math3d.V2(0, 0)},
+		{Position: math3d.V3(1, 0, 0), Normal: math3d.V3(0, 0, 1), UV: math3d.V2(1, 0)},
+		{Position: math3d.V3(0.5, 1, 0), Normal: math3d.V3(0, 0, 1), UV: math3d.V2(0.5, 1)},
+	}
+	mesh.Faces = []Face{{V: [3]int{0, 2, 1}}}
+
+	var buf strings.Builder
+	if err := WriteOBJ(&buf, mesh); err != nil {
+		t.Fatalf("WriteOBJ: %v", err)
+	}
+	got, err := NewOBJLoader().Load(strings.NewReader(buf.String()), "round trip")
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	if got.Name != "round_trip" {
+		t.Errorf("name = %q, want %q", got.Name, "round_trip")
+	}
+	if got.TriangleCount() != 1 || got.Faces[0].V != mesh.Faces[0].V {
+		t.Fatalf("faces = %v, want %v", got.Faces, mesh.Faces)
+	}
+	for i, v := range got.Vertices {
+		if v != mesh.Vertices[i] {
+			t.Errorf("vertex %d = %+v, want %+v", i, v, mesh.Vertices[i])
+		}
+	}
+}
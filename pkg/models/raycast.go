@@ -0,0 +1,97 @@
+package models
+
+import (
+	"math"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// rayEpsilon rejects rays nearly parallel to a triangle and hits at the origin.
+const rayEpsilon = 1e-9
+
+// Raycast finds the nearest face hit by the ray from origin along dir.
+// Both sides of a face count as hits. t is the distance along dir to
+// the hit, in units of dir's length, and bary holds the hit's weights
+// for the face's three vertices. Rays that miss BoundsMin/BoundsMax
+// return early, so recalculate bounds after editing vertices directly.
+func (m *Mesh) Raycast(origin, dir math3d.Vec3) (hit bool, t float64, faceIndex int, bary math3d.Vec3) {
+	faceIndex = -1
+	if m.BoundsMin != m.BoundsMax {
+		if _, ok := rayBox(origin, dir, m.BoundsMin, m.BoundsMax, math.Inf(1)); !ok {
+			return false, 0, -1, math3d.Vec3{}
+		}
+	}
+
+	t = math.Inf(1)
+	for i, f := range m.Faces {
+		ht, u, v, ok := rayTriangle(origin, dir,
+			m.Vertices[f.V[0]].Position, m.Vertices[f.V[1]].Position, m.Vertices[f.V[2]].Position)
+		if ok && ht < t {
+			hit, t, faceIndex = true, ht, i
+			bary = math3d.V3(1-u-v, u, v)
+		}
+	}
+	if !hit {
+		return false, 0, -1, math3d.Vec3{}
+	}
+	return hit, t, faceIndex, bary
+}
+
+// rayTriangle intersects a ray with triangle p0 p1 p2 (Möller–Trumbore).
+// u and v are the barycentric weights of p1 and p2 at the hit.
+func rayTriangle(origin, dir, p0, p1, p2 math3d.Vec3) (t, u, v float64, ok bool) {
+	e1 := p1.Sub(p0)
+	e2 := p2.Sub(p0)
+	p := dir.Cross(e2)
+	det := e1.Dot(p)
+	if math.Abs(det) < rayEpsilon {
+		return 0, 0, 0, false
+	}
+	inv := 1 / det
+
+	s := origin.Sub(p0)
+	u = s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	q := s.Cross(e1)
+	v = dir.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+	t = e2.Dot(q) * inv
+	if t < rayEpsilon {
+		return 0, 0, 0, false
+	}
+	return t, u, v, true
+}
+
+// rayBox returns where the ray enters the box (0 if it starts inside),
+// and whether it does so before maxT (slab test).
+func rayBox(origin, dir, bmin, bmax math3d.Vec3, maxT float64) (float64, bool) {
+	tmin, tmax := 0.0, maxT
+	o := [3]float64{origin.X, origin.Y, origin.Z}
+	d := [3]float64{dir.X, dir.Y, dir.Z}
+	lo := [3]float64{bmin.X, bmin.Y, bmin.Z}
+	hi := [3]float64{bmax.X, bmax.Y, bmax.Z}
+	for axis := range 3 {
+		if d[axis] == 0 {
+			if o[axis] < lo[axis] || o[axis] > hi[axis] {
+				return 0, false
+			}
+			continue
+		}
+		inv := 1 / d[axis]
+		t0 := (lo[axis] - o[axis]) * inv
+		t1 := (hi[axis] - o[axis]) * inv
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		tmin = max(tmin, t0)
+		tmax = min(tmax, t1)
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+	return tmin, true
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// raycastTestMesh returns a unit right triangle in the z=0 plane and a
+// copy of it at z=-1, so rays along -Z pass through both.
+func raycastTestMesh() *Mesh {
+	mesh := NewMesh("raycast")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0)},
+		{Position: math3d.V3(1, 0, 0)},
+		{Position: math3d.V3(0, 1, 0)},
+		{Position: math3d.V3(0, 0, -1)},
+		{Position: math3d.V3(1, 0, -1)},
+		{Position: math3d.V3(0, 1, -1)},
+	}
+	mesh.Faces = []Face{
+		{V: [3]int{3, 4, 5}},
+		{V: [3]int{0, 1, 2}},
+	}
+	mesh.CalculateBounds()
+	return mesh
+}
+
+func TestRaycast(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   math3d.Vec3
+		dir      math3d.Vec3
+		wantHit  bool
+		wantT    float64
+		wantFace int
+		wantBary math3d.Vec3
+	}{
+		{"front face nearest", math3d.V3(0.25, 0.25, 5), math3d.V3(0, 0, -1), true, 5, 1, math3d.V3(0.5, 0.25, 0.25)},
+		{"from behind", math3d.V3(0.25, 0.25, -5), math3d.V3(0, 0, 1), true, 4, 0, math3d.V3(0.5, 0.25, 0.25)},
+		{"between faces", math3d.V3(0.5, 0.25, -0.5), math3d.V3(0, 0, 1), true, 0.5, 1, math3d.V3(0.25, 0.5, 0.25)},
+		{"at vertex", math3d.V3(1, 0, 5), math3d.V3(0, 0, -1), true, 5, 1, math3d.V3(0, 1, 0)},
+		{"scaled dir", math3d.V3(0.25, 0.25, 5), math3d.V3(0, 0, -2), true, 2.5, 1, math3d.V3(0.5, 0.25, 0.25)},
+		{"outside triangle", math3d.V3(0.75, 0.75, 5), math3d.V3(0, 0, -1), false, 0, -1, math3d.Vec3{}},
+		{"pointing away", math3d.V3(0.25, 0.25, 5), math3d.V3(0, 0, 1), false, 0, -1, math3d.Vec3{}},
+		{"parallel", math3d.V3(-1, 0.25, 0), math3d.V3(1, 0, 0), false, 0, -1, math3d.Vec3{}},
+		{"misses bounds", math3d.V3(5, 5, 5), math3d.V3(0, 0, -1), false, 0, -1, math3d.Vec3{}},
+	}
+
+	mesh := raycastTestMesh()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, tHit, face, bary := mesh.Raycast(tt.origin, tt.dir)
+			if hit != tt.wantHit || face != tt.wantFace {
+				t.Fatalf("Raycast() hit=%v face=%d, want hit=%v face=%d", hit, face, tt.wantHit, tt.wantFace)
+			}
+			if math.Abs(tHit-tt.wantT) > 1e-9 {
+				t.Errorf("t = %v, want %v", tHit, tt.wantT)
+			}
+			if bary.Sub(tt.wantBary).Len() > 1e-9 {
+				t.Errorf("bary = %v, want %v", bary, tt.wantBary)
+			}
+		})
+	}
+}
+
+func TestRaycastHitPoint(t *testing.T) {
+	mesh := raycastTestMesh()
+	origin := math3d.V3(2, 3, 4)
+	target := math3d.V3(0.2, 0.3, 0)
+	dir := target.Sub(origin).Normalize()
+
+	hit, tHit, face, bary := mesh.Raycast(origin, dir)
+	if !hit || face != 1 {
+		t.Fatalf("Raycast() hit=%v face=%d, want hit on face 1", hit, face)
+	}
+
+	// The hit along the ray and the barycentric point must agree
+	along := origin.Add(dir.Scale(tHit))
+	var fromBary math3d.Vec3
+	for i, vi := range mesh.Faces[face].V {
+		w := [3]float64{bary.X, bary.Y, bary.Z}[i]
+		fromBary = fromBary.Add(mesh.Vertices[vi].Position.Scale(w))
+	}
+	if along.Distance(target) > 1e-9 || fromBary.Distance(target) > 1e-9 {
+		t.Errorf("hit %v (bary %v), want %v", along, fromBary, target)
+	}
+}
@@ -0,0 +1,379 @@
+package models
+
+import (
+	"math"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// Section returns a copy of the mesh cut open by the plane of points p with
+// normal·p = offset, keeping the side the normal points away from. Faces
+// crossing the plane are clipped to it; SectionCap closes the cut. Lines
+// and points are kept only if they lie wholly on the kept side. The copy's
+// bounds are m's.
+func (m *Mesh) Section(normal math3d.Vec3, offset float64) *Mesh {
+	section, _ := m.clip(normal, offset)
+	return section
+}
+
+// clip is Section, also returning the edges of the clipped faces that lie
+// in the plane, as pairs of positions in the faces' winding order.
+func (m *Mesh) clip(normal math3d.Vec3, offset float64) (*Mesh, [][2]math3d.Vec3) {
+	dist := make([]float64, len(m.Vertices))
+	for i, v := range m.Vertices {
+		dist[i] = normal.Dot(v.Position) - offset
+	}
+	kept := func(i int) bool { return dist[i] <= 0 }
+
+	section := *m
+	section.Vertices = append([]MeshVertex(nil), m.Vertices...)
+	section.Faces = make([]Face, 0, len(m.Faces))
+
+	// Vertices where an edge crosses the plane, shared by the faces on
+	// either side of it. They're interpolated from the kept end, so edges
+	// duplicated at UV seams cut at exactly the same position.
+	type edge struct{ kept, cut int }
+	cuts := make(map[edge]int)
+	onPlane := make(map[int]bool)
+	cut := func(a, b int) int {
+		if !kept(a) {
+			a, b = b, a
+		}
+		if i, ok := cuts[edge{a, b}]; ok {
+			return i
+		}
+		t := dist[a] / (dist[a] - dist[b])
+		section.Vertices = append(section.Vertices, lerpVertex(m.Vertices[a], m.Vertices[b], t))
+		i := len(section.Vertices) - 1
+		cuts[edge{a, b}] = i
+		onPlane[i] = true
+		return i
+	}
+	for i, d := range dist {
+		if d == 0 {
+			onPlane[i] = true
+		}
+	}
+
+	var rim [][2]math3d.Vec3
+	for _, f := range m.Faces {
+		// Clip the triangle to the kept side, keeping its winding; the
+		// result has up to four corners. An end lying on the plane is its
+		// own cut.
+		var poly [4]int
+		n := 0
+		for i := range 3 {
+			a, b := f.V[i], f.V[(i+1)%3]
+			if kept(a) {
+				poly[n] = a
+				n++
+			}
+			if kept(a) != kept(b) && dist[a] != 0 && dist[b] != 0 {
+				poly[n] = cut(a, b)
+				n++
+			}
+		}
+		for i := 1; i+1 < n; i++ {
+			clipped := f
+			clipped.V = [3]int{poly[0], poly[i], poly[i+1]}
+			section.Faces = append(section.Faces, clipped)
+		}
+
+		// The clipped face's edges along the plane, unless it lies in it
+		inPlane := 0
+		for _, vi := range poly[:n] {
+			if onPlane[vi] {
+				inPlane++
+			}
+		}
+		if n < 3 || inPlane == n {
+			continue
+		}
+		for i := range n {
+			a, b := poly[i], poly[(i+1)%n]
+			if onPlane[a] && onPlane[b] {
+				rim = append(rim, [2]math3d.Vec3{section.Vertices[a].Position, section.Vertices[b].Position})
+			}
+		}
+	}
+
+	section.Lines = nil
+	for _, l := range m.Lines {
+		if kept(l[0]) && kept(l[1]) {
+			section.Lines = append(section.Lines, l)
+		}
+	}
+	section.Points = nil
+	for _, p := range m.Points {
+		if kept(p) {
+			section.Points = append(section.Points, p)
+		}
+	}
+	return &section, rim
+}
+
+// lerpVertex interpolates every attribute from a to b by t.
+func lerpVertex(a, b MeshVertex, t float64) MeshVertex {
+	return MeshVertex{
+		Position: a.Position.Lerp(b.Position, t),
+		Normal:   a.Normal.Lerp(b.Normal, t).Normalize(),
+		UV:       a.UV.Lerp(b.UV, t),
+		UV1:      a.UV1.Lerp(b.UV1, t),
+		Tangent:  a.Tangent.Lerp(b.Tangent, t),
+	}
+}
+
+// SectionCap returns the faces closing the cut Section(normal, offset)
+// makes: the regions of the plane enclosed by the loops where the mesh
+// crosses it, with holes where it encloses other loops, wound like m and
+// with normals along the plane normal. Where the mesh isn't closed the
+// crossing doesn't form a loop, and that part of the cut gets no cap. The
+// cap's bounds are m's.
+func (m *Mesh) SectionCap(normal math3d.Vec3, offset float64) *Mesh {
+	capMesh := NewMesh(m.Name + " section cap")
+	capMesh.Winding = m.Winding
+	capMesh.BoundsMin, capMesh.BoundsMax = m.BoundsMin, m.BoundsMax
+	capMesh.sphereCenter, capMesh.sphereRadius = m.sphereCenter, m.sphereRadius
+
+	normal = normal.Normalize()
+	_, rim := m.clip(normal, offset)
+
+	// Edges shared by two clipped faces are inside the kept surface; the
+	// rest border the cut. The cap runs along them the other way.
+	next := make(map[math3d.Vec3]math3d.Vec3)
+	open := make(map[[2]math3d.Vec3]bool)
+	for _, e := range rim {
+		if open[[2]math3d.Vec3{e[1], e[0]}] {
+			delete(open, [2]math3d.Vec3{e[1], e[0]})
+		} else {
+			open[e] = true
+		}
+	}
+	for e := range open {
+		next[e[1]] = e[0]
+	}
+
+	// Follow the edges into loops, in the plane's 2D coordinates
+	u := perpendicular(normal)
+	v := normal.Cross(u)
+	var loops [][]capPoint
+	for len(next) > 0 {
+		var start math3d.Vec3
+		for p := range next {
+			start = p
+			break
+		}
+		var loop []capPoint
+		closed := false
+		for p := start; ; {
+			q, ok := next[p]
+			if !ok {
+				break
+			}
+			delete(next, p)
+			loop = append(loop, capPoint{pos: p, x: p.Dot(u), y: p.Dot(v)})
+			if q == start {
+				closed = true
+				break
+			}
+			p = q
+		}
+		if closed && len(loop) >= 3 {
+			loops = append(loops, loop)
+		}
+	}
+	if len(loops) == 0 {
+		return capMesh
+	}
+
+	// The largest loop is an outline; loops wound the other way are holes
+	largest := 0
+	for i, l := range loops {
+		if math.Abs(loopArea(l)) > math.Abs(loopArea(loops[largest])) {
+			largest = i
+		}
+	}
+	ccw := loopArea(loops[largest]) > 0
+	var outlines, holes [][]capPoint
+	for _, l := range loops {
+		if (loopArea(l) > 0) == ccw {
+			outlines = append(outlines, l)
+		} else {
+			holes = append(holes, l)
+		}
+	}
+	for _, h := range holes {
+		// The smallest outline around the hole takes it
+		best := -1
+		for i, o := range outlines {
+			if insideLoop(o, h[0]) && (best < 0 || math.Abs(loopArea(o)) < math.Abs(loopArea(outlines[best]))) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			outlines[best] = bridgeHole(outlines[best], h)
+		}
+	}
+
+	for _, o := range outlines {
+		for _, tri := range earClip(o, ccw) {
+			base := len(capMesh.Vertices)
+			for _, p := range tri {
+				capMesh.Vertices = append(capMesh.Vertices, MeshVertex{Position: p.pos, Normal: normal})
+			}
+			capMesh.Faces = append(capMesh.Faces, Face{V: [3]int{base, base + 1, base + 2}})
+		}
+	}
+	capMesh.Reindex()
+	return capMesh
+}
+
+// capPoint is a point of a cap outline, with its coordinates in the plane.
+type capPoint struct {
+	pos  math3d.Vec3
+	x, y float64
+}
+
+// loopArea returns the signed area of a loop, positive if it winds
+// counter-clockwise in the plane's coordinates.
+func loopArea(loop []capPoint) float64 {
+	area := 0.0
+	for i, p := range loop {
+		q := loop[(i+1)%len(loop)]
+		area += p.x*q.y - q.x*p.y
+	}
+	return area / 2
+}
+
+// orient returns twice the signed area of triangle a, b, c.
+func orient(a, b, c capPoint) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+// insideLoop reports whether p is inside loop, by the even-odd rule.
+func insideLoop(loop []capPoint, p capPoint) bool {
+	inside := false
+	for i, a := range loop {
+		b := loop[(i+1)%len(loop)]
+		if (a.y > p.y) != (b.y > p.y) && p.x < a.x+(p.y-a.y)*(b.x-a.x)/(b.y-a.y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// bridgeHole joins hole into the outline around it with a two-way cut from
+// the hole's rightmost point to an outline point it can see, so the two
+// triangulate as one outline.
+func bridgeHole(outline, hole []capPoint) []capPoint {
+	h := 0
+	for i, p := range hole {
+		if p.x > hole[h].x {
+			h = i
+		}
+	}
+	hp := hole[h]
+
+	// The nearest outline edge a ray to the right of hp crosses, and its
+	// end farther right
+	best, bestX := -1, math.Inf(1)
+	for i, a := range outline {
+		b := outline[(i+1)%len(outline)]
+		if (a.y > hp.y) == (b.y > hp.y) {
+			continue
+		}
+		x := a.x + (hp.y-a.y)*(b.x-a.x)/(b.y-a.y)
+		if x >= hp.x && x < bestX {
+			bestX = x
+			best = i
+			if b.x > a.x {
+				best = (i + 1) % len(outline)
+			}
+		}
+	}
+	if best < 0 {
+		return outline
+	}
+
+	// An outline corner inside the triangle from hp to the crossing and
+	// that end would block the bridge; take the one nearest the ray
+	hit := capPoint{x: bestX, y: hp.y}
+	p := outline[best]
+	for i, c := range outline {
+		if i == best || c.x < hp.x {
+			continue
+		}
+		if inTriangle(c, hp, hit, p) || inTriangle(c, hp, p, hit) {
+			if math.Abs(c.y-hp.y)*(p.x-hp.x) < math.Abs(p.y-hp.y)*(c.x-hp.x) {
+				best, p = i, c
+			}
+		}
+	}
+
+	joined := make([]capPoint, 0, len(outline)+len(hole)+2)
+	joined = append(joined, outline[:best+1]...)
+	joined = append(joined, hole[h:]...)
+	joined = append(joined, hole[:h+1]...)
+	return append(joined, outline[best:]...)
+}
+
+// inTriangle reports whether p is inside or on counter-clockwise triangle
+// a, b, c.
+func inTriangle(p, a, b, c capPoint) bool {
+	return orient(a, b, p) >= 0 && orient(b, c, p) >= 0 && orient(c, a, p) >= 0
+}
+
+// earClip triangulates a simple outline wound counter-clockwise if ccw, or
+// clockwise if not, into triangles wound the same way.
+func earClip(outline []capPoint, ccw bool) [][3]capPoint {
+	sign := 1.0
+	if !ccw {
+		sign = -1
+	}
+	pts := append([]capPoint(nil), outline...)
+	var tris [][3]capPoint
+	for len(pts) > 3 {
+		clipped := false
+		for i := range pts {
+			a, b, c := pts[(i+len(pts)-1)%len(pts)], pts[i], pts[(i+1)%len(pts)]
+			if sign*orient(a, b, c) <= 0 {
+				continue // Reflex or degenerate
+			}
+			ear := true
+			for _, p := range pts {
+				if p.pos == a.pos || p.pos == b.pos || p.pos == c.pos {
+					continue
+				}
+				if (ccw && inTriangle(p, a, b, c)) || (!ccw && inTriangle(p, c, b, a)) {
+					ear = false
+					break
+				}
+			}
+			if ear {
+				tris = append(tris, [3]capPoint{a, b, c})
+				pts = append(pts[:i], pts[i+1:]...)
+				clipped = true
+				break
+			}
+		}
+		if !clipped {
+			// Rounding left no clean ear; drop a collinear corner, or give
+			// up on what's left rather than loop forever
+			for i := range pts {
+				a, b, c := pts[(i+len(pts)-1)%len(pts)], pts[i], pts[(i+1)%len(pts)]
+				if orient(a, b, c) == 0 {
+					pts = append(pts[:i], pts[i+1:]...)
+					clipped = true
+					break
+				}
+			}
+			if !clipped {
+				return tris
+			}
+		}
+	}
+	if len(pts) == 3 && sign*orient(pts[0], pts[1], pts[2]) > 0 {
+		tris = append(tris, [3]capPoint{pts[0], pts[1], pts[2]})
+	}
+	return tris
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestSection(t *testing.T) {
+	mesh := NewMesh("test")
+	mesh.Vertices = []MeshVertex{
+		{Position: math3d.V3(-1, 0, 0)},
+		{Position: math3d.V3(1, 0, 0)},
+		{Position: math3d.V3(-1, 1, 0)},
+		{Position: math3d.V3(2, 0, 0)},
+		{Position: math3d.V3(2, 1, 0)},
+	}
+	mesh.Faces = []Face{
+		{V: [3]int{0, 1, 2}, Material: 3, Group: 1}, // Crosses x = 0
+		{V: [3]int{1, 3, 4}},                        // Wholly cut away
+	}
+	mesh.Points = []int{0, 3}
+	mesh.Lines = [][2]int{{0, 2}, {0, 1}}
+
+	normal := func(m *Mesh, f Face) math3d.Vec3 {
+		a, b, c := m.Vertices[f.V[0]].Position, m.Vertices[f.V[1]].Position, m.Vertices[f.V[2]].Position
+		return b.Sub(a).Cross(c.Sub(a))
+	}
+	want := normal(mesh, mesh.Faces[0])
+
+	section := mesh.Section(math3d.V3(1, 0, 0), 0)
+	if len(section.Faces) != 2 {
+		t.Fatalf("faces = %d, want the crossing triangle clipped to 2", len(section.Faces))
+	}
+	for _, f := range section.Faces {
+		for _, vi := range f.V {
+			if x := section.Vertices[vi].Position.X; x > 1e-9 {
+				t.Errorf("vertex at x = %g, want none past the plane", x)
+			}
+		}
+		if n := normal(section, f); n.Dot(want) <= 0 {
+			t.Errorf("face %v flipped: normal %v, want along %v", f.V, n, want)
+		}
+		if f.Material != 3 || f.Group != 1 {
+			t.Errorf("face material %d group %d, want the original's 3 and 1", f.Material, f.Group)
+		}
+	}
+	if len(section.Points) != 1 || len(section.Lines) != 1 {
+		t.Errorf("points %v lines %v, want only those left of the plane", section.Points, section.Lines)
+	}
+	if len(mesh.Faces) != 2 || len(mesh.Vertices) != 5 {
+		t.Error("Section modified the original mesh")
+	}
+}
+
+func TestSectionCap(t *testing.T) {
+	// A box with a box-shaped cavity, wound inward
+	hollow := NewMesh("hollow")
+	for _, box := range []struct {
+		size float64
+		flip bool
+	}{{3, false}, {1, true}} {
+		cube := sharedCube()
+		base := len(hollow.Vertices)
+		for _, v := range cube.Vertices {
+			p := v.Position.Scale(box.size).Add(math3d.V3(1.5-box.size/2, 1.5-box.size/2, 1.5-box.size/2))
+			hollow.Vertices = append(hollow.Vertices, MeshVertex{Position: p})
+		}
+		for _, f := range cube.Faces {
+			if box.flip {
+				f.V[1], f.V[2] = f.V[2], f.V[1]
+			}
+			hollow.Faces = append(hollow.Faces, Face{V: [3]int{f.V[0] + base, f.V[1] + base, f.V[2] + base}})
+		}
+	}
+
+	sheet := NewMesh("sheet")
+	sheet.Vertices = []MeshVertex{
+		{Position: math3d.V3(0, 0, 0)},
+		{Position: math3d.V3(1, 0, 0)},
+		{Position: math3d.V3(1, 1, 0)},
+		{Position: math3d.V3(0, 1, 0)},
+	}
+	sheet.Faces = []Face{{V: [3]int{0, 1, 2}}, {V: [3]int{0, 2, 3}}}
+
+	tests := []struct {
+		name   string
+		mesh   *Mesh
+		normal math3d.Vec3
+		offset float64
+		area   float64
+	}{
+		{"cube", sharedCube(), math3d.V3(1, 0, 0), 0.5, 1},
+		{"through corners", sharedCube(), math3d.V3(1, -1, 0), 0, math.Sqrt2},
+		{"hollow", hollow, math3d.V3(1, 0, 0), 1.5, 8},
+		{"open", sheet, math3d.V3(1, 0, 0), 0.5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normal := tt.normal.Normalize()
+			capMesh := tt.mesh.SectionCap(tt.normal, tt.offset)
+			area := 0.0
+			for _, f := range capMesh.Faces {
+				a, b, c := capMesh.Vertices[f.V[0]].Position, capMesh.Vertices[f.V[1]].Position, capMesh.Vertices[f.V[2]].Position
+				area += b.Sub(a).Cross(c.Sub(a)).Len() / 2
+				for _, p := range []math3d.Vec3{a, b, c} {
+					if d := normal.Dot(p) - tt.offset/tt.normal.Len(); math.Abs(d) > 1e-9 {
+						t.Errorf("cap vertex %v is %g off the plane", p, d)
+					}
+				}
+			}
+			if math.Abs(area-tt.area) > 1e-9 {
+				t.Errorf("cap area = %g, want %g", area, tt.area)
+			}
+			if tt.area == 0 {
+				return
+			}
+
+			// Together the section and its cap are closed: every edge is
+			// crossed once each way
+			edges := make(map[[2]math3d.Vec3]int)
+			for _, m := range []*Mesh{tt.mesh.Section(tt.normal, tt.offset), capMesh} {
+				for _, f := range m.Faces {
+					for i := range 3 {
+						a, b := m.Vertices[f.V[i]].Position, m.Vertices[f.V[(i+1)%3]].Position
+						edges[[2]math3d.Vec3{a, b}]++
+					}
+				}
+			}
+			for e, n := range edges {
+				if back := edges[[2]math3d.Vec3{e[1], e[0]}]; back != n {
+					t.Errorf("edge %v crossed %d times, back %d times", e, n, back)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// Smooth applies iterations of Laplacian smoothing, moving each vertex
+// lambda (0-1) of the way toward the average of its neighbors to even out
+// noise in scanned meshes. With keepBoundary, vertices on open edges stay
+// put so holes and outlines don't shrink. Normals are recomputed smooth.
+func (m *Mesh) Smooth(iterations int, lambda float64, keepBoundary bool) {
+	if iterations <= 0 || len(m.Faces) == 0 {
+		return
+	}
+
+	adj := m.buildAdjacency()
+	fixed := make([]bool, len(adj.pos))
+	if keepBoundary {
+		for c := range adj.pos {
+			fixed[c] = len(adj.boundaryNeighbors(c)) > 0
+		}
+	}
+
+	pos := adj.pos
+	next := make([]math3d.Vec3, len(pos))
+	for range iterations {
+		for c, ring := range adj.neighbors {
+			if fixed[c] || len(ring) == 0 {
+				next[c] = pos[c]
+				continue
+			}
+			avg := math3d.Zero3()
+			for _, n := range ring {
+				avg = avg.Add(pos[n])
+			}
+			avg = avg.Scale(1 / float64(len(ring)))
+			next[c] = pos[c].Lerp(avg, lambda)
+		}
+		pos, next = next, pos
+	}
+
+	for i := range m.Vertices {
+		m.Vertices[i].Position = pos[adj.cluster[i]]
+	}
+	m.smoothClusterNormals(adj.cluster, len(pos))
+	m.CalculateBounds()
+}
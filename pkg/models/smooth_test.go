@@ -0,0 +1,91 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// radiusSpread returns the standard deviation of vertex distances from the origin.
+func radiusSpread(m *Mesh) float64 {
+	var sum, sumSq float64
+	for _, v := range m.Vertices {
+		r := v.Position.Len()
+		sum += r
+		sumSq += r * r
+	}
+	n := float64(len(m.Vertices))
+	return math.Sqrt(sumSq/n - (sum/n)*(sum/n))
+}
+
+func TestSmoothReducesNoise(t *testing.T) {
+	mesh := uvSphere(16, 32)
+
+	// Jitter each position, moving seam duplicates together
+	rng := rand.New(rand.NewSource(3))
+	jitter := make(map[math3d.Vec3]float64)
+	for i, v := range mesh.Vertices {
+		s, ok := jitter[v.Position]
+		if !ok {
+			s = 1 + (rng.Float64()-0.5)*0.1
+			jitter[v.Position] = s
+		}
+		mesh.Vertices[i].Position = v.Position.Scale(s)
+	}
+
+	before := radiusSpread(mesh)
+	mesh.Smooth(5, 0.5, true)
+	after := radiusSpread(mesh)
+	if after > before/2 {
+		t.Errorf("radius spread %.4f -> %.4f, want at least halved", before, after)
+	}
+
+	// Seam duplicates move together, so the surface stays closed
+	for i := 0; i < len(mesh.Vertices); i += 33 {
+		first, last := mesh.Vertices[i], mesh.Vertices[i+32]
+		if first.Position != last.Position {
+			t.Fatalf("seam vertices %d and %d split: %v vs %v", i, i+32, first.Position, last.Position)
+		}
+	}
+}
+
+func TestSmoothBoundary(t *testing.T) {
+	tests := []struct {
+		name         string
+		keepBoundary bool
+		wantMoved    bool
+	}{
+		{"keep boundary", true, false},
+		{"free boundary", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := grid(4)
+			// Bump the middle so there's something to smooth
+			center := mesh.Vertices[2*5+2].Position
+			mesh.Vertices[2*5+2].Position = center.Add(math3d.V3(0, 0, 1))
+			corner := mesh.Vertices[0].Position
+
+			mesh.Smooth(3, 0.5, tt.keepBoundary)
+
+			if z := mesh.Vertices[2*5+2].Position.Z; z >= 1 || z <= 0 {
+				t.Errorf("bump height = %.3f, want lowered but not flattened", z)
+			}
+			if moved := mesh.Vertices[0].Position != corner; moved != tt.wantMoved {
+				t.Errorf("corner moved = %v, want %v", moved, tt.wantMoved)
+			}
+		})
+	}
+}
+
+func TestSmoothNoop(t *testing.T) {
+	mesh := grid(2)
+	mesh.Vertices[4].Position.Z = 1
+	mesh.Smooth(0, 0.5, false)
+	if mesh.Vertices[4].Position.Z != 1 {
+		t.Error("Smooth(0, ...) moved a vertex")
+	}
+}
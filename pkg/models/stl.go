@@ -63,6 +63,9 @@ func (l *STLLoader) LoadFile(path string) (*Mesh, error) {
 
 // LoadBytes parses STL from a byte slice.
 func (l *STLLoader) LoadBytes(data []byte, name string) (*Mesh, error) {
+	if isLFSPointer(data) {
+		return nil, ErrLFSPointer
+	}
 	if isBinarySTL(data) {
 		return l.loadBinary(data, name)
 	}
@@ -187,6 +190,7 @@ func (l *STLLoader) loadBinary(data []byte, name string) (*Mesh, error) {
 		}
 	}
 
+	mesh.Winding = WindingCW // Facets were reversed as they were read
 	mesh.CalculateBounds()
 
 	if l.SmoothNormals {
@@ -346,6 +350,7 @@ func (l *STLLoader) loadASCII(data []byte, name string) (*Mesh, error) {
 		}
 	}
 
+	mesh.Winding = WindingCW // Facets were reversed as they were read
 	mesh.CalculateBounds()
 
 	if l.SmoothNormals {
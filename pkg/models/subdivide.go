@@ -0,0 +1,104 @@
+package models
+
+import (
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// Subdivide splits every triangle into four, levels times, moving vertices
+// toward the limit of Loop subdivision so faceted models come out smooth.
+// Open edges follow the boundary curve. Texture coordinates are
+// interpolated, and normals are recomputed smooth across UV seams.
+func (m *Mesh) Subdivide(levels int) {
+	for range levels {
+		m.subdivideOnce()
+	}
+	if levels > 0 {
+		m.CalculateBounds()
+	}
+}
+
+// subdivideOnce applies one level of Loop subdivision.
+func (m *Mesh) subdivideOnce() {
+	if len(m.Faces) == 0 {
+		return
+	}
+
+	// Geometry works on positions so seams move together
+	adj := m.buildAdjacency()
+	cluster, pos := adj.cluster, adj.pos
+
+	// Even (existing) points: a weighted average with their ring, or
+	// with their boundary neighbors on open edges
+	even := make([]math3d.Vec3, len(pos))
+	for v, ring := range adj.neighbors {
+		boundary := adj.boundaryNeighbors(v)
+		switch {
+		case len(boundary) == 2:
+			even[v] = pos[v].Scale(0.75).Add(pos[boundary[0]].Add(pos[boundary[1]]).Scale(0.125))
+		case len(boundary) > 0 || len(ring) < 3:
+			even[v] = pos[v] // Corners and non-manifold junctions stay put
+		default:
+			n := float64(len(ring))
+			beta := 3.0 / (8 * n)
+			if len(ring) == 3 {
+				beta = 3.0 / 16
+			}
+			sum := math3d.Zero3()
+			for _, nb := range ring {
+				sum = sum.Add(pos[nb])
+			}
+			even[v] = pos[v].Scale(1 - n*beta).Add(sum.Scale(beta))
+		}
+	}
+
+	// Odd (edge) points, shared by faces that share the vertex pair so
+	// UV seams keep separate midpoints
+	oldCount := len(m.Vertices)
+	odd := make(map[posEdge]int)  // Position edge to its point's cluster
+	mids := make(map[posEdge]int) // Vertex edge to its midpoint vertex
+	midpoint := func(i, j int) int {
+		if idx, ok := mids[edgeKey(i, j)]; ok {
+			return idx
+		}
+		a, b := cluster[i], cluster[j]
+		c, ok := odd[edgeKey(a, b)]
+		if !ok {
+			p := pos[a].Add(pos[b]).Scale(0.5)
+			if opp := adj.opposite[edgeKey(a, b)]; len(opp) == 2 {
+				p = pos[a].Add(pos[b]).Scale(0.375).Add(pos[opp[0]].Add(pos[opp[1]]).Scale(0.125))
+			}
+			c = len(even)
+			even = append(even, p)
+			odd[edgeKey(a, b)] = c
+		}
+		idx := len(m.Vertices)
+		m.Vertices = append(m.Vertices, MeshVertex{
+			Position: even[c],
+			UV:       m.Vertices[i].UV.Lerp(m.Vertices[j].UV, 0.5),
+			UV1:      m.Vertices[i].UV1.Lerp(m.Vertices[j].UV1, 0.5),
+		})
+		cluster = append(cluster, c)
+		mids[edgeKey(i, j)] = idx
+		return idx
+	}
+
+	faces := make([]Face, 0, 4*len(m.Faces))
+	for _, f := range m.Faces {
+		m01 := midpoint(f.V[0], f.V[1])
+		m12 := midpoint(f.V[1], f.V[2])
+		m20 := midpoint(f.V[2], f.V[0])
+		faces = append(faces,
+			Face{V: [3]int{f.V[0], m01, m20}, Material: f.Material, Group: f.Group, Smoothing: f.Smoothing},
+			Face{V: [3]int{f.V[1], m12, m01}, Material: f.Material, Group: f.Group, Smoothing: f.Smoothing},
+			Face{V: [3]int{f.V[2], m20, m12}, Material: f.Material, Group: f.Group, Smoothing: f.Smoothing},
+			Face{V: [3]int{m01, m12, m20}, Material: f.Material, Group: f.Group, Smoothing: f.Smoothing},
+		)
+	}
+	m.Faces = faces
+	for i := range oldCount {
+		m.Vertices[i].Position = even[cluster[i]]
+	}
+
+	// Smooth normals per position, so seams don't show as creases
+	m.smoothClusterNormals(cluster, len(even))
+}
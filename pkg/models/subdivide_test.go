@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestSubdivideCounts(t *testing.T) {
+	tests := []struct {
+		levels    int
+		wantFaces int
+		wantVerts int
+	}{
+		{0, 1, 3},
+		{1, 4, 6},
+		{2, 16, 15},
+		{3, 64, 45},
+	}
+
+	for _, tt := range tests {
+		mesh := NewMesh("triangle")
+		mesh.Vertices = []MeshVertex{
+			{Position: math3d.V3(0, 0, 0), UV: math3d.V2(0, 0)},
+			{Position: math3d.V3(1, 0, 0), UV: math3d.V2(1, 0)},
+			{Position: math3d.V3(0, 1, 0), UV: math3d.V2(0, 1)},
+		}
+		mesh.Faces = []Face{{V: [3]int{0, 1, 2}, Material: 2}}
+
+		mesh.Subdivide(tt.levels)
+		if mesh.TriangleCount() != tt.wantFaces || mesh.VertexCount() != tt.wantVerts {
+			t.Errorf("Subdivide(%d): %d faces, %d vertices; want %d, %d",
+				tt.levels, mesh.TriangleCount(), mesh.VertexCount(), tt.wantFaces, tt.wantVerts)
+		}
+		for i, f := range mesh.Faces {
+			if f.Material != 2 {
+				t.Errorf("Subdivide(%d): face %d material = %d, want 2", tt.levels, i, f.Material)
+			}
+		}
+		// A flat patch stays flat, and interpolated UVs stay inside the original triangle
+		for i, v := range mesh.Vertices {
+			if v.Position.Z != 0 || v.UV.X < 0 || v.UV.Y < 0 || v.UV.X+v.UV.Y > 1+1e-9 {
+				t.Errorf("Subdivide(%d): vertex %d = %+v", tt.levels, i, v)
+			}
+		}
+	}
+}
+
+func TestSubdivideSphere(t *testing.T) {
+	mesh := uvSphere(6, 12)
+	before := mesh.TriangleCount()
+	mesh.Subdivide(2)
+
+	if got, want := mesh.TriangleCount(), before*16; got != want {
+		t.Fatalf("TriangleCount = %d, want %d", got, want)
+	}
+
+	// Loop subdivision shrinks a convex hull slightly but keeps it round
+	for i, v := range mesh.Vertices {
+		if r := v.Position.Len(); r > 1+1e-9 || r < 0.85 {
+			t.Fatalf("vertex %d at radius %.3f, want in [0.85, 1]", i, r)
+		}
+		if d := v.Normal.Dot(v.Position.Normalize()); d < 0.95 {
+			t.Fatalf("vertex %d normal %v points away from the surface", i, v.Normal)
+		}
+	}
+
+	// Vertices split along the UV seam move and shade together
+	byPos := make(map[math3d.Vec3]math3d.Vec3)
+	for i, v := range mesh.Vertices {
+		if n, ok := byPos[v.Position]; ok && n != v.Normal {
+			t.Fatalf("vertex %d normal %v differs from %v at the same position", i, v.Normal, n)
+		}
+		byPos[v.Position] = v.Normal
+	}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"math"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// CalculateTangents fills each vertex's Tangent from the direction its UVs
+// increase in U, orthogonalized against the normal, with the bitangent's
+// handedness in W. Vertices whose faces have no usable UVs, including every
+// vertex of a mesh without UVs, get an arbitrary tangent perpendicular to
+// the normal instead, so normal mapping still has a basis to work in.
+// Returns how many vertices fell back; ApproxTangents records whether any did.
+// Call it after normals are final, and again after editing the mesh.
+func (m *Mesh) CalculateTangents() int {
+	tan := make([]math3d.Vec3, len(m.Vertices))
+	bitan := make([]math3d.Vec3, len(m.Vertices))
+
+	for _, f := range m.Faces {
+		v0, v1, v2 := m.Vertices[f.V[0]], m.Vertices[f.V[1]], m.Vertices[f.V[2]]
+		e1 := v1.Position.Sub(v0.Position)
+		e2 := v2.Position.Sub(v0.Position)
+		du1, dv1 := v1.UV.X-v0.UV.X, v1.UV.Y-v0.UV.Y
+		du2, dv2 := v2.UV.X-v0.UV.X, v2.UV.Y-v0.UV.Y
+
+		det := du1*dv2 - du2*dv1
+		if math.Abs(det) < 1e-12 {
+			continue // UVs collapsed to a line or point: no U direction
+		}
+		t := e1.Scale(dv2).Sub(e2.Scale(dv1)).Scale(1 / det)
+		b := e2.Scale(du1).Sub(e1.Scale(du2)).Scale(1 / det)
+		for _, vi := range f.V {
+			tan[vi] = tan[vi].Add(t)
+			bitan[vi] = bitan[vi].Add(b)
+		}
+	}
+
+	approx := 0
+	for i := range m.Vertices {
+		n := m.Vertices[i].Normal.Normalize()
+		if n.LenSq() == 0 {
+			n = math3d.Up()
+		}
+
+		// Gram-Schmidt: drop the part of the tangent along the normal
+		t := tan[i].Sub(n.Scale(n.Dot(tan[i])))
+		if t.Len() < 1e-9 {
+			m.Vertices[i].Tangent = math3d.V4FromV3(perpendicular(n), 1)
+			approx++
+			continue
+		}
+		t = t.Normalize()
+
+		w := 1.0
+		if n.Cross(t).Dot(bitan[i]) < 0 {
+			w = -1
+		}
+		m.Vertices[i].Tangent = math3d.V4FromV3(t, w)
+	}
+
+	m.ApproxTangents = approx > 0
+	return approx
+}
+
+// perpendicular returns a unit vector perpendicular to unit vector n,
+// crossing with whichever axis is least parallel to it.
+func perpendicular(n math3d.Vec3) math3d.Vec3 {
+	axis := math3d.V3(1, 0, 0)
+	if math.Abs(n.X) > 0.9 {
+		axis = math3d.V3(0, 1, 0)
+	}
+	return axis.Sub(n.Scale(n.Dot(axis))).Normalize()
+}
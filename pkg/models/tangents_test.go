@@ -0,0 +1,68 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestCalculateTangents(t *testing.T) {
+	tests := []struct {
+		name       string
+		uv         func(p math3d.Vec3) math3d.Vec2
+		wantTan    math3d.Vec3 // Zero when any perpendicular will do
+		wantW      float64
+		wantApprox bool
+	}{
+		{"u along x", func(p math3d.Vec3) math3d.Vec2 { return math3d.V2(p.X, p.Y) }, math3d.V3(1, 0, 0), 1, false},
+		{"u along y", func(p math3d.Vec3) math3d.Vec2 { return math3d.V2(p.Y, p.X) }, math3d.V3(0, 1, 0), -1, false},
+		{"mirrored u", func(p math3d.Vec3) math3d.Vec2 { return math3d.V2(1-p.X, p.Y) }, math3d.V3(-1, 0, 0), -1, false},
+		{"no uvs", func(p math3d.Vec3) math3d.Vec2 { return math3d.Vec2{} }, math3d.Vec3{}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := grid(4)
+			for i := range mesh.Vertices {
+				mesh.Vertices[i].Normal = math3d.V3(0, 0, 1)
+				mesh.Vertices[i].UV = tt.uv(mesh.Vertices[i].Position)
+			}
+
+			approx := mesh.CalculateTangents()
+			if (approx > 0) != tt.wantApprox || mesh.ApproxTangents != tt.wantApprox {
+				t.Errorf("CalculateTangents() = %d, ApproxTangents = %v; want approximate %v",
+					approx, mesh.ApproxTangents, tt.wantApprox)
+			}
+			for i, v := range mesh.Vertices {
+				tan := v.Tangent.Vec3()
+				if math.Abs(tan.Len()-1) > 1e-9 || math.Abs(tan.Dot(v.Normal)) > 1e-9 {
+					t.Fatalf("vertex %d tangent %v is not a unit vector perpendicular to %v", i, tan, v.Normal)
+				}
+				if tt.wantTan != (math3d.Vec3{}) && tan.Sub(tt.wantTan).Len() > 1e-9 {
+					t.Fatalf("vertex %d tangent = %v, want %v", i, tan, tt.wantTan)
+				}
+				if v.Tangent.W != tt.wantW {
+					t.Fatalf("vertex %d handedness = %v, want %v", i, v.Tangent.W, tt.wantW)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateTangentsSphereWithoutUVs(t *testing.T) {
+	mesh := uvSphere(8, 16)
+	for i := range mesh.Vertices {
+		mesh.Vertices[i].UV = math3d.Vec2{}
+	}
+
+	if approx := mesh.CalculateTangents(); approx != len(mesh.Vertices) {
+		t.Errorf("CalculateTangents() = %d, want all %d vertices approximate", approx, len(mesh.Vertices))
+	}
+	for i, v := range mesh.Vertices {
+		tan := v.Tangent.Vec3()
+		if math.Abs(tan.Len()-1) > 1e-9 || math.Abs(tan.Dot(v.Normal.Normalize())) > 1e-9 {
+			t.Fatalf("vertex %d tangent %v is not a unit vector perpendicular to %v", i, tan, v.Normal)
+		}
+	}
+}
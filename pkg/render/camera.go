@@ -28,6 +28,7 @@ type Camera struct {
 	viewProjMatrix math3d.Mat4
 	viewDirty      bool
 	projDirty      bool
+	viewProjDirty  bool // Set whenever either matrix is recomputed
 }
 
 // NewCamera creates a new camera with default settings.
@@ -108,6 +109,7 @@ func (c *Camera) ViewMatrix() math3d.Mat4 {
 	if c.viewDirty {
 		c.computeViewMatrix()
 		c.viewDirty = false
+		c.viewProjDirty = true
 	}
 	return c.viewMatrix
 }
@@ -117,16 +119,19 @@ func (c *Camera) ProjectionMatrix() math3d.Mat4 {
 	if c.projDirty {
 		c.computeProjectionMatrix()
 		c.projDirty = false
+		c.viewProjDirty = true
 	}
 	return c.projMatrix
 }
 
 // ViewProjectionMatrix returns the combined view-projection matrix.
 func (c *Camera) ViewProjectionMatrix() math3d.Mat4 {
-	if c.viewDirty || c.projDirty {
-		_ = c.ViewMatrix()
-		_ = c.ProjectionMatrix()
-		c.viewProjMatrix = c.projMatrix.Mul(c.viewMatrix)
+	// Either matrix may already have been recomputed by its own getter,
+	// which is why the combined one has a flag of its own
+	view, proj := c.ViewMatrix(), c.ProjectionMatrix()
+	if c.viewProjDirty {
+		c.viewProjMatrix = proj.Mul(view)
+		c.viewProjDirty = false
 	}
 	return c.viewProjMatrix
 }
@@ -168,6 +173,41 @@ func (c *Camera) MoveUp(distance float64) {
 	c.viewDirty = true
 }
 
+// Pan moves the camera along its right and up vectors.
+// dx and dy are fractions of the view height measured at the depth of the
+// world origin, so the scene there tracks the cursor at any zoom level.
+func (c *Camera) Pan(dx, dy float64) {
+	distance := math.Abs(c.Position.Dot(c.Forward()))
+	viewHeight := 2 * distance * math.Tan(c.FOV/2)
+
+	offset := c.Right().Scale(dx * viewHeight).Add(c.Up().Scale(dy * viewHeight))
+	c.Position = c.Position.Add(offset)
+	c.viewDirty = true
+}
+
+// FitDistance returns how far from a sphere of the given radius the camera
+// must be for the sphere to fit the narrower of its two fields of view.
+func (c *Camera) FitDistance(radius float64) float64 {
+	halfFOV := c.FOV / 2
+	if c.AspectRatio < 1 {
+		// Portrait: horizontal FOV is the narrower one
+		halfFOV = math.Atan(math.Tan(halfFOV) * c.AspectRatio)
+	}
+	return radius / math.Sin(halfFOV)
+}
+
+// FrameBounds backs the camera away from the center of bounds along its
+// current view direction until the bounding sphere fits, scaled by margin
+// (1 = touching the edges), and looks at the center. Returns the distance.
+func (c *Camera) FrameBounds(bounds AABB, margin float64) float64 {
+	center := bounds.Center()
+	distance := c.FitDistance(bounds.HalfSize().Len()) * margin
+
+	c.Position = center.Sub(c.Forward().Scale(distance))
+	c.LookAt(center)
+	return distance
+}
+
 // Rotate rotates the camera by the given angles (in radians).
 func (c *Camera) Rotate(deltaPitch, deltaYaw, deltaRoll float64) {
 	c.Pitch += deltaPitch
@@ -223,3 +263,18 @@ func (c *Camera) WorldToScreen(worldPos math3d.Vec3, screenWidth, screenHeight i
 
 	return x, y, depth, true
 }
+
+// ScreenToRay returns the world-space ray through screen point (x, y).
+// It is the inverse of WorldToScreen: every point along the ray projects
+// back to (x, y). The direction is normalized.
+func (c *Camera) ScreenToRay(x, y float64, screenWidth, screenHeight int) (origin, dir math3d.Vec3) {
+	// Screen to NDC (-1 to 1), Y is flipped
+	ndcX := x/float64(screenWidth)*2 - 1
+	ndcY := 1 - y/float64(screenHeight)*2
+
+	// Unproject a point on the far plane
+	inv := c.ViewProjectionMatrix().Inverse()
+	far := inv.MulVec4(math3d.V4(ndcX, ndcY, 1, 1)).PerspectiveDivide()
+
+	return c.Position, far.Sub(c.Position).Normalize()
+}
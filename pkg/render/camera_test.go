@@ -0,0 +1,142 @@
+package render
+
+import (
+	"math"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestScreenToRay(t *testing.T) {
+	cam := NewCamera()
+	cam.SetPosition(math3d.V3(1, 2, 5))
+	cam.LookAt(math3d.V3(0, 0, 0))
+	cam.SetAspectRatio(2)
+
+	const w, h = 200, 100
+
+	t.Run("center ray is forward", func(t *testing.T) {
+		origin, dir := cam.ScreenToRay(w/2, h/2, w, h)
+		if origin != cam.Position {
+			t.Errorf("origin = %v, want %v", origin, cam.Position)
+		}
+		if d := dir.Sub(cam.Forward()).Len(); d > 1e-9 {
+			t.Errorf("dir = %v, want %v", dir, cam.Forward())
+		}
+	})
+
+	t.Run("round trip with WorldToScreen", func(t *testing.T) {
+		points := [][2]float64{{10, 10}, {150, 30}, {60, 90}}
+		for _, p := range points {
+			origin, dir := cam.ScreenToRay(p[0], p[1], w, h)
+			world := origin.Add(dir.Scale(4))
+			x, y, _, visible := cam.WorldToScreen(world, w, h)
+			if !visible {
+				t.Fatalf("point on ray through %v not visible", p)
+			}
+			if math.Abs(x-p[0]) > 1e-6 || math.Abs(y-p[1]) > 1e-6 {
+				t.Errorf("ray through %v projects back to (%v, %v)", p, x, y)
+			}
+		}
+	})
+}
+
+func TestPan(t *testing.T) {
+	cam := NewCamera()
+	cam.SetPosition(math3d.V3(0, 0, 5))
+	cam.LookAt(math3d.V3(0, 0, 0))
+	cam.SetAspectRatio(1)
+
+	const size = 100
+
+	// Panning by a quarter view height should shift the origin by a quarter
+	// of the screen in the opposite direction
+	cam.Pan(0.25, -0.25)
+	x, y, _, visible := cam.WorldToScreen(math3d.Zero3(), size, size)
+	if !visible {
+		t.Fatal("origin should stay visible")
+	}
+	if math.Abs(x-25) > 1e-6 || math.Abs(y-25) > 1e-6 {
+		t.Errorf("origin at (%v, %v), want (25, 25)", x, y)
+	}
+
+	// Panning must not change the viewing direction
+	if d := cam.Forward().Sub(math3d.V3(0, 0, -1)).Len(); d > 1e-9 {
+		t.Errorf("forward changed to %v", cam.Forward())
+	}
+}
+
+func TestFrameBounds(t *testing.T) {
+	for _, aspect := range []float64{2, 0.5} {
+		cam := NewCamera()
+		cam.SetPosition(math3d.V3(0, 0, 5))
+		cam.LookAt(math3d.V3(0, 0, 0))
+		cam.SetAspectRatio(aspect)
+
+		bounds := NewAABB(math3d.V3(9, -1, -3), math3d.V3(11, 3, 1))
+		dist := cam.FrameBounds(bounds, 1)
+
+		radius := bounds.HalfSize().Len()
+		if want := cam.FitDistance(radius); math.Abs(dist-want) > 1e-9 {
+			t.Errorf("aspect %v: distance = %v, want %v", aspect, dist, want)
+		}
+		if d := cam.Position.Sub(bounds.Center()).Len(); math.Abs(d-dist) > 1e-9 {
+			t.Errorf("aspect %v: camera is %v from center, want %v", aspect, d, dist)
+		}
+
+		// Every corner fits on screen
+		for i := range 8 {
+			corner := math3d.V3(bounds.Min.X, bounds.Min.Y, bounds.Min.Z)
+			if i&1 != 0 {
+				corner.X = bounds.Max.X
+			}
+			if i&2 != 0 {
+				corner.Y = bounds.Max.Y
+			}
+			if i&4 != 0 {
+				corner.Z = bounds.Max.Z
+			}
+			if _, _, _, visible := cam.WorldToScreen(corner, 100, 100); !visible {
+				t.Errorf("aspect %v: corner %v off screen", aspect, corner)
+			}
+		}
+	}
+}
+
+func TestViewProjectionMatrixStaysCurrent(t *testing.T) {
+	c := NewCamera()
+	steps := []struct {
+		name   string
+		change func()
+	}{
+		{"fov", func() { c.SetFOV(0.8) }},
+		{"position", func() { c.SetPosition(math3d.V3(1, 2, 3)) }},
+		{"aspect", func() { c.SetAspectRatio(2) }},
+		{"rotation", func() { c.SetRotation(0.2, 0.5, 0) }},
+		{"look at", func() { c.LookAt(math3d.V3(4, 0, -2)) }},
+		{"clip planes", func() { c.SetClipPlanes(0.5, 50) }},
+	}
+
+	r := NewRasterizer(c, NewFramebuffer(8, 8))
+	for i, step := range steps {
+		step.change()
+		// Fetch the parts first on alternate steps, which clears their own
+		// dirty flags before the combined matrix is asked for
+		if i%2 == 0 {
+			c.ProjectionMatrix()
+			c.ViewMatrix()
+		}
+
+		fresh := *c
+		fresh.viewDirty, fresh.projDirty = true, true
+		want := fresh.ProjectionMatrix().Mul(fresh.ViewMatrix())
+		if got := c.ViewProjectionMatrix(); got != want {
+			t.Fatalf("after %s: ViewProjectionMatrix() is stale", step.name)
+		}
+
+		r.InvalidateFrustum()
+		if got := r.GetFrustum(); got != ExtractFrustum(want) {
+			t.Fatalf("after %s: frustum doesn't match the camera", step.name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package render
+
+import "math"
+
+// depthTile is the side, in pixels, of the tiles the depth buffer is
+// summarized in for coarse occlusion tests.
+const depthTile = 8
+
+// depthTiles keeps the farthest depth in each tile of the depth buffer, so
+// a triangle nearer to nothing in a tile than what's already there can skip
+// the tile without testing its pixels.
+type depthTiles struct {
+	cols  int       // Tiles per row
+	far   []float32 // Farthest depth in each tile, never nearer than the truth
+	dirty []bool    // Tiles written by the triangle in progress, whose far depth is stale
+}
+
+func newDepthTiles(width, height int) depthTiles {
+	var t depthTiles
+	t.resize(width, height)
+	return t
+}
+
+// resize fits the tiles to a width by height depth buffer, reusing their
+// arrays when big enough. Call clear before using them.
+func (t *depthTiles) resize(width, height int) {
+	t.cols = (width + depthTile - 1) / depthTile
+	rows := (height + depthTile - 1) / depthTile
+	t.far = resized(t.far, t.cols*rows)
+	t.dirty = resized(t.dirty, t.cols*rows)
+	clear(t.dirty)
+}
+
+// clear resets every tile to the cleared depth.
+func (t *depthTiles) clear() {
+	for i := range t.far {
+		t.far[i] = math.MaxFloat32
+	}
+}
+
+// tile returns the index of the tile holding pixel (x, y).
+func (t *depthTiles) tile(x, y int) int {
+	return y/depthTile*t.cols + x/depthTile
+}
+
+// refreshTiles recomputes the far depth of the dirty tiles within the pixel
+// bounds, from the depth buffer. Bounds smaller than a tile are left dirty
+// for a later, larger triangle to refresh: on a dense mesh rescanning after
+// every few-pixel triangle costs more than the culling saves, and a stale
+// far depth only culls less.
+func (r *Rasterizer) refreshTiles(minX, minY, maxX, maxY int) {
+	if (maxX-minX+1)*(maxY-minY+1) < depthTile*depthTile {
+		return
+	}
+	t := &r.tiles
+	for ty := minY / depthTile; ty <= maxY/depthTile; ty++ {
+		for tx := minX / depthTile; tx <= maxX/depthTile; tx++ {
+			i := ty*t.cols + tx
+			if !t.dirty[i] {
+				continue
+			}
+			t.dirty[i] = false
+			far := float32(-math.MaxFloat32)
+			for y := ty * depthTile; y < min((ty+1)*depthTile, r.height); y++ {
+				row := r.zbuffer[y*r.width+tx*depthTile : y*r.width+min((tx+1)*depthTile, r.width)]
+				for _, z := range row {
+					far = max(far, z)
+				}
+			}
+			t.far[i] = far
+		}
+	}
+}
+
+// nearestDepth returns the depth of a triangle's nearest vertex, which no
+// pixel of it is nearer than, and whether it can be tested against tiles.
+// It can't if a vertex is behind the camera, where depth isn't meaningful.
+func (r *Rasterizer) nearestDepth(sv *[3]screenVertex) (float32, bool) {
+	if sv[0].W <= 0 || sv[1].W <= 0 || sv[2].W <= 0 {
+		return 0, false
+	}
+	return float32(min3(sv[0].Z, sv[1].Z, sv[2].Z)), true
+}
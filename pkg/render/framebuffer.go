@@ -5,7 +5,9 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"math"
 	"os"
+	"strings"
 )
 
 // Framebuffer is a 2D array of pixels that can be rendered to the terminal.
@@ -26,10 +28,32 @@ func NewFramebuffer(width, height int) *Framebuffer {
 	}
 }
 
+// Resize changes the framebuffer's size, reusing its pixel array when it's
+// big enough. The pixels' contents are undefined until the next Clear.
+func (fb *Framebuffer) Resize(width, height int) {
+	fb.Width, fb.Height = width, height
+	fb.Pixels = resized(fb.Pixels, width*height)
+}
+
+// resized returns s with length n, reallocating only if s lacks the
+// capacity.
+func resized[T any](s []T, n int) []T {
+	if cap(s) >= n {
+		return s[:n]
+	}
+	return make([]T, n)
+}
+
 // Clear fills the framebuffer with a solid color.
 func (fb *Framebuffer) Clear(c color.RGBA) {
-	for i := range fb.Pixels {
-		fb.Pixels[i] = c
+	// Use copy-doubling for faster clearing
+	n := len(fb.Pixels)
+	if n == 0 {
+		return
+	}
+	fb.Pixels[0] = c
+	for i := 1; i < n; i *= 2 {
+		copy(fb.Pixels[i:], fb.Pixels[:i])
 	}
 }
 
@@ -82,6 +106,113 @@ func (fb *Framebuffer) DrawLine(x0, y0, x1, y1 int, c color.RGBA) {
 	}
 }
 
+// BlendPixel mixes c into the pixel at (x, y) with the given coverage,
+// from 0 (unchanged) to 1 (replaced). A transparent pixel has no color to
+// mix with, so it's replaced if coverage is at least half and kept if not.
+func (fb *Framebuffer) BlendPixel(x, y int, c color.RGBA, alpha float64) {
+	if x < 0 || x >= fb.Width || y < 0 || y >= fb.Height || alpha <= 0 {
+		return
+	}
+	i := y*fb.Width + x
+	if fb.Pixels[i].A == 0 {
+		if alpha >= 0.5 {
+			fb.Pixels[i] = c
+		}
+		return
+	}
+	fb.Pixels[i] = lerpColor(fb.Pixels[i], c, min(alpha, 1))
+}
+
+// DrawLineWidth draws a line width pixels thick as parallel Bresenham
+// lines offset along the minor axis. Widths below 2 draw a plain line.
+func (fb *Framebuffer) DrawLineWidth(x0, y0, x1, y1, width int, c color.RGBA) {
+	if width < 2 {
+		fb.DrawLine(x0, y0, x1, y1, c)
+		return
+	}
+
+	// Offsets along the minor axis thin out diagonals, so add more of them
+	// to keep the width measured across the line
+	dx, dy := abs(x1-x0), abs(y1-y0)
+	n := width
+	if major := max(dx, dy); major > 0 {
+		g := float64(min(dx, dy)) / float64(major)
+		n = int(math.Round(float64(width) * math.Sqrt(1+g*g)))
+	}
+	for off := -(n - 1) / 2; off <= n/2; off++ {
+		if dx >= dy {
+			fb.DrawLine(x0, y0+off, x1, y1+off, c)
+		} else {
+			fb.DrawLine(x0+off, y0, x1+off, y1, c)
+		}
+	}
+}
+
+// DrawLineAA draws an anti-aliased line between pixel centers using
+// Xiaolin Wu's algorithm, blending each pixel by how much the line covers it.
+// Endpoints are continuous, so lines don't snap to the pixel grid as they move.
+func (fb *Framebuffer) DrawLineAA(x0, y0, x1, y1 float64, c color.RGBA) {
+	fb.DrawLineAAWidth(x0, y0, x1, y1, 1, c)
+}
+
+// DrawLineAAWidth draws an anti-aliased line width pixels thick, widening
+// Wu's two-pixel span to a band so only its edges are blended.
+func (fb *Framebuffer) DrawLineAAWidth(x0, y0, x1, y1, width float64, c color.RGBA) {
+	steep := math.Abs(y1-y0) > math.Abs(x1-x0)
+	if steep {
+		x0, y0, x1, y1 = y0, x0, y1, x1
+	}
+	if x0 > x1 {
+		x0, y0, x1, y1 = x1, y1, x0, y0
+	}
+	plot := func(x, y int, alpha float64) {
+		if steep {
+			x, y = y, x
+		}
+		fb.BlendPixel(x, y, c, alpha)
+	}
+	gradient := 1.0
+	if dx := x1 - x0; dx > 0 {
+		gradient = (y1 - y0) / dx
+	}
+
+	// The band is measured across the line, so it spans more of each
+	// column as the line tilts
+	half := width / 2 * math.Sqrt(1+gradient*gradient)
+	column := func(x int, y, weight float64) {
+		lo, hi := y-half, y+half
+		for yi := int(math.Floor(lo + 0.5)); yi <= int(math.Floor(hi+0.5)); yi++ {
+			// Pixel yi spans [yi-0.5, yi+0.5]
+			cover := min(hi, float64(yi)+0.5) - max(lo, float64(yi)-0.5)
+			plot(x, yi, cover*weight)
+		}
+	}
+
+	// Endpoints are weighted by how much of their pixel the line reaches into
+	endpoint := func(x, y, xgap float64) int {
+		px := math.Round(x)
+		column(int(px), y+gradient*(px-x), xgap)
+		return int(px)
+	}
+	start := endpoint(x0, y0, 1-fract(x0+0.5))
+	end := endpoint(x1, y1, fract(x1+0.5))
+
+	// Only walk the span that can land on screen; far off-screen vertices
+	// would otherwise cost millions of clipped plots
+	limit := fb.Width
+	if steep {
+		limit = fb.Height
+	}
+	for x := max(start+1, 0); x < min(end, limit); x++ {
+		column(x, y0+gradient*(float64(x)-x0), 1)
+	}
+}
+
+// fract returns the fractional part of x, in [0, 1).
+func fract(x float64) float64 {
+	return x - math.Floor(x)
+}
+
 // DrawRect draws a filled rectangle.
 func (fb *Framebuffer) DrawRect(x, y, w, h int, c color.RGBA) {
 	for py := y; py < y+h; py++ {
@@ -123,6 +254,58 @@ func (fb *Framebuffer) ToImage() *image.RGBA {
 	return img
 }
 
+// asciiRamp orders characters from darkest to brightest.
+const asciiRamp = " .:-=+*#%@"
+
+// ASCII returns the framebuffer as plain text with no escape codes.
+// Each character covers two rows, matching the half-block layout, and is
+// chosen by the pair's average luminance.
+func (fb *Framebuffer) ASCII() string {
+	var sb strings.Builder
+	sb.Grow((fb.Width + 1) * (fb.Height + 1) / 2)
+
+	for row := 0; row*2 < fb.Height; row++ {
+		for x := 0; x < fb.Width; x++ {
+			top, bot := fb.cellPixels(x, row)
+			lum := (luminance(top) + luminance(bot)) / 2
+			sb.WriteByte(asciiRamp[int(lum*float64(len(asciiRamp)-1)+0.5)])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// cellPixels returns the top and bottom pixels of the text cell in column
+// x of text row row. The last row of an odd-height framebuffer has only a
+// top pixel, so it fills the whole cell rather than the row being lost.
+func (fb *Framebuffer) cellPixels(x, row int) (top, bot color.RGBA) {
+	top = fb.GetPixel(x, row*2)
+	if row*2+1 == fb.Height {
+		return top, top
+	}
+	return top, fb.GetPixel(x, row*2+1)
+}
+
+// ANSI returns the framebuffer as 24-bit color text: a line of upper half
+// blocks (▀) per two rows, each colored with the top pixel as foreground
+// and the bottom as background, like a TerminalRenderer draws it.
+// Transparent pixels leave the terminal's default color showing. Every
+// line ends by resetting its colors.
+func (fb *Framebuffer) ANSI() string {
+	var sb strings.Builder
+	writeANSI(&sb, fb, fb.Width, (fb.Height+1)/2, nil, GlyphUpperHalf)
+	return sb.String()
+}
+
+// luminance returns the perceived brightness of c in [0, 1].
+// Transparent pixels count as black.
+func luminance(c color.RGBA) float64 {
+	if c.A == 0 {
+		return 0
+	}
+	return (0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)) / 255
+}
+
 // SavePNG saves the framebuffer as a PNG file.
 func (fb *Framebuffer) SavePNG(path string) error {
 	f, err := os.Create(path)
@@ -1,6 +1,7 @@
 package render
 
 import (
+	"image/color"
 	"os"
 	"path/filepath"
 	"testing"
@@ -56,3 +57,181 @@ func TestFramebufferToImage(t *testing.T) {
 		t.Errorf("Green pixel wrong: got %d,%d,%d,%d", r>>8, g>>8, b>>8, a>>8)
 	}
 }
+
+func TestFramebufferClear(t *testing.T) {
+	// Include sizes that aren't powers of two so the last copy is partial
+	sizes := []struct{ w, h int }{{0, 0}, {1, 1}, {3, 1}, {7, 5}, {64, 32}, {211, 97}}
+	fill := RGB(12, 34, 56)
+
+	for _, size := range sizes {
+		fb := NewFramebuffer(size.w, size.h)
+		fb.SetPixel(0, 0, ColorRed)
+		fb.Clear(fill)
+		for i, p := range fb.Pixels {
+			if p != fill {
+				t.Fatalf("%dx%d: pixel %d = %v, want %v", size.w, size.h, i, p, fill)
+			}
+		}
+	}
+}
+
+func BenchmarkFramebufferClear(b *testing.B) {
+	fb := NewFramebuffer(400, 200)
+	for b.Loop() {
+		fb.Clear(ColorBlack)
+	}
+}
+
+func TestFramebufferASCII(t *testing.T) {
+	// Odd height: the last row's pixels fill their whole cells
+	fb := NewFramebuffer(3, 3)
+	fb.Clear(ColorBlack)
+	fb.SetPixel(1, 0, ColorWhite)
+	fb.SetPixel(1, 1, ColorWhite)
+	fb.SetPixel(2, 0, ColorWhite)
+	fb.SetPixel(0, 2, ColorWhite)
+
+	want := " @+\n@  \n"
+	if got := fb.ASCII(); got != want {
+		t.Errorf("ASCII() = %q, want %q", got, want)
+	}
+}
+
+func TestFramebufferANSI(t *testing.T) {
+	fb := NewFramebuffer(3, 3)
+	fb.Clear(ColorBlack)
+	fb.SetPixel(1, 0, ColorWhite)
+	fb.SetPixel(2, 0, ColorWhite)
+	fb.SetPixel(0, 2, color.RGBA{}) // Transparent
+
+	black, white := "\x1b[38;2;0;0;0m", "\x1b[38;2;255;255;255m"
+	bgBlack := "\x1b[48;2;0;0;0m"
+	want := black + bgBlack + "▀" + white + "▀▀\x1b[0m\n" +
+		// The last row's pixels fill their whole cells
+		"\x1b[39m\x1b[49m▀" + black + bgBlack + "▀▀\x1b[0m\n"
+	if got := fb.ANSI(); got != want {
+		t.Errorf("ANSI() = %q, want %q", got, want)
+	}
+}
+
+func TestBlendPixel(t *testing.T) {
+	tests := []struct {
+		name  string
+		under Color
+		alpha float64
+		want  Color
+	}{
+		{"half over black", ColorBlack, 0.5, RGB(127, 127, 127)},
+		{"full", ColorBlack, 1, ColorWhite},
+		{"none", ColorBlack, 0, ColorBlack},
+		// A transparent background has nothing to mix with
+		{"mostly over transparent", Color{}, 0.7, ColorWhite},
+		{"barely over transparent", Color{}, 0.3, Color{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb := NewFramebuffer(1, 1)
+			fb.Clear(tt.under)
+			fb.BlendPixel(0, 0, ColorWhite, tt.alpha)
+			if got := fb.GetPixel(0, 0); got != tt.want {
+				t.Errorf("BlendPixel gave %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrawLineAA(t *testing.T) {
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 float64
+		wantRows       map[int]uint8 // Row to expected red level mid-line
+	}{
+		{"on pixel centers", 2, 5, 17, 5, map[int]uint8{4: 0, 5: 255, 6: 0}},
+		{"between rows", 2, 5.5, 17, 5.5, map[int]uint8{5: 127, 6: 127}},
+		{"quarter", 2, 5.25, 17, 5.25, map[int]uint8{5: 191, 6: 63}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, flip := range []bool{false, true} {
+				fb := NewFramebuffer(20, 20)
+				fb.Clear(RGB(0, 0, 0))
+				at := func(x, y int) uint8 { return fb.GetPixel(x, y).R }
+				if flip {
+					// The same line, steep and drawn backwards
+					fb.DrawLineAA(tt.y1, tt.x1, tt.y0, tt.x0, RGB(255, 255, 255))
+					at = func(x, y int) uint8 { return fb.GetPixel(y, x).R }
+				} else {
+					fb.DrawLineAA(tt.x0, tt.y0, tt.x1, tt.y1, RGB(255, 255, 255))
+				}
+				for row, want := range tt.wantRows {
+					if got := at(10, row); absInt(int(got)-int(want)) > 1 {
+						t.Errorf("flip=%v: row %d = %d, want %d", flip, row, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDrawLineAAOffscreen(t *testing.T) {
+	fb := NewFramebuffer(20, 20)
+	fb.Clear(RGB(0, 0, 0))
+	// Endpoints far off screen must neither hang nor write out of bounds
+	fb.DrawLineAA(-1e9, 10, 1e9, 10, RGB(255, 255, 255))
+	for x := range fb.Width {
+		if fb.GetPixel(x, 10).R != 255 {
+			t.Fatalf("pixel (%d, 10) not drawn", x)
+		}
+	}
+}
+
+func TestDrawLineWidth(t *testing.T) {
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 int
+		width          int
+		want           int // Pixels drawn across the middle of the line
+	}{
+		{"thin", 2, 10, 17, 10, 1, 1},
+		{"horizontal", 2, 10, 17, 10, 3, 3},
+		{"vertical", 10, 2, 10, 17, 2, 2},
+		{"diagonal", 2, 2, 17, 17, 3, 4}, // Widened so the perpendicular width stays 3
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb := NewFramebuffer(20, 20)
+			fb.Clear(RGB(0, 0, 0))
+			fb.DrawLineWidth(tt.x0, tt.y0, tt.x1, tt.y1, tt.width, RGB(255, 255, 255))
+
+			// Count along the minor axis through the line's midpoint
+			got := 0
+			for i := range 20 {
+				x, y := 10, i
+				if tt.x0 == tt.x1 {
+					x, y = i, 10
+				}
+				if fb.GetPixel(x, y).R != 0 {
+					got++
+				}
+			}
+			if got != tt.want {
+				t.Errorf("%d pixels across the line, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrawLineAAWidth(t *testing.T) {
+	fb := NewFramebuffer(20, 20)
+	fb.Clear(RGB(0, 0, 0))
+	fb.DrawLineAAWidth(2, 10, 17, 10, 3, RGB(255, 255, 255))
+
+	// A band from 8.5 to 11.5 fully covers rows 9-11 and nothing else
+	for y, want := range map[int]uint8{8: 0, 9: 255, 10: 255, 11: 255, 12: 0} {
+		if got := fb.GetPixel(10, y).R; got != want {
+			t.Errorf("row %d = %d, want %d", y, got, want)
+		}
+	}
+}
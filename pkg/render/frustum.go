@@ -199,6 +199,12 @@ func (f Frustum) IntersectAABB(box AABB) bool {
 	return true
 }
 
+// IntersectsBox is IntersectAABB for bounds given as two corners,
+// which lets a Frustum cull a models.BVH.
+func (f Frustum) IntersectsBox(min, max math3d.Vec3) bool {
+	return f.IntersectAABB(AABB{Min: min, Max: max})
+}
+
 // ContainsAABB tests if the AABB is completely inside the frustum.
 // Returns true only if all 8 corners are inside all 6 planes.
 func (f Frustum) ContainsAABB(box AABB) bool {
@@ -237,6 +237,9 @@ func TestFrustumIntersectAABB(t *testing.T) {
 			if result != tc.expected {
 				t.Errorf("IntersectAABB(%v) = %v, want %v", tc.box, result, tc.expected)
 			}
+			if got := frustum.IntersectsBox(tc.box.Min, tc.box.Max); got != result {
+				t.Errorf("IntersectsBox(%v, %v) = %v, IntersectAABB = %v", tc.box.Min, tc.box.Max, got, result)
+			}
 		})
 	}
 }
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+	"time"
+)
+
+// GIFRecorder captures framebuffers at a limited rate and encodes them as
+// an animated GIF, played back with the timing they were captured at.
+type GIFRecorder struct {
+	Interval  time.Duration // Minimum time between captured frames
+	MaxFrames int           // Capturing stops once this many frames are held
+
+	anim gif.GIF
+	last time.Time // When the last frame was captured
+}
+
+// NewGIFRecorder creates a recorder capturing at most fps frames per second,
+// up to maxFrames frames.
+func NewGIFRecorder(fps, maxFrames int) *GIFRecorder {
+	return &GIFRecorder{
+		Interval:  time.Second / time.Duration(max(fps, 1)),
+		MaxFrames: maxFrames,
+	}
+}
+
+// Capture adds fb as a frame if Interval has passed since the last one.
+// Returns false once the recording is full, or if fb's size differs from
+// the first frame's, since a GIF's frames share one size.
+func (r *GIFRecorder) Capture(fb *Framebuffer, now time.Time) bool {
+	n := len(r.anim.Image)
+	if n >= r.MaxFrames {
+		return false
+	}
+	if n > 0 {
+		if b := r.anim.Image[0].Bounds(); b.Dx() != fb.Width || b.Dy() != fb.Height {
+			return false
+		}
+		elapsed := now.Sub(r.last)
+		if elapsed < r.Interval {
+			return true
+		}
+		// The previous frame stays up until this one replaces it
+		r.anim.Delay[n-1] = gifDelay(elapsed)
+	}
+
+	bounds := image.Rect(0, 0, fb.Width, fb.Height)
+	frame := image.NewPaletted(bounds, palette.Plan9)
+	draw.FloydSteinberg.Draw(frame, bounds, fb.ToImage(), image.Point{})
+	r.anim.Image = append(r.anim.Image, frame)
+	r.anim.Delay = append(r.anim.Delay, gifDelay(r.Interval))
+	r.last = now
+	return len(r.anim.Image) < r.MaxFrames
+}
+
+// gifDelay converts d to GIF frame delay units of 10ms, at least one.
+func gifDelay(d time.Duration) int {
+	return max(int(d/(10*time.Millisecond)), 1)
+}
+
+// Frames returns how many frames have been captured.
+func (r *GIFRecorder) Frames() int {
+	return len(r.anim.Image)
+}
+
+// Encode writes the captured frames to w as a looping animated GIF.
+func (r *GIFRecorder) Encode(w io.Writer) error {
+	if len(r.anim.Image) == 0 {
+		return errors.New("encode gif: no frames captured")
+	}
+	if err := gif.EncodeAll(w, &r.anim); err != nil {
+		return fmt.Errorf("encode gif: %w", err)
+	}
+	return nil
+}
+
+// SaveGIF writes the captured frames to path as an animated GIF.
+func (r *GIFRecorder) SaveGIF(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Encode(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestGIFRecorder(t *testing.T) {
+	rec := NewGIFRecorder(10, 3)
+	fb := NewFramebuffer(8, 4)
+	start := time.Unix(0, 0)
+
+	tests := []struct {
+		after      time.Duration
+		wantMore   bool
+		wantFrames int
+	}{
+		{0, true, 1},
+		{50 * time.Millisecond, true, 1}, // Too soon, skipped
+		{250 * time.Millisecond, true, 2},
+		{400 * time.Millisecond, false, 3}, // Full
+		{900 * time.Millisecond, false, 3},
+	}
+	for i, tt := range tests {
+		fb.Clear(RGB(uint8(i*50), 0, 0))
+		if more := rec.Capture(fb, start.Add(tt.after)); more != tt.wantMore || rec.Frames() != tt.wantFrames {
+			t.Fatalf("capture at %v: more = %v, frames = %d; want %v, %d",
+				tt.after, more, rec.Frames(), tt.wantMore, tt.wantFrames)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := rec.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 3 || anim.Config.Width != 8 || anim.Config.Height != 4 {
+		t.Fatalf("decoded %d frames of %dx%d, want 3 of 8x4", len(anim.Image), anim.Config.Width, anim.Config.Height)
+	}
+	// Frames play back as long as they were on screen while recording
+	if want := []int{25, 15, 10}; anim.Delay[0] != want[0] || anim.Delay[1] != want[1] || anim.Delay[2] != want[2] {
+		t.Errorf("delays = %v, want %v", anim.Delay, want)
+	}
+	if r, _, _, _ := anim.Image[2].At(0, 0).RGBA(); r>>8 < 140 || r>>8 > 160 {
+		t.Errorf("last frame red = %d, want about 150", r>>8)
+	}
+}
+
+func TestGIFRecorderResize(t *testing.T) {
+	rec := NewGIFRecorder(10, 10)
+	rec.Capture(NewFramebuffer(8, 4), time.Unix(0, 0))
+	if rec.Capture(NewFramebuffer(4, 4), time.Unix(1, 0)) || rec.Frames() != 1 {
+		t.Errorf("capture after resize: frames = %d, want recording stopped at 1", rec.Frames())
+	}
+	if err := NewGIFRecorder(10, 10).Encode(&bytes.Buffer{}); err == nil {
+		t.Error("encoding an empty recording should fail")
+	}
+}
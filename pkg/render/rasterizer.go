@@ -12,6 +12,7 @@ type Vertex struct {
 	Position math3d.Vec3 // World position
 	Normal   math3d.Vec3 // Normal vector (for lighting)
 	UV       math3d.Vec2 // Texture coordinates
+	AOUV     math3d.Vec2 // Ambient occlusion map coordinates
 	Color    Color       // Vertex color
 }
 
@@ -22,22 +23,151 @@ type Triangle struct {
 
 // Rasterizer handles software triangle rasterization.
 type Rasterizer struct {
-	camera                *Camera
-	fb                    *Framebuffer
-	zbuffer               []float64 // Depth buffer (1D array, row-major)
-	width                 int
-	height                int
-	frustum               Frustum      // Cached frustum planes
-	frustumDirty          bool         // Whether frustum needs recalculation
-	CullingStats          CullingStats // Statistics for debugging/benchmarking
-	DisableBackfaceCulling bool        // If true, render both sides of triangles
+	camera                 *Camera
+	fb                     *Framebuffer
+	zbuffer                []float32  // Depth buffer (1D array, row-major); float32 halves its cache footprint
+	tiles                  depthTiles // Coarse summary of zbuffer, for skipping hidden tiles
+	width                  int
+	height                 int
+	frustum                Frustum             // Cached frustum planes
+	frustumDirty           bool                // Whether frustum needs recalculation
+	CullingStats           CullingStats        // Statistics for debugging/benchmarking
+	TextureStats           TextureStats        // Texture sampling statistics for debugging
+	DisableBackfaceCulling bool                // If true, render both sides of triangles
+	FrontFace              FrontFace           // Screen-space winding of front faces; culling drops the other
+	AntialiasLines         bool                // If true, draw wireframe and line primitives with DrawLineAA
+	LineWidth              int                 // Wireframe and line width in pixels; below 2 draws 1-pixel lines
+	Palette                Palette             // Colors for WireframeByMaterial edges
+	FlatShading            bool                // If true, the Gouraud paths light each triangle by its face normal, ignoring vertex normals
+	Workers                int                 // Goroutines the optimized mesh paths split the frame's rows among; below 2 draws serially
+	Deterministic          bool                // If true, draw serially and round shaded colors, so a scene renders identically everywhere
+	FaceBase               int                 // Added to mesh triangle indices in the face ID buffer, so meshes drawn together pick apart
+	vertexCache            []transformedVertex // Per-draw transformed vertices, reused across frames
+	faceIDs                []int32             // Per-pixel mesh triangle index, nil unless picking is enabled
+	currentFace            int32               // Triangle index written to faceIDs by the draw in progress
+	coverage               []uint8             // Per-pixel face count for DrawMeshUV, reused across frames
+	occlusion              *Texture            // Ambient occlusion map, nil for none
+	occlusionStrength      float64
+	banded                 bool        // Whether this is one worker's copy, drawing only rows bandTop to bandBottom
+	viewProj               math3d.Mat4 // Camera view-projection snapshotted by BeginFrame
+	frameStarted           bool        // Whether BeginFrame has been called, so draws use viewProj
+	bandTop, bandBottom    int
+	meshDraw               meshDraw // The DrawMesh*Opt call in progress, for drawFaces
+}
+
+// FrontFace is the winding, as seen on screen, of triangles facing the camera.
+type FrontFace int
+
+const (
+	FrontFaceCW  FrontFace = iota // Clockwise, as the bundled loaders produce
+	FrontFaceCCW                  // Counter-clockwise, the OpenGL and glTF convention
+)
+
+// backfacing reports whether a triangle whose screen-space edge cross
+// product is cross faces away and should be culled.
+func (r *Rasterizer) backfacing(cross float64) bool {
+	if r.DisableBackfaceCulling {
+		return false
+	}
+	// Screen Y points down, so clockwise triangles have a positive cross
+	if r.FrontFace == FrontFaceCCW {
+		return cross > 0
+	}
+	return cross < 0
+}
+
+// ambientLight is the unoccluded ambient term of the lighting model.
+const ambientLight = 0.3
+
+// flatNormals replaces tri's vertex normals with its geometric face normal,
+// for FlatShading, or when they're all zero, as in a model loaded without
+// normals, so it's still lit by its shape. The face normal is turned to
+// agree with the vertex normals, so it doesn't depend on which winding the
+// mesh uses; without any, it's turned to the FrontFace side.
+func (r *Rasterizer) flatNormals(tri *Triangle) {
+	var zero math3d.Vec3
+	missing := tri.V[0].Normal == zero && tri.V[1].Normal == zero && tri.V[2].Normal == zero
+	if !r.FlatShading && !missing {
+		return
+	}
+	p0 := tri.V[0].Position
+	n := tri.V[1].Position.Sub(p0).Cross(tri.V[2].Position.Sub(p0))
+	if n.LenSq() == 0 {
+		return
+	}
+	n = n.Normalize()
+	if missing {
+		// Front faces wind clockwise on screen for FrontFaceCW, so their
+		// edge cross product points into the screen
+		if r.FrontFace == FrontFaceCW {
+			n = n.Scale(-1)
+		}
+	} else if n.Dot(tri.V[0].Normal.Add(tri.V[1].Normal).Add(tri.V[2].Normal)) < 0 {
+		n = n.Scale(-1)
+	}
+	for i := range tri.V {
+		tri.V[i].Normal = n
+	}
+}
+
+// SetOcclusion sets the ambient occlusion map the optimized shading paths
+// darken ambient light with, sampling its red channel at each vertex's AOUV.
+// strength blends from no occlusion (0) to the full map (1). Pass nil to
+// turn occlusion off.
+func (r *Rasterizer) SetOcclusion(tex *Texture, strength float64) {
+	r.occlusion = tex
+	r.occlusionStrength = strength
+}
+
+// ambient returns the ambient light term at occlusion map coordinates uv.
+func (r *Rasterizer) ambient(uv math3d.Vec2) float64 {
+	if r.occlusion == nil {
+		return ambientLight
+	}
+	ao := float64(r.occlusion.Sample(uv.X, uv.Y).R) / 255
+	return ambientLight * (1 + r.occlusionStrength*(ao-1))
+}
+
+// noFace marks pixels not covered by a mesh triangle in the face ID buffer.
+const noFace = -1
+
+// transformedVertex holds a mesh vertex after the model transform.
+type transformedVertex struct {
+	Position math3d.Vec3
+	Normal   math3d.Vec3
+	UV       math3d.Vec2
+	AOUV     math3d.Vec2
 }
 
 // CullingStats tracks frustum culling performance.
 type CullingStats struct {
-	MeshesTested int // Total meshes tested for culling
-	MeshesCulled int // Meshes culled (not rendered)
-	MeshesDrawn  int // Meshes that passed culling
+	MeshesTested       int // Total meshes tested for culling
+	MeshesCulled       int // Meshes culled (not rendered)
+	MeshesDrawn        int // Meshes that passed culling
+	MeshesSphereCulled int // Meshes rejected by the bounding sphere alone, before the box test
+	TrianglesTested    int // Triangles checked against the frustum
+	TrianglesCulled    int // Triangles rejected for lying entirely outside one frustum plane
+	TrianglesBackface  int // Triangles rejected as back-facing
+}
+
+// TrianglesDrawn returns how many tested triangles went on to be rasterized.
+func (s CullingStats) TrianglesDrawn() int {
+	return s.TrianglesTested - s.TrianglesCulled - s.TrianglesBackface
+}
+
+// TextureStats tracks texture sampling, to tell whether sampling is what
+// makes a textured render slow.
+type TextureStats struct {
+	Samples  int // Total texture lookups
+	Nearest  int // Lookups using nearest filtering
+	Bilinear int // Lookups using bilinear filtering
+	RowJumps int // Lookups more than one texel row from the previous one (likely cache misses)
+
+	// Texture most recently sampled
+	Width, Height int
+	Filter        FilterMode
+
+	lastRow int
 }
 
 // NewRasterizer creates a new rasterizer.
@@ -46,13 +176,31 @@ func NewRasterizer(camera *Camera, fb *Framebuffer) *Rasterizer {
 	return &Rasterizer{
 		camera:       camera,
 		fb:           fb,
-		zbuffer:      make([]float64, w*h),
+		zbuffer:      make([]float32, w*h),
+		tiles:        newDepthTiles(w, h),
 		width:        w,
 		height:       h,
 		frustumDirty: true,
+		currentFace:  noFace,
+		Palette:      DefaultPalette,
 	}
 }
 
+// SetFramebuffer makes r draw into fb, resizing its own buffers to match
+// and reusing their memory when they're big enough, so following a
+// resized Framebuffer allocates little. The depth buffer is cleared.
+func (r *Rasterizer) SetFramebuffer(fb *Framebuffer) {
+	r.fb = fb
+	r.width, r.height = fb.Width, fb.Height
+	r.zbuffer = resized(r.zbuffer, fb.Width*fb.Height)
+	r.tiles.resize(fb.Width, fb.Height)
+	if r.faceIDs != nil {
+		r.faceIDs = resized(r.faceIDs, fb.Width*fb.Height)
+	}
+	r.frustumDirty = true
+	r.ClearDepth()
+}
+
 // ClearDepth clears the Z-buffer (call before each frame).
 func (r *Rasterizer) ClearDepth() {
 	// Use copy-doubling for faster clearing
@@ -60,10 +208,63 @@ func (r *Rasterizer) ClearDepth() {
 	if n == 0 {
 		return
 	}
-	r.zbuffer[0] = math.MaxFloat64
+	r.zbuffer[0] = math.MaxFloat32
 	for i := 1; i < n; i *= 2 {
 		copy(r.zbuffer[i:], r.zbuffer[:i])
 	}
+	r.tiles.clear()
+
+	if r.faceIDs != nil {
+		r.faceIDs[0] = noFace
+		for i := 1; i < n; i *= 2 {
+			copy(r.faceIDs[i:], r.faceIDs[:i])
+		}
+	}
+}
+
+// EnablePicking turns the face ID buffer used by PickFace on or off.
+// It costs an extra write per pixel, so leave it off unless needed.
+func (r *Rasterizer) EnablePicking(on bool) {
+	switch {
+	case on && r.faceIDs == nil:
+		r.faceIDs = make([]int32, len(r.zbuffer))
+		for i := range r.faceIDs {
+			r.faceIDs[i] = noFace
+		}
+	case !on:
+		r.faceIDs = nil
+	}
+}
+
+// PickFace returns the index of the mesh triangle drawn at pixel (x, y)
+// since the last ClearDepth, or -1 if there is none or picking is off.
+// Indices refer to whichever mesh covered the pixel last, offset by the
+// FaceBase it was drawn with.
+func (r *Rasterizer) PickFace(x, y int) int {
+	if r.faceIDs == nil || x < 0 || x >= r.width || y < 0 || y >= r.height {
+		return noFace
+	}
+	return int(r.faceIDs[y*r.width+x])
+}
+
+// BeginFrame snapshots the camera's matrices for the frame's draws, so
+// they skip the camera's cache checks and can't see it change mid-frame.
+// Call it once per frame, after moving the camera and before drawing;
+// draws use the snapshot until the next call. Until the first call, each
+// draw reads the camera itself.
+func (r *Rasterizer) BeginFrame() {
+	r.viewProj = r.camera.ViewProjectionMatrix()
+	r.frameStarted = true
+	r.frustumDirty = true
+}
+
+// viewProjection returns the view-projection matrix to draw with: the
+// BeginFrame snapshot if there is one, otherwise the camera's.
+func (r *Rasterizer) viewProjection() math3d.Mat4 {
+	if r.frameStarted {
+		return r.viewProj
+	}
+	return r.camera.ViewProjectionMatrix()
 }
 
 // InvalidateFrustum marks the frustum as needing recalculation.
@@ -75,7 +276,7 @@ func (r *Rasterizer) InvalidateFrustum() {
 // UpdateFrustum recalculates the frustum planes from the camera.
 func (r *Rasterizer) UpdateFrustum() {
 	if r.frustumDirty {
-		r.frustum = ExtractFrustum(r.camera.ViewProjectionMatrix())
+		r.frustum = ExtractFrustum(r.viewProjection())
 		r.frustumDirty = false
 	}
 }
@@ -91,6 +292,45 @@ func (r *Rasterizer) ResetCullingStats() {
 	r.CullingStats = CullingStats{}
 }
 
+// ResetTextureStats resets the texture sampling statistics (call once per frame).
+func (r *Rasterizer) ResetTextureStats() {
+	r.TextureStats = TextureStats{}
+}
+
+// sampleTexture samples tex at (u, v), recording the lookup in TextureStats.
+func (r *Rasterizer) sampleTexture(tex *Texture, u, v float64) Color {
+	st := &r.TextureStats
+	st.Samples++
+	if tex.FilterMode == FilterBilinear {
+		st.Bilinear++
+	} else {
+		st.Nearest++
+	}
+	st.Width, st.Height, st.Filter = tex.Width, tex.Height, tex.FilterMode
+
+	// Texels are stored row-major, so jumping rows is what defeats the cache
+	row := int(v * float64(tex.Height))
+	if d := row - st.lastRow; d > 1 || d < -1 {
+		st.RowJumps++
+	}
+	st.lastRow = row
+
+	return tex.Sample(u, v)
+}
+
+// sampleNearest is sampleTexture for a texture with a nearestSampler.
+func (r *Rasterizer) sampleNearest(s *nearestSampler, u, v float64) Color {
+	c, row := s.sample(u, v)
+	st := &r.TextureStats
+	st.Samples++
+	st.Nearest++
+	if d := row - st.lastRow; d > 1 || d < -1 {
+		st.RowJumps++
+	}
+	st.lastRow = row
+	return c
+}
+
 // IsVisible tests if a world-space AABB is visible in the frustum.
 func (r *Rasterizer) IsVisible(worldBounds AABB) bool {
 	r.UpdateFrustum()
@@ -103,20 +343,67 @@ func (r *Rasterizer) IsVisibleTransformed(localBounds AABB, transform math3d.Mat
 	return r.IsVisible(worldBounds)
 }
 
-// getDepth returns the depth at (x, y).
-func (r *Rasterizer) getDepth(x, y int) float64 {
+// cullTriangle reports whether a clip-space triangle lies entirely behind
+// the camera or outside one of the six frustum planes, so it can be skipped
+// before rasterization. Rejected triangles are counted in
+// CullingStats.TrianglesCulled.
+func (r *Rasterizer) cullTriangle(clip [3]math3d.Vec4) bool {
+	r.CullingStats.TrianglesTested++
+
+	if clip[0].W <= 0 && clip[1].W <= 0 && clip[2].W <= 0 {
+		r.CullingStats.TrianglesCulled++
+		return true
+	}
+
+	var outside [6]int
+	for _, c := range clip {
+		if c.X < -c.W {
+			outside[FrustumLeft]++
+		}
+		if c.X > c.W {
+			outside[FrustumRight]++
+		}
+		if c.Y < -c.W {
+			outside[FrustumBottom]++
+		}
+		if c.Y > c.W {
+			outside[FrustumTop]++
+		}
+		if c.Z < -c.W {
+			outside[FrustumNear]++
+		}
+		if c.Z > c.W {
+			outside[FrustumFar]++
+		}
+	}
+
+	for _, n := range outside {
+		if n == 3 {
+			r.CullingStats.TrianglesCulled++
+			return true
+		}
+	}
+	return false
+}
+
+// getDepth returns the depth at (x, y), or the cleared depth,
+// math.MaxFloat32, outside the buffer.
+func (r *Rasterizer) getDepth(x, y int) float32 {
 	if x < 0 || x >= r.width || y < 0 || y >= r.height {
-		return math.MaxFloat64
+		return math.MaxFloat32
 	}
 	return r.zbuffer[y*r.width+x]
 }
 
 // setDepth sets the depth at (x, y).
-func (r *Rasterizer) setDepth(x, y int, z float64) {
+func (r *Rasterizer) setDepth(x, y int, z float32) {
 	if x < 0 || x >= r.width || y < 0 || y >= r.height {
 		return
 	}
 	r.zbuffer[y*r.width+x] = z
+	if r.faceIDs != nil {
+		r.faceIDs[y*r.width+x] = r.currentFace
+	}
 }
 
 // screenVertex holds a vertex transformed to screen space.
@@ -127,24 +414,21 @@ type screenVertex struct {
 	Color  Color
 	Normal math3d.Vec3
 	UV     math3d.Vec2
+	AOUV   math3d.Vec2
 }
 
 // DrawTriangle rasterizes a single triangle.
 func (r *Rasterizer) DrawTriangle(tri Triangle) {
 	// Transform vertices to screen space
 	var sv [3]screenVertex
-	allBehind := true
+	var clip [3]math3d.Vec4
 
-	viewProj := r.camera.ViewProjectionMatrix()
+	viewProj := r.viewProjection()
 
 	for i := range 3 {
 		// Transform to clip space
 		clipPos := viewProj.MulVec4(math3d.V4FromV3(tri.V[i].Position, 1))
-
-		// Check if behind camera
-		if clipPos.W > 0 {
-			allBehind = false
-		}
+		clip[i] = clipPos
 
 		// Perspective divide
 		if clipPos.W != 0 {
@@ -165,7 +449,7 @@ func (r *Rasterizer) DrawTriangle(tri Triangle) {
 	}
 
 	// Skip if entirely behind camera
-	if allBehind {
+	if r.cullTriangle(clip) {
 		return
 	}
 
@@ -173,7 +457,8 @@ func (r *Rasterizer) DrawTriangle(tri Triangle) {
 	edge1 := math3d.V2(sv[1].X-sv[0].X, sv[1].Y-sv[0].Y)
 	edge2 := math3d.V2(sv[2].X-sv[0].X, sv[2].Y-sv[0].Y)
 	cross := edge1.X*edge2.Y - edge1.Y*edge2.X
-	if cross < 0 {
+	if r.backfacing(cross) {
+		r.CullingStats.TrianglesBackface++
 		return // Back-facing
 	}
 
@@ -182,6 +467,7 @@ func (r *Rasterizer) DrawTriangle(tri Triangle) {
 	maxX := int(math.Min(float64(r.width-1), math.Ceil(max3(sv[0].X, sv[1].X, sv[2].X))))
 	minY := int(math.Max(0, math.Floor(min3(sv[0].Y, sv[1].Y, sv[2].Y))))
 	maxY := int(math.Min(float64(r.height-1), math.Ceil(max3(sv[0].Y, sv[1].Y, sv[2].Y))))
+	invW := inverseW(&sv)
 
 	// Rasterize using barycentric coordinates
 	for y := minY; y <= maxY; y++ {
@@ -202,15 +488,15 @@ func (r *Rasterizer) DrawTriangle(tri Triangle) {
 			}
 
 			// Interpolate depth (perspective-correct)
-			z := bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z
+			z := float32(bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z)
 
 			// Z-buffer test
 			if z >= r.getDepth(x, y) {
 				continue
 			}
 
-			// Interpolate color (perspective-correct would use W, but flat is fine for now)
-			color := interpolateColor3(sv[0].Color, sv[1].Color, sv[2].Color, bc)
+			// Interpolate color (perspective-correct)
+			color := r.interpolateColor3(sv[0].Color, sv[1].Color, sv[2].Color, perspectiveWeights(bc, invW))
 
 			// Set pixel
 			r.setDepth(x, y, z)
@@ -223,18 +509,14 @@ func (r *Rasterizer) DrawTriangle(tri Triangle) {
 func (r *Rasterizer) DrawTriangleTextured(tri Triangle, tex *Texture, lightDir math3d.Vec3) {
 	// Transform vertices to screen space
 	var sv [3]screenVertex
-	allBehind := true
+	var clip [3]math3d.Vec4
 
-	viewProj := r.camera.ViewProjectionMatrix()
+	viewProj := r.viewProjection()
 
 	for i := range 3 {
 		// Transform to clip space
 		clipPos := viewProj.MulVec4(math3d.V4FromV3(tri.V[i].Position, 1))
-
-		// Check if behind camera
-		if clipPos.W > 0 {
-			allBehind = false
-		}
+		clip[i] = clipPos
 
 		// Perspective divide
 		if clipPos.W != 0 {
@@ -255,7 +537,7 @@ func (r *Rasterizer) DrawTriangleTextured(tri Triangle, tex *Texture, lightDir m
 	}
 
 	// Skip if entirely behind camera
-	if allBehind {
+	if r.cullTriangle(clip) {
 		return
 	}
 
@@ -263,7 +545,8 @@ func (r *Rasterizer) DrawTriangleTextured(tri Triangle, tex *Texture, lightDir m
 	edge1 := math3d.V2(sv[1].X-sv[0].X, sv[1].Y-sv[0].Y)
 	edge2 := math3d.V2(sv[2].X-sv[0].X, sv[2].Y-sv[0].Y)
 	cross := edge1.X*edge2.Y - edge1.Y*edge2.X
-	if cross < 0 {
+	if r.backfacing(cross) {
+		r.CullingStats.TrianglesBackface++
 		return // Back-facing
 	}
 
@@ -281,14 +564,7 @@ func (r *Rasterizer) DrawTriangleTextured(tri Triangle, tex *Texture, lightDir m
 	maxY := int(math.Min(float64(r.height-1), math.Ceil(max3(sv[0].Y, sv[1].Y, sv[2].Y))))
 
 	// Precompute perspective-correct interpolation factors (1/w for each vertex)
-	var invW [3]float64
-	for i := range 3 {
-		if sv[i].W != 0 {
-			invW[i] = 1.0 / sv[i].W
-		} else {
-			invW[i] = 0
-		}
-	}
+	invW := inverseW(&sv)
 
 	// Rasterize using barycentric coordinates with perspective correction
 	for y := minY; y <= maxY; y++ {
@@ -309,7 +585,7 @@ func (r *Rasterizer) DrawTriangleTextured(tri Triangle, tex *Texture, lightDir m
 			}
 
 			// Interpolate depth
-			z := bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z
+			z := float32(bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z)
 
 			// Z-buffer test
 			if z >= r.getDepth(x, y) {
@@ -328,10 +604,10 @@ func (r *Rasterizer) DrawTriangleTextured(tri Triangle, tex *Texture, lightDir m
 			v := (w0*sv[0].UV.Y + w1*sv[1].UV.Y + w2*sv[2].UV.Y) / oneOverW
 
 			// Sample texture
-			texColor := tex.Sample(u, v)
+			texColor := r.sampleTexture(tex, u, v)
 
 			// Apply lighting
-			litColor := MultiplyColor(texColor, intensity)
+			litColor := r.light(texColor, intensity)
 
 			// Set pixel
 			r.setDepth(x, y, z)
@@ -365,9 +641,9 @@ func (r *Rasterizer) DrawTriangleLit(v0, v1, v2 math3d.Vec3, baseColor Color, li
 
 	// Apply lighting to color
 	litColor := RGB(
-		uint8(float64(baseColor.R)*intensity),
-		uint8(float64(baseColor.G)*intensity),
-		uint8(float64(baseColor.B)*intensity),
+		r.channel(float64(baseColor.R)*intensity),
+		r.channel(float64(baseColor.G)*intensity),
+		r.channel(float64(baseColor.B)*intensity),
 	)
 
 	r.DrawTriangleFlat(v0, v1, v2, litColor)
@@ -480,9 +756,10 @@ func (r *Rasterizer) DrawTransformedCubeGouraud(transform math3d.Mat4, size floa
 	// Transform vertices and normals
 	var v [8]math3d.Vec3
 	var n [8]math3d.Vec3
+	normalMat := transform.NormalMatrix()
 	for i := range local {
 		v[i] = transform.MulVec3(local[i])
-		n[i] = transform.MulVec3Dir(vertexNormals[i]).Normalize()
+		n[i] = normalMat.MulVec3Dir(vertexNormals[i]).Normalize()
 	}
 
 	// Face definitions (same as before)
@@ -561,8 +838,9 @@ func (r *Rasterizer) DrawTexturedCube(transform math3d.Mat4, size float64, tex *
 		{X: 0, Y: -1, Z: 0}, // Bottom
 	}
 
+	normalMat := transform.NormalMatrix()
 	for fi, f := range faces {
-		normal := transform.MulVec3Dir(normals[fi]).Normalize()
+		normal := normalMat.MulVec3Dir(normals[fi]).Normalize()
 
 		// Triangle 1: v0, v1, v2 (BL, BR, TR)
 		tri1 := Triangle{
@@ -605,15 +883,65 @@ func barycentric(x0, y0, x1, y1, x2, y2, px, py float64) math3d.Vec3 {
 	return math3d.V3(1-u-v, v, u)
 }
 
+// inverseW returns 1/W for each vertex, or 0 where W is 0.
+func inverseW(sv *[3]screenVertex) [3]float64 {
+	var invW [3]float64
+	for i := range 3 {
+		if sv[i].W != 0 {
+			invW[i] = 1.0 / sv[i].W
+		}
+	}
+	return invW
+}
+
+// perspectiveWeights turns screen-space barycentric coordinates into ones
+// that interpolate linearly across the triangle in 3D, given each vertex's
+// 1/W. Returns bc unchanged if the weights vanish.
+func perspectiveWeights(bc math3d.Vec3, invW [3]float64) math3d.Vec3 {
+	w := math3d.V3(bc.X*invW[0], bc.Y*invW[1], bc.Z*invW[2])
+	sum := w.X + w.Y + w.Z
+	if sum == 0 {
+		return bc
+	}
+	return w.Scale(1 / sum)
+}
+
 // interpolateColor3 interpolates between 3 colors using barycentric coords.
-func interpolateColor3(c0, c1, c2 Color, bc math3d.Vec3) Color {
+func (r *Rasterizer) interpolateColor3(c0, c1, c2 Color, bc math3d.Vec3) Color {
 	return RGB(
-		uint8(float64(c0.R)*bc.X+float64(c1.R)*bc.Y+float64(c2.R)*bc.Z),
-		uint8(float64(c0.G)*bc.X+float64(c1.G)*bc.Y+float64(c2.G)*bc.Z),
-		uint8(float64(c0.B)*bc.X+float64(c1.B)*bc.Y+float64(c2.B)*bc.Z),
+		r.channel(float64(c0.R)*bc.X+float64(c1.R)*bc.Y+float64(c2.R)*bc.Z),
+		r.channel(float64(c0.G)*bc.X+float64(c1.G)*bc.Y+float64(c2.G)*bc.Z),
+		r.channel(float64(c0.B)*bc.X+float64(c1.B)*bc.Y+float64(c2.B)*bc.Z),
 	)
 }
 
+// channel converts a shaded color channel to a byte. Deterministic mode
+// rounds it, since truncating turns a channel that should be whole, like
+// 200*1.0, into 199 or 200 depending on the last bit of float error.
+func (r *Rasterizer) channel(v float64) uint8 {
+	if r.Deterministic {
+		v = math.Round(v)
+	}
+	return uint8(min(v, 255))
+}
+
+// light scales c by a lighting intensity, like MultiplyColor, rounding in
+// Deterministic mode. The result is opaque whatever the texture's alpha,
+// so a mesh always hides what's behind it, even a transparent background.
+func (r *Rasterizer) light(c Color, intensity float64) Color {
+	if !r.Deterministic {
+		c = MultiplyColor(c, intensity)
+		c.A = 255
+		return c
+	}
+	return Color{
+		R: r.channel(float64(c.R) * intensity),
+		G: r.channel(float64(c.G) * intensity),
+		B: r.channel(float64(c.B) * intensity),
+		A: 255,
+	}
+}
+
 func min3(a, b, c float64) float64 {
 	return math.Min(a, math.Min(b, c))
 }
@@ -625,21 +953,19 @@ func max3(a, b, c float64) float64 {
 // DrawTriangleGouraud rasterizes a triangle with Gouraud shading (per-vertex lighting).
 // Lighting is calculated at each vertex and interpolated across the triangle.
 func (r *Rasterizer) DrawTriangleGouraud(tri Triangle, lightDir math3d.Vec3) {
+	r.flatNormals(&tri)
+
 	// Transform vertices to screen space
 	var sv [3]screenVertex
-	allBehind := true
+	var clip [3]math3d.Vec4
 
-	viewProj := r.camera.ViewProjectionMatrix()
+	viewProj := r.viewProjection()
 	normLight := lightDir.Normalize()
 
 	for i := range 3 {
 		// Transform to clip space
 		clipPos := viewProj.MulVec4(math3d.V4FromV3(tri.V[i].Position, 1))
-
-		// Check if behind camera
-		if clipPos.W > 0 {
-			allBehind = false
-		}
+		clip[i] = clipPos
 
 		// Perspective divide
 		if clipPos.W != 0 {
@@ -659,16 +985,16 @@ func (r *Rasterizer) DrawTriangleGouraud(tri Triangle, lightDir math3d.Vec3) {
 
 		// Apply lighting to vertex color
 		sv[i].Color = RGB(
-			uint8(float64(tri.V[i].Color.R)*intensity),
-			uint8(float64(tri.V[i].Color.G)*intensity),
-			uint8(float64(tri.V[i].Color.B)*intensity),
+			r.channel(float64(tri.V[i].Color.R)*intensity),
+			r.channel(float64(tri.V[i].Color.G)*intensity),
+			r.channel(float64(tri.V[i].Color.B)*intensity),
 		)
 		sv[i].Normal = tri.V[i].Normal
 		sv[i].UV = tri.V[i].UV
 	}
 
 	// Skip if entirely behind camera
-	if allBehind {
+	if r.cullTriangle(clip) {
 		return
 	}
 
@@ -676,7 +1002,8 @@ func (r *Rasterizer) DrawTriangleGouraud(tri Triangle, lightDir math3d.Vec3) {
 	edge1 := math3d.V2(sv[1].X-sv[0].X, sv[1].Y-sv[0].Y)
 	edge2 := math3d.V2(sv[2].X-sv[0].X, sv[2].Y-sv[0].Y)
 	cross := edge1.X*edge2.Y - edge1.Y*edge2.X
-	if cross < 0 {
+	if r.backfacing(cross) {
+		r.CullingStats.TrianglesBackface++
 		return // Back-facing
 	}
 
@@ -685,6 +1012,7 @@ func (r *Rasterizer) DrawTriangleGouraud(tri Triangle, lightDir math3d.Vec3) {
 	maxX := int(math.Min(float64(r.width-1), math.Ceil(max3(sv[0].X, sv[1].X, sv[2].X))))
 	minY := int(math.Max(0, math.Floor(min3(sv[0].Y, sv[1].Y, sv[2].Y))))
 	maxY := int(math.Min(float64(r.height-1), math.Ceil(max3(sv[0].Y, sv[1].Y, sv[2].Y))))
+	invW := inverseW(&sv)
 
 	// Rasterize using barycentric coordinates
 	for y := minY; y <= maxY; y++ {
@@ -705,15 +1033,15 @@ func (r *Rasterizer) DrawTriangleGouraud(tri Triangle, lightDir math3d.Vec3) {
 			}
 
 			// Interpolate depth
-			z := bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z
+			z := float32(bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z)
 
 			// Z-buffer test
 			if z >= r.getDepth(x, y) {
 				continue
 			}
 
-			// Interpolate lit vertex colors (Gouraud shading)
-			color := interpolateColor3(sv[0].Color, sv[1].Color, sv[2].Color, bc)
+			// Interpolate lit vertex colors (Gouraud shading, perspective-correct)
+			color := r.interpolateColor3(sv[0].Color, sv[1].Color, sv[2].Color, perspectiveWeights(bc, invW))
 
 			// Set pixel
 			r.setDepth(x, y, z)
@@ -725,22 +1053,20 @@ func (r *Rasterizer) DrawTriangleGouraud(tri Triangle, lightDir math3d.Vec3) {
 // DrawTriangleTexturedGouraud rasterizes a textured triangle with Gouraud shading.
 // Per-vertex lighting is calculated and interpolated, then modulated with texture.
 func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lightDir math3d.Vec3) {
+	r.flatNormals(&tri)
+
 	// Transform vertices to screen space
 	var sv [3]screenVertex
+	var clip [3]math3d.Vec4
 	var vertexIntensity [3]float64 // Store lighting intensity per vertex
-	allBehind := true
 
-	viewProj := r.camera.ViewProjectionMatrix()
+	viewProj := r.viewProjection()
 	normLight := lightDir.Normalize()
 
 	for i := range 3 {
 		// Transform to clip space
 		clipPos := viewProj.MulVec4(math3d.V4FromV3(tri.V[i].Position, 1))
-
-		// Check if behind camera
-		if clipPos.W > 0 {
-			allBehind = false
-		}
+		clip[i] = clipPos
 
 		// Perspective divide
 		if clipPos.W != 0 {
@@ -765,7 +1091,7 @@ func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lig
 	}
 
 	// Skip if entirely behind camera
-	if allBehind {
+	if r.cullTriangle(clip) {
 		return
 	}
 
@@ -773,7 +1099,8 @@ func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lig
 	edge1 := math3d.V2(sv[1].X-sv[0].X, sv[1].Y-sv[0].Y)
 	edge2 := math3d.V2(sv[2].X-sv[0].X, sv[2].Y-sv[0].Y)
 	cross := edge1.X*edge2.Y - edge1.Y*edge2.X
-	if cross < 0 {
+	if r.backfacing(cross) {
+		r.CullingStats.TrianglesBackface++
 		return // Back-facing
 	}
 
@@ -784,14 +1111,7 @@ func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lig
 	maxY := int(math.Min(float64(r.height-1), math.Ceil(max3(sv[0].Y, sv[1].Y, sv[2].Y))))
 
 	// Precompute perspective-correct interpolation factors (1/w for each vertex)
-	var invW [3]float64
-	for i := range 3 {
-		if sv[i].W != 0 {
-			invW[i] = 1.0 / sv[i].W
-		} else {
-			invW[i] = 0
-		}
-	}
+	invW := inverseW(&sv)
 
 	// Rasterize using barycentric coordinates with perspective correction
 	for y := minY; y <= maxY; y++ {
@@ -812,7 +1132,7 @@ func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lig
 			}
 
 			// Interpolate depth
-			z := bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z
+			z := float32(bc.X*sv[0].Z + bc.Y*sv[1].Z + bc.Z*sv[2].Z)
 
 			// Z-buffer test
 			if z >= r.getDepth(x, y) {
@@ -834,10 +1154,10 @@ func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lig
 			intensity := (w0*vertexIntensity[0] + w1*vertexIntensity[1] + w2*vertexIntensity[2]) / oneOverW
 
 			// Sample texture
-			texColor := tex.Sample(u, v)
+			texColor := r.sampleTexture(tex, u, v)
 
 			// Apply interpolated lighting (Gouraud)
-			litColor := MultiplyColor(texColor, intensity)
+			litColor := r.light(texColor, intensity)
 
 			// Set pixel
 			r.setDepth(x, y, z)
@@ -846,6 +1166,26 @@ func (r *Rasterizer) DrawTriangleTexturedGouraud(tri Triangle, tex *Texture, lig
 	}
 }
 
+// coloredTriangle assembles a triangle from transformed vertices with a flat color.
+func coloredTriangle(verts []transformedVertex, face [3]int, color Color) Triangle {
+	var tri Triangle
+	for i, idx := range face {
+		v := &verts[idx]
+		tri.V[i] = Vertex{Position: v.Position, Normal: v.Normal, AOUV: v.AOUV, Color: color}
+	}
+	return tri
+}
+
+// texturedTriangle assembles a triangle from transformed vertices with UVs and white base color.
+func texturedTriangle(verts []transformedVertex, face [3]int) Triangle {
+	var tri Triangle
+	for i, idx := range face {
+		v := &verts[idx]
+		tri.V[i] = Vertex{Position: v.Position, Normal: v.Normal, UV: v.UV, AOUV: v.AOUV, Color: RGB(255, 255, 255)}
+	}
+	return tri
+}
+
 // MeshRenderer is imported from models to avoid circular deps.
 // This interface allows drawing meshes without importing the models package.
 type MeshRenderer interface {
@@ -861,32 +1201,119 @@ type BoundedMeshRenderer interface {
 	GetBounds() (min, max math3d.Vec3)
 }
 
+// SphereBoundedMeshRenderer extends MeshRenderer with a bounding sphere,
+// which frustum culling tests before the bounding box since it is cheaper.
+type SphereBoundedMeshRenderer interface {
+	MeshRenderer
+	BoundingSphere() (center math3d.Vec3, radius float64)
+}
+
+// MaterialMeshRenderer extends MeshRenderer with per-face material indices.
+type MaterialMeshRenderer interface {
+	MeshRenderer
+	GetFaceMaterial(i int) int // -1 for no material
+}
+
+// OcclusionMeshRenderer extends MeshRenderer with separate ambient occlusion
+// map coordinates. Meshes without it sample occlusion at their texture UVs.
+type OcclusionMeshRenderer interface {
+	MeshRenderer
+	GetOcclusionUV(i int) math3d.Vec2
+}
+
+// LineMeshRenderer extends MeshRenderer with line and point primitives.
+type LineMeshRenderer interface {
+	MeshRenderer
+	LineCount() int
+	GetLine(i int) [2]int
+	PointCount() int
+	GetPoint(i int) int
+}
+
 // tryFrustumCull attempts to cull a mesh using its bounds if available.
 // Returns true if the mesh should be culled (not visible).
 func (r *Rasterizer) tryFrustumCull(mesh MeshRenderer, transform math3d.Mat4) bool {
 	// Check if mesh supports bounds for frustum culling
-	bounded, ok := mesh.(BoundedMeshRenderer)
-	if !ok {
+	bounded, hasBox := mesh.(BoundedMeshRenderer)
+	sphered, hasSphere := mesh.(SphereBoundedMeshRenderer)
+	if !hasBox && !hasSphere {
 		// No bounds available, can't cull
 		return false
 	}
 
 	r.CullingStats.MeshesTested++
 
-	// Get local bounds and transform to world space
-	minBounds, maxBounds := bounded.GetBounds()
-	localBounds := AABB{Min: minBounds, Max: maxBounds}
+	// The sphere test is six dot products, so try it first. Scaling
+	// stretches the sphere by at most the largest axis scale.
+	if hasSphere {
+		center, radius := sphered.BoundingSphere()
+		scale := math.Sqrt(max(
+			transform[0]*transform[0]+transform[1]*transform[1]+transform[2]*transform[2],
+			transform[4]*transform[4]+transform[5]*transform[5]+transform[6]*transform[6],
+			transform[8]*transform[8]+transform[9]*transform[9]+transform[10]*transform[10],
+		))
+		r.UpdateFrustum()
+		if !r.frustum.IntersectsSphere(transform.MulVec3(center), radius*scale) {
+			r.CullingStats.MeshesCulled++
+			r.CullingStats.MeshesSphereCulled++
+			return true
+		}
+	}
 
-	// Check if visible
-	if !r.IsVisibleTransformed(localBounds, transform) {
-		r.CullingStats.MeshesCulled++
-		return true
+	// Get local bounds and transform to world space
+	if hasBox {
+		minBounds, maxBounds := bounded.GetBounds()
+		localBounds := AABB{Min: minBounds, Max: maxBounds}
+
+		// Check if visible
+		if !r.IsVisibleTransformed(localBounds, transform) {
+			r.CullingStats.MeshesCulled++
+			return true
+		}
 	}
 
 	r.CullingStats.MeshesDrawn++
 	return false
 }
 
+// unitTolerance is how far from 1 a squared length can be and still count
+// as unit, far below what lighting can show.
+const unitTolerance = 1e-6
+
+// transformVertices transforms every vertex of the mesh once into the
+// rasterizer's reusable cache, so faces sharing a vertex don't redo the work.
+// The returned slice is only valid until the next call.
+func (r *Rasterizer) transformVertices(mesh MeshRenderer, transform math3d.Mat4) []transformedVertex {
+	n := mesh.VertexCount()
+	if cap(r.vertexCache) < n {
+		r.vertexCache = make([]transformedVertex, n)
+	}
+	verts := r.vertexCache[:n]
+
+	normalMat := transform.NormalMatrix()
+	// A rotation keeps unit normals unit, so only the rare ones that
+	// aren't, or every one under scale or shear, need normalizing
+	rigid := math3d.Mat3FromMat4(transform).IsOrthonormal(unitTolerance)
+	occluded, hasAOUV := mesh.(OcclusionMeshRenderer)
+	for i := range verts {
+		pos, normal, uv := mesh.GetVertex(i)
+		n := normalMat.MulVec3Dir(normal)
+		if !rigid || math.Abs(normal.LenSq()-1) > unitTolerance {
+			n = n.Normalize()
+		}
+		verts[i] = transformedVertex{
+			Position: transform.MulVec3(pos),
+			Normal:   n,
+			UV:       uv,
+			AOUV:     uv,
+		}
+		if r.occlusion != nil && hasAOUV {
+			verts[i].AOUV = occluded.GetOcclusionUV(i)
+		}
+	}
+	return verts
+}
+
 // DrawMesh renders a mesh with the given transform and color.
 // Automatically performs frustum culling if the mesh provides bounds.
 func (r *Rasterizer) DrawMesh(mesh MeshRenderer, transform math3d.Mat4, color Color, lightDir math3d.Vec3) {
@@ -899,21 +1326,14 @@ func (r *Rasterizer) DrawMesh(mesh MeshRenderer, transform math3d.Mat4, color Co
 	invTransform := transform.Inverse()
 	localLight := invTransform.MulVec3Dir(lightDir).Normalize()
 
+	verts := r.transformVertices(mesh, transform)
+
 	for i := 0; i < mesh.TriangleCount(); i++ {
+		r.currentFace = int32(r.FaceBase + i)
 		face := mesh.GetFace(i)
-
-		// Get vertices
-		p0, _, _ := mesh.GetVertex(face[0])
-		p1, _, _ := mesh.GetVertex(face[1])
-		p2, _, _ := mesh.GetVertex(face[2])
-
-		// Transform to world space
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
-
-		r.DrawTriangleLit(v0, v1, v2, color, localLight)
+		r.DrawTriangleLit(verts[face[0]].Position, verts[face[1]].Position, verts[face[2]].Position, color, localLight)
 	}
+	r.currentFace = noFace
 }
 
 // DrawMeshTextured renders a mesh with texture mapping.
@@ -924,35 +1344,14 @@ func (r *Rasterizer) DrawMeshTextured(mesh MeshRenderer, transform math3d.Mat4,
 		return
 	}
 
-	for i := 0; i < mesh.TriangleCount(); i++ {
-		face := mesh.GetFace(i)
-
-		// Get vertices with all attributes
-		p0, n0, uv0 := mesh.GetVertex(face[0])
-		p1, n1, uv1 := mesh.GetVertex(face[1])
-		p2, n2, uv2 := mesh.GetVertex(face[2])
-
-		// Transform positions to world space
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
-
-		// Transform normals (using rotation only, ignoring translation/scale for now)
-		wn0 := transform.MulVec3Dir(n0).Normalize()
-		wn1 := transform.MulVec3Dir(n1).Normalize()
-		wn2 := transform.MulVec3Dir(n2).Normalize()
-
-		// Build triangle with all attributes
-		tri := Triangle{
-			V: [3]Vertex{
-				{Position: v0, Normal: wn0, UV: uv0, Color: RGB(255, 255, 255)},
-				{Position: v1, Normal: wn1, UV: uv1, Color: RGB(255, 255, 255)},
-				{Position: v2, Normal: wn2, UV: uv2, Color: RGB(255, 255, 255)},
-			},
-		}
+	// Transform each shared vertex once, then index into the cache per face
+	verts := r.transformVertices(mesh, transform)
 
-		r.DrawTriangleTextured(tri, tex, lightDir)
+	for i := 0; i < mesh.TriangleCount(); i++ {
+		r.currentFace = int32(r.FaceBase + i)
+		r.DrawTriangleTextured(texturedTriangle(verts, mesh.GetFace(i)), tex, lightDir)
 	}
+	r.currentFace = noFace
 }
 
 // DrawMeshGouraud renders a mesh with Gouraud shading (per-vertex lighting).
@@ -964,35 +1363,13 @@ func (r *Rasterizer) DrawMeshGouraud(mesh MeshRenderer, transform math3d.Mat4, c
 		return
 	}
 
-	for i := 0; i < mesh.TriangleCount(); i++ {
-		face := mesh.GetFace(i)
-
-		// Get vertices with all attributes
-		p0, n0, _ := mesh.GetVertex(face[0])
-		p1, n1, _ := mesh.GetVertex(face[1])
-		p2, n2, _ := mesh.GetVertex(face[2])
+	verts := r.transformVertices(mesh, transform)
 
-		// Transform positions to world space
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
-
-		// Transform normals
-		wn0 := transform.MulVec3Dir(n0).Normalize()
-		wn1 := transform.MulVec3Dir(n1).Normalize()
-		wn2 := transform.MulVec3Dir(n2).Normalize()
-
-		// Build triangle with per-vertex normals for Gouraud
-		tri := Triangle{
-			V: [3]Vertex{
-				{Position: v0, Normal: wn0, Color: color},
-				{Position: v1, Normal: wn1, Color: color},
-				{Position: v2, Normal: wn2, Color: color},
-			},
-		}
-
-		r.DrawTriangleGouraud(tri, lightDir)
+	for i := 0; i < mesh.TriangleCount(); i++ {
+		r.currentFace = int32(r.FaceBase + i)
+		r.DrawTriangleGouraud(coloredTriangle(verts, mesh.GetFace(i), color), lightDir)
 	}
+	r.currentFace = noFace
 }
 
 // DrawMeshTexturedGouraud renders a mesh with texture mapping and Gouraud shading.
@@ -1004,35 +1381,13 @@ func (r *Rasterizer) DrawMeshTexturedGouraud(mesh MeshRenderer, transform math3d
 		return
 	}
 
-	for i := 0; i < mesh.TriangleCount(); i++ {
-		face := mesh.GetFace(i)
-
-		// Get vertices with all attributes
-		p0, n0, uv0 := mesh.GetVertex(face[0])
-		p1, n1, uv1 := mesh.GetVertex(face[1])
-		p2, n2, uv2 := mesh.GetVertex(face[2])
-
-		// Transform positions to world space
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
-
-		// Transform normals
-		wn0 := transform.MulVec3Dir(n0).Normalize()
-		wn1 := transform.MulVec3Dir(n1).Normalize()
-		wn2 := transform.MulVec3Dir(n2).Normalize()
-
-		// Build triangle with all attributes
-		tri := Triangle{
-			V: [3]Vertex{
-				{Position: v0, Normal: wn0, UV: uv0, Color: RGB(255, 255, 255)},
-				{Position: v1, Normal: wn1, UV: uv1, Color: RGB(255, 255, 255)},
-				{Position: v2, Normal: wn2, UV: uv2, Color: RGB(255, 255, 255)},
-			},
-		}
+	verts := r.transformVertices(mesh, transform)
 
-		r.DrawTriangleTexturedGouraud(tri, tex, lightDir)
+	for i := 0; i < mesh.TriangleCount(); i++ {
+		r.currentFace = int32(r.FaceBase + i)
+		r.DrawTriangleTexturedGouraud(texturedTriangle(verts, mesh.GetFace(i)), tex, lightDir)
 	}
+	r.currentFace = noFace
 }
 
 // DrawMeshGouraudCulled renders a mesh with Gouraud shading, with frustum culling.
@@ -1102,32 +1457,82 @@ func (r *Rasterizer) DrawMeshTexturedCulled(mesh MeshRenderer, transform math3d.
 // DrawMeshWireframe renders a mesh as wireframe.
 // Automatically performs frustum culling if the mesh provides bounds.
 func (r *Rasterizer) DrawMeshWireframe(mesh MeshRenderer, transform math3d.Mat4, color Color) {
+	r.DrawMeshWireframeColored(mesh, transform, color, WireframeSolid)
+}
+
+// DrawMeshWireframeColored renders a mesh as wireframe, coloring each
+// triangle's edges by mode. Meshes without materials fall back to color
+// in WireframeByMaterial mode.
+func (r *Rasterizer) DrawMeshWireframeColored(mesh MeshRenderer, transform math3d.Mat4, color Color, mode WireframeColorMode) {
 	// Frustum culling check
 	if r.tryFrustumCull(mesh, transform) {
 		return
 	}
 
+	verts := r.transformVertices(mesh, transform)
+	materials, _ := mesh.(MaterialMeshRenderer)
+
 	for i := 0; i < mesh.TriangleCount(); i++ {
 		face := mesh.GetFace(i)
+		v0 := verts[face[0]].Position
+		v1 := verts[face[1]].Position
+		v2 := verts[face[2]].Position
+
+		c := color
+		switch mode {
+		case WireframeByMaterial:
+			if materials != nil {
+				c = r.Palette.MaterialColor(materials.GetFaceMaterial(i), color)
+			}
+		case WireframeByNormal:
+			c = NormalColor(v1.Sub(v0).Cross(v2.Sub(v0)))
+		}
 
-		p0, _, _ := mesh.GetVertex(face[0])
-		p1, _, _ := mesh.GetVertex(face[1])
-		p2, _, _ := mesh.GetVertex(face[2])
+		// Project and draw lines (using framebuffer directly for now)
+		r.drawLine3D(v0, v1, c)
+		r.drawLine3D(v1, v2, c)
+		r.drawLine3D(v2, v0, c)
+	}
+}
 
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
+// DrawMeshLines draws the mesh's line and point primitives in a flat color.
+// Like the wireframe they are not depth tested.
+func (r *Rasterizer) DrawMeshLines(mesh LineMeshRenderer, transform math3d.Mat4, color Color) {
+	if mesh.LineCount() == 0 && mesh.PointCount() == 0 {
+		return
+	}
 
-		// Project and draw lines (using framebuffer directly for now)
-		r.drawLine3D(v0, v1, color)
-		r.drawLine3D(v1, v2, color)
-		r.drawLine3D(v2, v0, color)
+	// Frustum culling check
+	if r.tryFrustumCull(mesh, transform) {
+		return
+	}
+
+	verts := r.transformVertices(mesh, transform)
+
+	for i := 0; i < mesh.LineCount(); i++ {
+		line := mesh.GetLine(i)
+		r.drawLine3D(verts[line[0]].Position, verts[line[1]].Position, color)
+	}
+	for i := 0; i < mesh.PointCount(); i++ {
+		r.drawPoint3D(verts[mesh.GetPoint(i)].Position, color)
 	}
 }
 
+// drawPoint3D draws a single pixel at a projected 3D point.
+func (r *Rasterizer) drawPoint3D(p math3d.Vec3, color Color) {
+	clip := r.viewProjection().MulVec4(math3d.V4FromV3(p, 1))
+	if clip.W <= 0 {
+		return
+	}
+
+	x := int((clip.X/clip.W + 1) * 0.5 * float64(r.width))
+	y := int((1 - clip.Y/clip.W) * 0.5 * float64(r.height))
+	r.fb.SetPixel(x, y, color)
+}
+
 // drawLine3D draws a 3D line (projected to screen).
 func (r *Rasterizer) drawLine3D(a, b math3d.Vec3, color Color) {
-	viewProj := r.camera.ViewProjectionMatrix()
+	viewProj := r.viewProjection()
 
 	// Transform to clip space
 	clipA := viewProj.MulVec4(math3d.V4FromV3(a, 1))
@@ -1148,10 +1553,15 @@ func (r *Rasterizer) drawLine3D(a, b math3d.Vec3, color Color) {
 		clipB.Y /= clipB.W
 	}
 
-	x0 := int((clipA.X + 1) * 0.5 * float64(r.width))
-	y0 := int((1 - clipA.Y) * 0.5 * float64(r.height))
-	x1 := int((clipB.X + 1) * 0.5 * float64(r.width))
-	y1 := int((1 - clipB.Y) * 0.5 * float64(r.height))
+	fx0 := (clipA.X + 1) * 0.5 * float64(r.width)
+	fy0 := (1 - clipA.Y) * 0.5 * float64(r.height)
+	fx1 := (clipB.X + 1) * 0.5 * float64(r.width)
+	fy1 := (1 - clipB.Y) * 0.5 * float64(r.height)
 
-	r.fb.DrawLine(x0, y0, x1, y1, color)
+	if r.AntialiasLines {
+		// Pixel x covers [x, x+1), so its center is at x+0.5
+		r.fb.DrawLineAAWidth(fx0-0.5, fy0-0.5, fx1-0.5, fy1-0.5, float64(max(r.LineWidth, 1)), color)
+		return
+	}
+	r.fb.DrawLineWidth(int(fx0), int(fy0), int(fx1), int(fy1), r.LineWidth, color)
 }
@@ -4,6 +4,7 @@ package render
 
 import (
 	"math"
+	"sync"
 
 	"github.com/taigrr/trophy/pkg/math3d"
 )
@@ -25,19 +26,23 @@ func edgeFunc(A, B, C, x, y float64) float64 {
 
 // DrawTriangleGouraudOpt is an optimized version using edge functions with incremental updates.
 func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3) {
+	r.drawTriangleGouraudOpt(tri, lightDir, r.viewProjection())
+}
+
+// drawTriangleGouraudOpt is DrawTriangleGouraudOpt with the view-projection
+// matrix passed in, so mesh draws fetch it once rather than per triangle.
+func (r *Rasterizer) drawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3, viewProj math3d.Mat4) {
+	r.flatNormals(&tri)
+
 	// Transform vertices to screen space
 	var sv [3]screenVertex
-	allBehind := true
+	var clip [3]math3d.Vec4
 
-	viewProj := r.camera.ViewProjectionMatrix()
 	normLight := lightDir.Normalize()
 
 	for i := range 3 {
 		clipPos := viewProj.MulVec4(math3d.V4FromV3(tri.V[i].Position, 1))
-
-		if clipPos.W > 0 {
-			allBehind = false
-		}
+		clip[i] = clipPos
 
 		if clipPos.W != 0 {
 			invW := 1.0 / clipPos.W
@@ -53,16 +58,16 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 
 		// Per-vertex lighting
 		intensity := math.Max(0, tri.V[i].Normal.Dot(normLight))
-		intensity = 0.3 + 0.7*intensity
+		intensity = r.ambient(tri.V[i].AOUV) + 0.7*intensity
 
 		sv[i].Color = RGB(
-			uint8(float64(tri.V[i].Color.R)*intensity),
-			uint8(float64(tri.V[i].Color.G)*intensity),
-			uint8(float64(tri.V[i].Color.B)*intensity),
+			r.channel(float64(tri.V[i].Color.R)*intensity),
+			r.channel(float64(tri.V[i].Color.G)*intensity),
+			r.channel(float64(tri.V[i].Color.B)*intensity),
 		)
 	}
 
-	if allBehind {
+	if r.cullTriangle(clip) {
 		return
 	}
 
@@ -72,7 +77,8 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 	edge2X := sv[2].X - sv[0].X
 	edge2Y := sv[2].Y - sv[0].Y
 	cross := edge1X*edge2Y - edge1Y*edge2X
-	if cross < 0 && !r.DisableBackfaceCulling {
+	if r.backfacing(cross) {
+		r.CullingStats.TrianglesBackface++
 		return
 	}
 
@@ -81,6 +87,10 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 	maxX := int(math.Min(float64(r.width-1), math.Ceil(max3(sv[0].X, sv[1].X, sv[2].X))))
 	minY := int(math.Max(0, math.Floor(min3(sv[0].Y, sv[1].Y, sv[2].Y))))
 	maxY := int(math.Min(float64(r.height-1), math.Ceil(max3(sv[0].Y, sv[1].Y, sv[2].Y))))
+	top := minY
+	if r.banded {
+		minY, maxY = max(minY, r.bandTop), min(maxY, r.bandBottom)
+	}
 
 	if minX > maxX || minY > maxY {
 		return
@@ -97,6 +107,14 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 	if area2 == 0 {
 		return
 	}
+	if area2 < 0 {
+		// A back face drawn with culling off: flip the edge functions so
+		// its inside is positive too
+		A0, B0, C0 = -A0, -B0, -C0
+		A1, B1, C1 = -A1, -B1, -C1
+		A2, B2, C2 = -A2, -B2, -C2
+		area2 = -area2
+	}
 	invArea := 1.0 / area2
 
 	// Pre-compute depth deltas
@@ -104,6 +122,14 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 	dZ1 := sv[1].Z
 	dZ2 := sv[2].Z
 
+	// Colors interpolate perspective-correctly, by 1/W
+	var invW [3]float64
+	for i := range 3 {
+		if sv[i].W != 0 {
+			invW[i] = 1.0 / sv[i].W
+		}
+	}
+
 	// Pre-compute color components
 	r0, g0, b0 := float64(sv[0].Color.R), float64(sv[0].Color.G), float64(sv[0].Color.B)
 	r1, g1, b1 := float64(sv[1].Color.R), float64(sv[1].Color.G), float64(sv[1].Color.B)
@@ -111,51 +137,84 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 
 	// Evaluate edge functions at top-left corner of bounding box
 	px := float64(minX) + 0.5
-	py := float64(minY) + 0.5
+	py := float64(top) + 0.5
 
 	w0Row := edgeFunc(A0, B0, C0, px, py)
 	w1Row := edgeFunc(A1, B1, C1, px, py)
 	w2Row := edgeFunc(A2, B2, C2, px, py)
 
+	// A band steps down from the triangle's top like a serial draw would,
+	// so rounding can't put an edge pixel in a different triangle
+	for range minY - top {
+		w0Row += B0
+		w1Row += B1
+		w2Row += B2
+	}
+
 	width := r.width
 	zbuffer := r.zbuffer
 	fb := r.fb
+	tiles := &r.tiles
+	nearZ, tested := r.nearestDepth(&sv)
 
-	// Rasterize using incremental edge functions
+	// Rasterize using incremental edge functions, a tile's span of the row
+	// at a time
 	for y := minY; y <= maxY; y++ {
 		w0 := w0Row
 		w1 := w1Row
 		w2 := w2Row
 		rowOffset := y * width
-
-		for x := minX; x <= maxX; x++ {
-			// Check if inside triangle (all edge functions >= 0)
-			if w0 >= 0 && w1 >= 0 && w2 >= 0 {
-				// Compute barycentric coordinates
-				bc0 := w0 * invArea
-				bc1 := w1 * invArea
-				bc2 := w2 * invArea
-
-				// Interpolate depth
-				z := bc0*dZ0 + bc1*dZ1 + bc2*dZ2
-
-				// Z-buffer test (no bounds check - we're within clamped bounds)
-				idx := rowOffset + x
-				if z < zbuffer[idx] {
-					// Interpolate color
-					cr := uint8(r0*bc0 + r1*bc1 + r2*bc2)
-					cg := uint8(g0*bc0 + g1*bc1 + g2*bc2)
-					cb := uint8(b0*bc0 + b1*bc1 + b2*bc2)
-
-					zbuffer[idx] = z
-					fb.SetPixel(x, y, RGB(cr, cg, cb))
+		tileRow := tiles.tile(0, y)
+
+		for x := minX; x <= maxX; {
+			end := min(x|(depthTile-1), maxX)
+			tile := tileRow + x/depthTile
+			if tested && nearZ >= tiles.far[tile] {
+				// Hidden behind everything already in the tile. Stepping
+				// pixel by pixel keeps the edge functions exactly as if
+				// the span had been drawn.
+				for ; x <= end; x++ {
+					w0 += A0
+					w1 += A1
+					w2 += A2
 				}
+				continue
 			}
 
-			// Step in X direction
-			w0 += A0
-			w1 += A1
-			w2 += A2
+			for ; x <= end; x++ {
+				// Check if inside triangle (all edge functions >= 0)
+				if w0 >= 0 && w1 >= 0 && w2 >= 0 {
+					// Compute barycentric coordinates
+					bc0 := w0 * invArea
+					bc1 := w1 * invArea
+					bc2 := w2 * invArea
+
+					// Interpolate depth
+					z := float32(bc0*dZ0 + bc1*dZ1 + bc2*dZ2)
+
+					// Z-buffer test (no bounds check - we're within clamped bounds)
+					idx := rowOffset + x
+					if z < zbuffer[idx] {
+						// Interpolate color
+						p := perspectiveWeights(math3d.V3(bc0, bc1, bc2), invW)
+						cr := r.channel(r0*p.X + r1*p.Y + r2*p.Z)
+						cg := r.channel(g0*p.X + g1*p.Y + g2*p.Z)
+						cb := r.channel(b0*p.X + b1*p.Y + b2*p.Z)
+
+						zbuffer[idx] = z
+						tiles.dirty[tile] = true
+						if r.faceIDs != nil {
+							r.faceIDs[idx] = r.currentFace
+						}
+						fb.SetPixel(x, y, RGB(cr, cg, cb))
+					}
+				}
+
+				// Step in X direction
+				w0 += A0
+				w1 += A1
+				w2 += A2
+			}
 		}
 
 		// Step in Y direction
@@ -163,6 +222,7 @@ func (r *Rasterizer) DrawTriangleGouraudOpt(tri Triangle, lightDir math3d.Vec3)
 		w1Row += B1
 		w2Row += B2
 	}
+	r.refreshTiles(minX, minY, maxX, maxY)
 }
 
 // DrawMeshGouraudOpt renders a mesh with optimized Gouraud shading.
@@ -171,48 +231,63 @@ func (r *Rasterizer) DrawMeshGouraudOpt(mesh MeshRenderer, transform math3d.Mat4
 		return
 	}
 
-	for i := 0; i < mesh.TriangleCount(); i++ {
-		face := mesh.GetFace(i)
-
-		p0, n0, _ := mesh.GetVertex(face[0])
-		p1, n1, _ := mesh.GetVertex(face[1])
-		p2, n2, _ := mesh.GetVertex(face[2])
-
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
-
-		wn0 := transform.MulVec3Dir(n0).Normalize()
-		wn1 := transform.MulVec3Dir(n1).Normalize()
-		wn2 := transform.MulVec3Dir(n2).Normalize()
+	r.meshDraw = meshDraw{
+		mesh:     mesh,
+		verts:    r.transformVertices(mesh, transform),
+		viewProj: r.viewProjection(),
+		lightDir: lightDir,
+		color:    color,
+	}
+	r.drawFaces(mesh.TriangleCount(), func(r *Rasterizer, i int) {
+		d := &r.meshDraw
+		r.drawTriangleGouraudOpt(coloredTriangle(d.verts, d.mesh.GetFace(i), d.color), d.lightDir, d.viewProj)
+	})
+}
 
-		tri := Triangle{
-			V: [3]Vertex{
-				{Position: v0, Normal: wn0, Color: color},
-				{Position: v1, Normal: wn1, Color: color},
-				{Position: v2, Normal: wn2, Color: color},
-			},
-		}
+// DrawMeshVertexColorsOpt renders a mesh with optimized Gouraud shading,
+// coloring each vertex from colors, which is indexed like the vertices.
+func (r *Rasterizer) DrawMeshVertexColorsOpt(mesh MeshRenderer, transform math3d.Mat4, colors []Color, lightDir math3d.Vec3) {
+	if r.tryFrustumCull(mesh, transform) {
+		return
+	}
 
-		r.DrawTriangleGouraudOpt(tri, lightDir)
+	r.meshDraw = meshDraw{
+		mesh:     mesh,
+		verts:    r.transformVertices(mesh, transform),
+		viewProj: r.viewProjection(),
+		lightDir: lightDir,
+		colors:   colors,
 	}
+	r.drawFaces(mesh.TriangleCount(), func(r *Rasterizer, i int) {
+		d := &r.meshDraw
+		face := d.mesh.GetFace(i)
+		tri := coloredTriangle(d.verts, face, Color{})
+		for j, idx := range face {
+			tri.V[j].Color = d.colors[idx]
+		}
+		r.drawTriangleGouraudOpt(tri, d.lightDir, d.viewProj)
+	})
 }
 
 // DrawTriangleTexturedOpt is an optimized textured triangle rasterizer with Gouraud shading.
 func (r *Rasterizer) DrawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDir math3d.Vec3) {
+	r.drawTriangleTexturedOpt(tri, tex, lightDir, r.viewProjection())
+}
+
+// drawTriangleTexturedOpt is DrawTriangleTexturedOpt with the
+// view-projection matrix passed in.
+func (r *Rasterizer) drawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDir math3d.Vec3, viewProj math3d.Mat4) {
+	r.flatNormals(&tri)
+
 	var sv [3]screenVertex
+	var clip [3]math3d.Vec4
 	var vertexIntensity [3]float64
-	allBehind := true
 
-	viewProj := r.camera.ViewProjectionMatrix()
 	normLight := lightDir.Normalize()
 
 	for i := range 3 {
 		clipPos := viewProj.MulVec4(math3d.V4FromV3(tri.V[i].Position, 1))
-
-		if clipPos.W > 0 {
-			allBehind = false
-		}
+		clip[i] = clipPos
 
 		if clipPos.W != 0 {
 			invW := 1.0 / clipPos.W
@@ -225,13 +300,18 @@ func (r *Rasterizer) DrawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDi
 		sv[i].X = (sv[i].X + 1) * 0.5 * float64(r.width)
 		sv[i].Y = (1 - sv[i].Y) * 0.5 * float64(r.height)
 		sv[i].UV = tri.V[i].UV
+		sv[i].AOUV = tri.V[i].AOUV
 
-		// Per-vertex lighting (Gouraud)
+		// Per-vertex lighting (Gouraud). With an occlusion map the ambient
+		// term is sampled per pixel instead.
 		intensity := math.Max(0, tri.V[i].Normal.Dot(normLight))
-		vertexIntensity[i] = 0.3 + 0.7*intensity
+		vertexIntensity[i] = 0.7 * intensity
+		if r.occlusion == nil {
+			vertexIntensity[i] += ambientLight
+		}
 	}
 
-	if allBehind {
+	if r.cullTriangle(clip) {
 		return
 	}
 
@@ -241,7 +321,8 @@ func (r *Rasterizer) DrawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDi
 	edge2X := sv[2].X - sv[0].X
 	edge2Y := sv[2].Y - sv[0].Y
 	cross := edge1X*edge2Y - edge1Y*edge2X
-	if cross < 0 && !r.DisableBackfaceCulling {
+	if r.backfacing(cross) {
+		r.CullingStats.TrianglesBackface++
 		return
 	}
 
@@ -249,6 +330,10 @@ func (r *Rasterizer) DrawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDi
 	maxX := int(math.Min(float64(r.width-1), math.Ceil(max3(sv[0].X, sv[1].X, sv[2].X))))
 	minY := int(math.Max(0, math.Floor(min3(sv[0].Y, sv[1].Y, sv[2].Y))))
 	maxY := int(math.Min(float64(r.height-1), math.Ceil(max3(sv[0].Y, sv[1].Y, sv[2].Y))))
+	top := minY
+	if r.banded {
+		minY, maxY = max(minY, r.bandTop), min(maxY, r.bandBottom)
+	}
 
 	if minX > maxX || minY > maxY {
 		return
@@ -263,6 +348,14 @@ func (r *Rasterizer) DrawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDi
 	if area2 == 0 {
 		return
 	}
+	if area2 < 0 {
+		// A back face drawn with culling off: flip the edge functions so
+		// its inside is positive too
+		A0, B0, C0 = -A0, -B0, -C0
+		A1, B1, C1 = -A1, -B1, -C1
+		A2, B2, C2 = -A2, -B2, -C2
+		area2 = -area2
+	}
 	invArea := 1.0 / area2
 
 	// Perspective-correct interpolation: precompute 1/W
@@ -274,63 +367,110 @@ func (r *Rasterizer) DrawTriangleTexturedOpt(tri Triangle, tex *Texture, lightDi
 	}
 
 	px := float64(minX) + 0.5
-	py := float64(minY) + 0.5
+	py := float64(top) + 0.5
 
 	w0Row := edgeFunc(A0, B0, C0, px, py)
 	w1Row := edgeFunc(A1, B1, C1, px, py)
 	w2Row := edgeFunc(A2, B2, C2, px, py)
 
+	// Step down to the band as a serial draw would
+	for range minY - top {
+		w0Row += B0
+		w1Row += B1
+		w2Row += B2
+	}
+
 	width := r.width
 	zbuffer := r.zbuffer
 	fb := r.fb
 
+	// The default texture modes have a faster sampler
+	fast, isFast := tex.nearestSampler()
+	if isFast {
+		r.TextureStats.Width, r.TextureStats.Height, r.TextureStats.Filter = tex.Width, tex.Height, FilterNearest
+	}
+
+	tiles := &r.tiles
+	nearZ, tested := r.nearestDepth(&sv)
+
 	for y := minY; y <= maxY; y++ {
 		w0 := w0Row
 		w1 := w1Row
 		w2 := w2Row
 		rowOffset := y * width
+		tileRow := tiles.tile(0, y)
+
+		for x := minX; x <= maxX; {
+			end := min(x|(depthTile-1), maxX)
+			tile := tileRow + x/depthTile
+			if tested && nearZ >= tiles.far[tile] {
+				// Hidden; see drawTriangleGouraudOpt
+				for ; x <= end; x++ {
+					w0 += A0
+					w1 += A1
+					w2 += A2
+				}
+				continue
+			}
 
-		for x := minX; x <= maxX; x++ {
-			if w0 >= 0 && w1 >= 0 && w2 >= 0 {
-				bc0 := w0 * invArea
-				bc1 := w1 * invArea
-				bc2 := w2 * invArea
-
-				z := bc0*sv[0].Z + bc1*sv[1].Z + bc2*sv[2].Z
-
-				idx := rowOffset + x
-				if z < zbuffer[idx] {
-					// Perspective-correct interpolation
-					pw0 := bc0 * invW[0]
-					pw1 := bc1 * invW[1]
-					pw2 := bc2 * invW[2]
-					oneOverW := pw0 + pw1 + pw2
-					if oneOverW != 0 {
-						invOneOverW := 1.0 / oneOverW
-						u := (pw0*sv[0].UV.X + pw1*sv[1].UV.X + pw2*sv[2].UV.X) * invOneOverW
-						v := (pw0*sv[0].UV.Y + pw1*sv[1].UV.Y + pw2*sv[2].UV.Y) * invOneOverW
-
-						// Perspective-correct lighting intensity
-						intensity := (pw0*vertexIntensity[0] + pw1*vertexIntensity[1] + pw2*vertexIntensity[2]) * invOneOverW
-
-						texColor := tex.Sample(u, v)
-						litColor := MultiplyColor(texColor, intensity)
-
-						zbuffer[idx] = z
-						fb.SetPixel(x, y, litColor)
+			for ; x <= end; x++ {
+				if w0 >= 0 && w1 >= 0 && w2 >= 0 {
+					bc0 := w0 * invArea
+					bc1 := w1 * invArea
+					bc2 := w2 * invArea
+
+					z := float32(bc0*sv[0].Z + bc1*sv[1].Z + bc2*sv[2].Z)
+
+					idx := rowOffset + x
+					if z < zbuffer[idx] {
+						// Perspective-correct interpolation
+						pw0 := bc0 * invW[0]
+						pw1 := bc1 * invW[1]
+						pw2 := bc2 * invW[2]
+						oneOverW := pw0 + pw1 + pw2
+						if oneOverW != 0 {
+							invOneOverW := 1.0 / oneOverW
+							u := (pw0*sv[0].UV.X + pw1*sv[1].UV.X + pw2*sv[2].UV.X) * invOneOverW
+							v := (pw0*sv[0].UV.Y + pw1*sv[1].UV.Y + pw2*sv[2].UV.Y) * invOneOverW
+
+							// Perspective-correct lighting intensity
+							intensity := (pw0*vertexIntensity[0] + pw1*vertexIntensity[1] + pw2*vertexIntensity[2]) * invOneOverW
+							if r.occlusion != nil {
+								intensity += r.ambient(math3d.V2(
+									(pw0*sv[0].AOUV.X+pw1*sv[1].AOUV.X+pw2*sv[2].AOUV.X)*invOneOverW,
+									(pw0*sv[0].AOUV.Y+pw1*sv[1].AOUV.Y+pw2*sv[2].AOUV.Y)*invOneOverW,
+								))
+							}
+
+							var texColor Color
+							if isFast {
+								texColor = r.sampleNearest(&fast, u, v)
+							} else {
+								texColor = r.sampleTexture(tex, u, v)
+							}
+							litColor := r.light(texColor, intensity)
+
+							zbuffer[idx] = z
+							tiles.dirty[tile] = true
+							if r.faceIDs != nil {
+								r.faceIDs[idx] = r.currentFace
+							}
+							fb.SetPixel(x, y, litColor)
+						}
 					}
 				}
-			}
 
-			w0 += A0
-			w1 += A1
-			w2 += A2
+				w0 += A0
+				w1 += A1
+				w2 += A2
+			}
 		}
 
 		w0Row += B0
 		w1Row += B1
 		w2Row += B2
 	}
+	r.refreshTiles(minX, minY, maxX, maxY)
 }
 
 // DrawMeshTexturedOpt renders a textured mesh with optimized rasterization.
@@ -339,29 +479,84 @@ func (r *Rasterizer) DrawMeshTexturedOpt(mesh MeshRenderer, transform math3d.Mat
 		return
 	}
 
-	for i := 0; i < mesh.TriangleCount(); i++ {
-		face := mesh.GetFace(i)
-
-		p0, n0, uv0 := mesh.GetVertex(face[0])
-		p1, n1, uv1 := mesh.GetVertex(face[1])
-		p2, n2, uv2 := mesh.GetVertex(face[2])
-
-		v0 := transform.MulVec3(p0)
-		v1 := transform.MulVec3(p1)
-		v2 := transform.MulVec3(p2)
+	r.meshDraw = meshDraw{
+		mesh:     mesh,
+		verts:    r.transformVertices(mesh, transform),
+		viewProj: r.viewProjection(),
+		lightDir: lightDir,
+		tex:      tex,
+	}
+	r.drawFaces(mesh.TriangleCount(), func(r *Rasterizer, i int) {
+		d := &r.meshDraw
+		r.drawTriangleTexturedOpt(texturedTriangle(d.verts, d.mesh.GetFace(i)), d.tex, d.lightDir, d.viewProj)
+	})
+}
 
-		wn0 := transform.MulVec3Dir(n0).Normalize()
-		wn1 := transform.MulVec3Dir(n1).Normalize()
-		wn2 := transform.MulVec3Dir(n2).Normalize()
+// meshDraw holds what a DrawMesh*Opt call draws its faces with. It lives
+// in the Rasterizer, which every band copies, so the face callbacks
+// capture nothing and drawing serially doesn't allocate a closure.
+type meshDraw struct {
+	mesh     MeshRenderer
+	verts    []transformedVertex
+	viewProj math3d.Mat4
+	lightDir math3d.Vec3
+	color    Color
+	colors   []Color
+	tex      *Texture
+}
 
-		tri := Triangle{
-			V: [3]Vertex{
-				{Position: v0, Normal: wn0, UV: uv0, Color: RGB(255, 255, 255)},
-				{Position: v1, Normal: wn1, UV: uv1, Color: RGB(255, 255, 255)},
-				{Position: v2, Normal: wn2, UV: uv2, Color: RGB(255, 255, 255)},
-			},
+// drawFaces calls draw for each of a mesh's n faces, recording it as the
+// face being drawn, then clears r.meshDraw. With Workers set, the frame's
+// rows are split into bands of whole depth tiles, each drawn by its own
+// goroutine with its own copy of r limited to the band, and their stats
+// are merged back into r.
+func (r *Rasterizer) drawFaces(n int, draw func(r *Rasterizer, face int)) {
+	tileRows := (r.height + depthTile - 1) / depthTile
+	workers := min(r.Workers, tileRows)
+	if workers < 2 || r.Deterministic {
+		for i := range n {
+			r.currentFace = int32(r.FaceBase + i)
+			draw(r, i)
 		}
+		r.currentFace = noFace
+		r.meshDraw = meshDraw{}
+		return
+	}
 
-		r.DrawTriangleTexturedOpt(tri, tex, lightDir)
+	bands := make([]Rasterizer, workers)
+	var wg sync.WaitGroup
+	for b := range bands {
+		band := &bands[b]
+		*band = *r
+		band.CullingStats, band.TextureStats = CullingStats{}, TextureStats{}
+		band.banded = true
+		band.bandTop = b * tileRows / workers * depthTile
+		band.bandBottom = min((b+1)*tileRows/workers*depthTile, r.height) - 1
+		wg.Go(func() {
+			for i := range n {
+				band.currentFace = int32(r.FaceBase + i)
+				draw(band, i)
+			}
+		})
+	}
+	wg.Wait()
+	r.meshDraw = meshDraw{}
+
+	// Every band sets up every triangle, so any one has the triangle counts;
+	// each pixel is drawn by one band, so texture samples add up
+	cs := bands[0].CullingStats
+	r.CullingStats.TrianglesTested += cs.TrianglesTested
+	r.CullingStats.TrianglesCulled += cs.TrianglesCulled
+	r.CullingStats.TrianglesBackface += cs.TrianglesBackface
+	for _, band := range bands {
+		ts := band.TextureStats
+		if ts.Samples == 0 {
+			continue
+		}
+		r.TextureStats.Samples += ts.Samples
+		r.TextureStats.Nearest += ts.Nearest
+		r.TextureStats.Bilinear += ts.Bilinear
+		r.TextureStats.RowJumps += ts.RowJumps
+		r.TextureStats.Width, r.TextureStats.Height, r.TextureStats.Filter = ts.Width, ts.Height, ts.Filter
 	}
 }
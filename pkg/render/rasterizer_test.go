@@ -92,7 +92,7 @@ func TestInterpolateColor3(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := interpolateColor3(c0, c1, c2, tc.bc)
+			result := (&Rasterizer{}).interpolateColor3(c0, c1, c2, tc.bc)
 			// Allow 1 unit tolerance due to rounding
 			if absInt(int(result.R)-int(tc.expected.R)) > 1 ||
 				absInt(int(result.G)-int(tc.expected.G)) > 1 ||
@@ -103,6 +103,61 @@ func TestInterpolateColor3(t *testing.T) {
 	}
 }
 
+func TestDrawTrianglePerspectiveColor(t *testing.T) {
+	// A triangle receding steeply from the camera, one vertex near and two
+	// far, so screen-space and 3D interpolation disagree
+	tri := Triangle{V: [3]Vertex{
+		{Position: math3d.V3(0, -2, 6), Color: RGB(255, 0, 0)},
+		{Position: math3d.V3(-8, 4, -30), Color: RGB(0, 255, 0)},
+		{Position: math3d.V3(8, 4, -30), Color: RGB(0, 0, 255)},
+	}}
+	for i := range tri.V {
+		tri.V[i].Normal = math3d.V3(0, 0, 1)
+	}
+	centroid := tri.V[0].Position.Add(tri.V[1].Position).Add(tri.V[2].Position).Scale(1.0 / 3)
+
+	for _, draw := range []struct {
+		name string
+		fn   func(r *Rasterizer)
+	}{
+		{"DrawTriangle", func(r *Rasterizer) { r.DrawTriangle(tri) }},
+		{"DrawTriangleGouraud", func(r *Rasterizer) { r.DrawTriangleGouraud(tri, math3d.V3(0, 0, 1)) }},
+		{"DrawTriangleGouraudOpt", func(r *Rasterizer) { r.DrawTriangleGouraudOpt(tri, math3d.V3(0, 0, 1)) }},
+		{"DrawMeshVertexColorsOpt", func(r *Rasterizer) {
+			mesh := &mockMesh{faces: [][3]int{{0, 1, 2}}}
+			colors := make([]Color, 3)
+			for i, v := range tri.V {
+				mesh.vertices = append(mesh.vertices, struct {
+					pos    math3d.Vec3
+					normal math3d.Vec3
+					uv     math3d.Vec2
+				}{v.Position, v.Normal, math3d.Vec2{}})
+				colors[i] = v.Color
+			}
+			r.DrawMeshVertexColorsOpt(mesh, math3d.Identity(), colors, math3d.V3(0, 0, 1))
+		}},
+	} {
+		t.Run(draw.name, func(t *testing.T) {
+			r, fb := createTestRasterizer(100, 100)
+			r.camera.SetFOV(math.Pi / 3)
+			r.DisableBackfaceCulling = true
+			r.ClearDepth()
+			draw.fn(r)
+
+			// The 3D centroid shows an even mix of the three colors
+			x, y, _, ok := r.camera.WorldToScreen(centroid, fb.Width, fb.Height)
+			if !ok {
+				t.Fatal("centroid not on screen")
+			}
+			c := fb.GetPixel(int(x), int(y))
+			lo, hi := min(c.R, c.G, c.B), max(c.R, c.G, c.B)
+			if hi == 0 || float64(hi-lo) > 0.25*float64(hi) {
+				t.Errorf("color at the centroid = %v, want an even mix", c)
+			}
+		})
+	}
+}
+
 func TestDrawTriangleGouraud_VertexLighting(t *testing.T) {
 	r, fb := createTestRasterizer(100, 100)
 	r.ClearDepth()
@@ -224,6 +279,580 @@ func TestDrawMeshGouraud(t *testing.T) {
 	}
 }
 
+// mockLineMesh adds line and point primitives to mockMesh.
+type mockLineMesh struct {
+	mockMesh
+	lines  [][2]int
+	points []int
+}
+
+func (m *mockLineMesh) LineCount() int       { return len(m.lines) }
+func (m *mockLineMesh) GetLine(i int) [2]int { return m.lines[i] }
+func (m *mockLineMesh) PointCount() int      { return len(m.points) }
+func (m *mockLineMesh) GetPoint(i int) int   { return m.points[i] }
+
+func TestDrawMeshLines(t *testing.T) {
+	r, fb := createTestRasterizer(100, 100)
+	r.ClearDepth()
+	fb.Clear(RGB(0, 0, 0))
+
+	mesh := &mockLineMesh{
+		mockMesh: mockMesh{
+			vertices: []struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				{pos: math3d.V3(-3, 0, 0)},
+				{pos: math3d.V3(3, 0, 0)},
+				{pos: math3d.V3(0, 3, 0)},
+			},
+		},
+		lines:  [][2]int{{0, 1}},
+		points: []int{2},
+	}
+
+	r.DrawMeshLines(mesh, math3d.Identity(), RGB(255, 255, 255))
+
+	// The line lies on the center row; the point is a single pixel off it
+	lineCount, pointCount := 0, 0
+	for y := 0; y < fb.Height; y++ {
+		for x := 0; x < fb.Width; x++ {
+			if fb.GetPixel(x, y).R == 0 {
+				continue
+			}
+			if y == fb.Height/2 {
+				lineCount++
+			} else {
+				pointCount++
+			}
+		}
+	}
+	if lineCount < 2 {
+		t.Errorf("expected a horizontal line, got %d pixels", lineCount)
+	}
+	if pointCount != 1 {
+		t.Errorf("expected exactly 1 point pixel, got %d", pointCount)
+	}
+}
+
+type mockMaterialMesh struct {
+	mockMesh
+	materials []int
+}
+
+func (m *mockMaterialMesh) GetFaceMaterial(i int) int { return m.materials[i] }
+
+func TestDrawMeshWireframeColored(t *testing.T) {
+	mesh := &mockMaterialMesh{
+		mockMesh: mockMesh{
+			vertices: []struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				{pos: math3d.V3(-4, -1, 0)},
+				{pos: math3d.V3(-2, 1, 0)},
+				{pos: math3d.V3(-1, -1, 0)},
+				{pos: math3d.V3(1, -1, 0)},
+				{pos: math3d.V3(2, 1, 0)},
+				{pos: math3d.V3(4, -1, 0)},
+			},
+			faces: [][3]int{{0, 1, 2}, {3, 4, 5}},
+		},
+		materials: []int{0, 1},
+	}
+	solid := RGB(0, 255, 128)
+	normal := NormalColor(math3d.V3(2, 2, 0).Cross(math3d.V3(3, 0, 0)))
+
+	tests := []struct {
+		name    string
+		mode    WireframeColorMode
+		palette *Palette // nil keeps the rasterizer's default
+		want    []Color
+	}{
+		{"solid", WireframeSolid, nil, []Color{solid}},
+		{"material", WireframeByMaterial, nil, []Color{DefaultPalette.Materials[0], DefaultPalette.Materials[1]}},
+		{"color-blind material", WireframeByMaterial, &ColorBlindPalette,
+			[]Color{ColorBlindPalette.Materials[0], ColorBlindPalette.Materials[1]}},
+		{"normal", WireframeByNormal, nil, []Color{normal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, fb := createTestRasterizer(100, 100)
+			if tt.palette != nil {
+				r.Palette = *tt.palette
+			}
+			fb.Clear(RGB(0, 0, 0))
+			r.DrawMeshWireframeColored(mesh, math3d.Identity(), solid, tt.mode)
+
+			seen := map[Color]int{}
+			for y := 0; y < fb.Height; y++ {
+				for x := 0; x < fb.Width; x++ {
+					if c := fb.GetPixel(x, y); c != RGB(0, 0, 0) {
+						seen[c]++
+					}
+				}
+			}
+			if len(seen) != len(tt.want) {
+				t.Errorf("drew colors %v, want %v", seen, tt.want)
+			}
+			for _, c := range tt.want {
+				if seen[c] == 0 {
+					t.Errorf("no edges drawn in %v; got %v", c, seen)
+				}
+			}
+		})
+	}
+}
+
+func TestPickFace(t *testing.T) {
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+			{math3d.V3(5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+			{math3d.V3(5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+			{math3d.V3(-5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+		},
+		faces: [][3]int{
+			{0, 3, 2},
+			{0, 2, 1},
+		},
+	}
+
+	tests := []struct {
+		name string
+		draw func(r *Rasterizer)
+	}{
+		{"gouraud", func(r *Rasterizer) {
+			r.DrawMeshGouraud(mesh, math3d.Identity(), RGB(255, 255, 255), math3d.V3(0, 0, 1))
+		}},
+		{"gouraud opt", func(r *Rasterizer) {
+			r.DrawMeshGouraudOpt(mesh, math3d.Identity(), RGB(255, 255, 255), math3d.V3(0, 0, 1))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, fb := createTestRasterizer(100, 100)
+			r.EnablePicking(true)
+			r.ClearDepth()
+			fb.Clear(RGB(0, 0, 0))
+			tt.draw(r)
+
+			seen := map[int]int{}
+			for y := 0; y < fb.Height; y++ {
+				for x := 0; x < fb.Width; x++ {
+					id := r.PickFace(x, y)
+					c := fb.GetPixel(x, y)
+					lit := c.R > 0 || c.G > 0 || c.B > 0
+					if lit != (id >= 0) {
+						t.Fatalf("pixel (%d,%d): lit=%v but face %d", x, y, lit, id)
+					}
+					seen[id]++
+				}
+			}
+			if seen[0] == 0 || seen[1] == 0 {
+				t.Errorf("expected both faces to be pickable, got %v", seen)
+			}
+
+			r.ClearDepth()
+			if id := r.PickFace(50, 50); id != -1 {
+				t.Errorf("after ClearDepth PickFace = %d, want -1", id)
+			}
+		})
+	}
+
+	r, _ := createTestRasterizer(100, 100)
+	r.EnablePicking(true)
+	r.ClearDepth()
+	r.FaceBase = 10
+	r.DrawMeshGouraudOpt(mesh, math3d.Identity(), RGB(255, 255, 255), math3d.V3(0, 0, 1))
+	if id := r.PickFace(50, 50); id != 10 && id != 11 {
+		t.Errorf("PickFace with FaceBase 10 = %d, want 10 or 11", id)
+	}
+
+	r, _ = createTestRasterizer(10, 10)
+	r.DrawMeshGouraud(mesh, math3d.Identity(), RGB(255, 255, 255), math3d.V3(0, 0, 1))
+	if id := r.PickFace(5, 5); id != -1 {
+		t.Errorf("PickFace without picking enabled = %d, want -1", id)
+	}
+	r.EnablePicking(true)
+	if id := r.PickFace(-1, 5); id != -1 {
+		t.Errorf("out of bounds PickFace = %d, want -1", id)
+	}
+}
+
+func TestDrawMeshGouraudOpt_MatchesPerFaceTransform(t *testing.T) {
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+			{math3d.V3(5, -5, 0), math3d.V3(0.3, 0, 1).Normalize(), math3d.V2(1, 0)},
+			{math3d.V3(5, 5, 0), math3d.V3(0, 0.3, 1).Normalize(), math3d.V2(1, 1)},
+			{math3d.V3(-5, 5, 0), math3d.V3(-0.3, 0, 1).Normalize(), math3d.V2(0, 1)},
+		},
+		faces: [][3]int{
+			{0, 3, 2},
+			{0, 2, 1},
+		},
+	}
+	transform := math3d.RotateY(0.3).Mul(math3d.Scale(math3d.V3(1, 0.8, 1.5)))
+	color := RGB(255, 100, 50)
+	lightDir := math3d.V3(0.2, 0.5, 1)
+
+	// Cached path
+	r1, fb1 := createTestRasterizer(100, 100)
+	r1.ClearDepth()
+	fb1.Clear(RGB(0, 0, 0))
+	r1.DrawMeshGouraudOpt(mesh, transform, color, lightDir)
+
+	// Reference: transform each face's vertices independently
+	r2, fb2 := createTestRasterizer(100, 100)
+	r2.ClearDepth()
+	fb2.Clear(RGB(0, 0, 0))
+	normalMat := transform.NormalMatrix()
+	for _, face := range mesh.faces {
+		var tri Triangle
+		for i, idx := range face {
+			p, n, _ := mesh.GetVertex(idx)
+			tri.V[i] = Vertex{
+				Position: transform.MulVec3(p),
+				Normal:   normalMat.MulVec3Dir(n).Normalize(),
+				Color:    color,
+			}
+		}
+		r2.DrawTriangleGouraudOpt(tri, lightDir)
+	}
+
+	for i := range fb1.Pixels {
+		if fb1.Pixels[i] != fb2.Pixels[i] {
+			t.Fatalf("pixel %d differs: cached %v, reference %v", i, fb1.Pixels[i], fb2.Pixels[i])
+		}
+	}
+}
+
+func TestDrawMeshVertexColorsOpt(t *testing.T) {
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+			{math3d.V3(5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+			{math3d.V3(5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+			{math3d.V3(-5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+		},
+		faces: [][3]int{
+			{0, 3, 2},
+			{0, 2, 1},
+		},
+	}
+	lightDir := math3d.V3(0, 0, 1)
+	color := RGB(255, 100, 50)
+
+	// The same color at every vertex matches a single-color draw
+	r1, fb1 := createTestRasterizer(100, 100)
+	r1.ClearDepth()
+	fb1.Clear(RGB(0, 0, 0))
+	r1.DrawMeshVertexColorsOpt(mesh, math3d.Identity(), []Color{color, color, color, color}, lightDir)
+
+	r2, fb2 := createTestRasterizer(100, 100)
+	r2.ClearDepth()
+	fb2.Clear(RGB(0, 0, 0))
+	r2.DrawMeshGouraudOpt(mesh, math3d.Identity(), color, lightDir)
+
+	for i := range fb1.Pixels {
+		if fb1.Pixels[i] != fb2.Pixels[i] {
+			t.Fatalf("pixel %d differs: vertex colors %v, single color %v", i, fb1.Pixels[i], fb2.Pixels[i])
+		}
+	}
+
+	// Differing colors blend across the faces
+	r1.camera.SetFOV(math.Pi / 3)
+	r1.InvalidateFrustum()
+	r1.ClearDepth()
+	fb1.Clear(RGB(0, 0, 0))
+	blue, red := RGB(0, 0, 255), RGB(255, 0, 0)
+	r1.DrawMeshVertexColorsOpt(mesh, math3d.Identity(), []Color{blue, red, red, blue}, lightDir)
+	left, right := fb1.GetPixel(30, 50), fb1.GetPixel(70, 50)
+	if left.B <= left.R || right.R <= right.B {
+		t.Errorf("left %v should be bluer and right %v redder", left, right)
+	}
+}
+
+func TestHeatColor(t *testing.T) {
+	tests := []struct {
+		t    float64
+		want Color
+	}{
+		{-1, RGB(0, 0, 255)},
+		{0, RGB(0, 0, 255)},
+		{0.5, RGB(0, 255, 0)},
+		{1, RGB(255, 0, 0)},
+		{2, RGB(255, 0, 0)},
+	}
+	for _, tt := range tests {
+		if got := HeatColor(tt.t); got != tt.want {
+			t.Errorf("HeatColor(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestTransformVerticesNormals(t *testing.T) {
+	type vertex = struct {
+		pos    math3d.Vec3
+		normal math3d.Vec3
+		uv     math3d.Vec2
+	}
+	mesh := &mockMesh{vertices: []vertex{
+		{normal: math3d.V3(1, 2, 2).Normalize()},
+		{normal: math3d.V3(0, 3, 0)}, // Not unit, as some files have
+	}}
+
+	tests := []struct {
+		name      string
+		transform math3d.Mat4
+	}{
+		{"rotation", math3d.RotateX(0.7).Mul(math3d.RotateY(-2.1))},
+		{"scale", math3d.Scale(math3d.V3(1, 4, 0.5))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := createTestRasterizer(10, 10)
+			normalMat := tt.transform.NormalMatrix()
+			for i, v := range r.transformVertices(mesh, tt.transform) {
+				want := normalMat.MulVec3Dir(mesh.vertices[i].normal).Normalize()
+				if v.Normal.Sub(want).Len() > 1e-6 {
+					t.Errorf("vertex %d normal = %v, want %v", i, v.Normal, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBeginFrame(t *testing.T) {
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+			{math3d.V3(5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+			{math3d.V3(5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+		},
+		faces: [][3]int{{0, 1, 2}},
+	}
+	r, fb := createTestRasterizer(40, 40)
+	r.camera.SetFOV(math.Pi / 3)
+	draw := func() int {
+		fb.Clear(ColorBlack)
+		r.ClearDepth()
+		r.DrawMeshGouraudOpt(mesh, math3d.Identity(), ColorWhite, math3d.V3(0, 0, 1))
+		drawn := 0
+		for _, c := range fb.Pixels {
+			if c != ColorBlack {
+				drawn++
+			}
+		}
+		return drawn
+	}
+
+	r.BeginFrame()
+	if draw() == 0 {
+		t.Fatal("triangle not drawn")
+	}
+
+	// Turning the camera away mid-frame doesn't move what the frame draws
+	r.camera.SetPosition(math3d.V3(0, 0, -10))
+	if draw() == 0 {
+		t.Error("camera change showed before the next BeginFrame")
+	}
+	r.BeginFrame()
+	if n := draw(); n != 0 {
+		t.Errorf("drew %d pixels after turning the camera away", n)
+	}
+}
+
+func TestFlatShading(t *testing.T) {
+	// A flat quad with tilted vertex normals, so smooth shading varies
+	// across it and flat shading doesn't
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-5, -5, 0), math3d.V3(-0.5, -0.5, 1).Normalize(), math3d.V2(0, 0)},
+			{math3d.V3(5, -5, 0), math3d.V3(0.5, -0.5, 1).Normalize(), math3d.V2(1, 0)},
+			{math3d.V3(5, 5, 0), math3d.V3(0.5, 0.5, 1).Normalize(), math3d.V2(1, 1)},
+			{math3d.V3(-5, 5, 0), math3d.V3(-0.5, 0.5, 1).Normalize(), math3d.V2(0, 1)},
+		},
+		faces: [][3]int{
+			{0, 3, 2},
+			{0, 2, 1},
+		},
+	}
+	white := RGB(255, 255, 255)
+	lightDir := math3d.V3(0.3, 0, 1)
+
+	draws := []struct {
+		name string
+		draw func(r *Rasterizer)
+	}{
+		{"gouraud", func(r *Rasterizer) { r.DrawMeshGouraudOpt(mesh, math3d.Identity(), white, lightDir) }},
+		{"textured", func(r *Rasterizer) {
+			r.DrawMeshTexturedOpt(mesh, math3d.Identity(), NewCheckerTexture(4, 4, 2, white, white), lightDir)
+		}},
+	}
+	for _, d := range draws {
+		for _, flat := range []bool{false, true} {
+			r, fb := createTestRasterizer(100, 100)
+			r.camera.SetFOV(math.Pi / 3)
+			r.DisableBackfaceCulling = true
+			r.FlatShading = flat
+			r.ClearDepth()
+			fb.Clear(RGB(0, 0, 0))
+			d.draw(r)
+
+			left, right := fb.GetPixel(35, 50), fb.GetPixel(65, 50)
+			if left == (Color{A: 255}) {
+				t.Fatalf("%s: quad not drawn", d.name)
+			}
+			if uniform := left == right; uniform != flat {
+				t.Errorf("%s, flat %v: left %v, right %v", d.name, flat, left, right)
+			}
+		}
+	}
+}
+
+func TestMissingNormals(t *testing.T) {
+	// A quad facing the camera, drawn with its true normals and with none
+	quad := func(normal math3d.Vec3) *mockMesh {
+		mesh := &mockMesh{faces: [][3]int{{0, 3, 2}, {0, 2, 1}}}
+		for _, p := range []math3d.Vec3{math3d.V3(-5, -5, 0), math3d.V3(5, -5, 0), math3d.V3(5, 5, 0), math3d.V3(-5, 5, 0)} {
+			mesh.vertices = append(mesh.vertices, struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{p, normal, math3d.V2(0, 0)})
+		}
+		return mesh
+	}
+	white := RGB(255, 255, 255)
+	lightDir := math3d.V3(0, 0, 1)
+
+	draws := []struct {
+		name string
+		draw func(r *Rasterizer, mesh *mockMesh)
+	}{
+		{"gouraud", func(r *Rasterizer, mesh *mockMesh) { r.DrawMeshGouraudOpt(mesh, math3d.Identity(), white, lightDir) }},
+		{"textured", func(r *Rasterizer, mesh *mockMesh) {
+			r.DrawMeshTexturedOpt(mesh, math3d.Identity(), NewCheckerTexture(4, 4, 2, white, white), lightDir)
+		}},
+	}
+	for _, d := range draws {
+		var got [2]Color
+		for i, normal := range []math3d.Vec3{{Z: 1}, {}} {
+			r, fb := createTestRasterizer(100, 100)
+			r.camera.SetFOV(math.Pi / 3)
+			r.ClearDepth()
+			fb.Clear(RGB(0, 0, 0))
+			d.draw(r, quad(normal))
+			got[i] = fb.GetPixel(50, 50)
+		}
+		if got[1] != got[0] {
+			t.Errorf("%s: without normals got %v, want %v as with them", d.name, got[1], got[0])
+		}
+	}
+}
+
+func TestWorkersMatchSerial(t *testing.T) {
+	// A bumpy grid, so faces overlap in depth and shade differently
+	mesh := &mockMesh{}
+	const n = 12
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			fx, fy := float64(x)/n, float64(y)/n
+			mesh.vertices = append(mesh.vertices, struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				pos:    math3d.V3(fx*12-6, fy*12-6, math.Sin(fx*7)*math.Cos(fy*5)*2),
+				normal: math3d.V3(math.Cos(fx*7), math.Sin(fy*5), 1).Normalize(),
+				uv:     math3d.V2(fx, fy),
+			})
+		}
+	}
+	for y := range n {
+		for x := range n {
+			a := y*(n+1) + x
+			mesh.faces = append(mesh.faces, [3]int{a, a + n + 1, a + 1}, [3]int{a + 1, a + n + 1, a + n + 2})
+		}
+	}
+	transform := math3d.RotateX(0.4).Mul(math3d.RotateY(0.3))
+	lightDir := math3d.V3(0.3, 0.5, 1)
+	tex := NewCheckerTexture(16, 16, 2, RGB(250, 200, 100), RGB(40, 60, 120))
+
+	draws := []struct {
+		name string
+		draw func(r *Rasterizer)
+	}{
+		{"gouraud", func(r *Rasterizer) { r.DrawMeshGouraudOpt(mesh, transform, RGB(200, 180, 160), lightDir) }},
+		{"textured", func(r *Rasterizer) { r.DrawMeshTexturedOpt(mesh, transform, tex, lightDir) }},
+	}
+	for _, d := range draws {
+		t.Run(d.name, func(t *testing.T) {
+			render := func(workers int) (*Rasterizer, *Framebuffer) {
+				r, fb := createTestRasterizer(120, 90)
+				r.camera.SetFOV(math.Pi / 3)
+				r.camera.SetPosition(math3d.V3(0, 0, 20))
+				r.EnablePicking(true)
+				r.DisableBackfaceCulling = true
+				r.Workers = workers
+				r.ClearDepth()
+				fb.Clear(RGB(0, 0, 0))
+				d.draw(r)
+				return r, fb
+			}
+			serial, serialFB := render(1)
+			if serial.CullingStats.TrianglesDrawn() == 0 {
+				t.Fatal("nothing drawn")
+			}
+			for _, workers := range []int{2, 3, 7} {
+				parallel, parallelFB := render(workers)
+				for i := range serialFB.Pixels {
+					if serialFB.Pixels[i] != parallelFB.Pixels[i] {
+						t.Fatalf("%d workers: pixel %d is %v, serially %v", workers, i, parallelFB.Pixels[i], serialFB.Pixels[i])
+					}
+				}
+				for i := range serial.faceIDs {
+					if serial.faceIDs[i] != parallel.faceIDs[i] {
+						t.Fatalf("%d workers: face ID %d is %d, serially %d", workers, i, parallel.faceIDs[i], serial.faceIDs[i])
+					}
+				}
+				if serial.CullingStats != parallel.CullingStats {
+					t.Errorf("%d workers: culling stats %+v, serially %+v", workers, parallel.CullingStats, serial.CullingStats)
+				}
+				if serial.TextureStats.Samples != parallel.TextureStats.Samples {
+					t.Errorf("%d workers: %d texture samples, serially %d", workers, parallel.TextureStats.Samples, serial.TextureStats.Samples)
+				}
+			}
+		})
+	}
+}
+
 func TestDrawMeshGouraud_SmoothVsFlat(t *testing.T) {
 	// This test verifies that Gouraud shading produces different results
 	// than flat shading when normals vary across the surface
@@ -328,6 +957,137 @@ func TestDrawTriangleGouraud_BackfaceCulling(t *testing.T) {
 	}
 }
 
+func TestFrontFace(t *testing.T) {
+	// Counter-clockwise on screen when viewed from +Z
+	ccw := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{pos: math3d.V3(-5, -5, 0), normal: math3d.V3(0, 0, 1)},
+			{pos: math3d.V3(5, -5, 0), normal: math3d.V3(0, 0, 1)},
+			{pos: math3d.V3(0, 5, 0), normal: math3d.V3(0, 0, 1)},
+		},
+		faces: [][3]int{{0, 1, 2}},
+	}
+	white := RGB(255, 255, 255)
+	light := math3d.V3(0, 0, 1)
+
+	draws := []struct {
+		name string
+		draw func(r *Rasterizer)
+	}{
+		{"gouraud", func(r *Rasterizer) { r.DrawMeshGouraud(ccw, math3d.Identity(), white, light) }},
+		{"gouraud opt", func(r *Rasterizer) { r.DrawMeshGouraudOpt(ccw, math3d.Identity(), white, light) }},
+		{"textured opt", func(r *Rasterizer) {
+			r.DrawMeshTexturedOpt(ccw, math3d.Identity(), NewCheckerTexture(4, 4, 2, white, white), light)
+		}},
+		{"triangle", func(r *Rasterizer) {
+			var tri Triangle
+			for i := range 3 {
+				tri.V[i] = Vertex{Position: ccw.vertices[i].pos, Normal: ccw.vertices[i].normal, Color: white}
+			}
+			r.DrawTriangleGouraud(tri, light)
+		}},
+	}
+	tests := []struct {
+		front   FrontFace
+		disable bool
+		want    bool
+	}{
+		{FrontFaceCW, false, false},
+		{FrontFaceCCW, false, true},
+		{FrontFaceCW, true, true},
+	}
+
+	for _, d := range draws {
+		for _, tt := range tests {
+			fb := NewFramebuffer(100, 100)
+			camera := NewCamera()
+			camera.SetPosition(math3d.V3(0, 0, 20))
+			camera.LookAt(math3d.Zero3())
+			camera.SetFOV(math.Pi / 3)
+			r := NewRasterizer(camera, fb)
+			r.FrontFace = tt.front
+			r.DisableBackfaceCulling = tt.disable
+			r.ClearDepth()
+			fb.Clear(RGB(0, 0, 0))
+			d.draw(r)
+
+			if drawn := fb.GetPixel(50, 50) != RGB(0, 0, 0); drawn != tt.want {
+				t.Errorf("%s, front %d, culling disabled %v: drawn = %v, want %v",
+					d.name, tt.front, tt.disable, drawn, tt.want)
+			}
+		}
+	}
+}
+
+func TestDrawTriangle_FrustumRejection(t *testing.T) {
+	r, fb := createTestRasterizer(100, 100)
+	r.ClearDepth()
+	fb.Clear(RGB(0, 0, 0))
+	lightDir := math3d.V3(0, 0, 1)
+
+	// Far off to the side: every vertex is outside the same side plane
+	offscreen := Triangle{
+		V: [3]Vertex{
+			{Position: math3d.V3(995, -5, 0), Normal: math3d.V3(0, 0, 1), Color: RGB(255, 255, 255)},
+			{Position: math3d.V3(1000, 5, 0), Normal: math3d.V3(0, 0, 1), Color: RGB(255, 255, 255)},
+			{Position: math3d.V3(1005, -5, 0), Normal: math3d.V3(0, 0, 1), Color: RGB(255, 255, 255)},
+		},
+	}
+	r.DrawTriangleGouraud(offscreen, lightDir)
+	r.DrawTriangleGouraudOpt(offscreen, lightDir)
+
+	if r.CullingStats.TrianglesCulled != 2 {
+		t.Errorf("TrianglesCulled = %d, want 2", r.CullingStats.TrianglesCulled)
+	}
+
+	// Visible triangle must not be counted
+	r.ResetCullingStats()
+	visible := Triangle{
+		V: [3]Vertex{
+			{Position: math3d.V3(-5, -5, 0), Normal: math3d.V3(0, 0, 1), Color: RGB(255, 255, 255)},
+			{Position: math3d.V3(0, 5, 0), Normal: math3d.V3(0, 0, 1), Color: RGB(255, 255, 255)},
+			{Position: math3d.V3(5, -5, 0), Normal: math3d.V3(0, 0, 1), Color: RGB(255, 255, 255)},
+		},
+	}
+	r.DrawTriangleGouraudOpt(visible, lightDir)
+
+	if r.CullingStats.TrianglesCulled != 0 {
+		t.Errorf("visible triangle counted as culled: TrianglesCulled = %d", r.CullingStats.TrianglesCulled)
+	}
+	if r.CullingStats.TrianglesTested != 1 || r.CullingStats.TrianglesDrawn() != 1 {
+		t.Errorf("tested/drawn = %d/%d, want 1/1", r.CullingStats.TrianglesTested, r.CullingStats.TrianglesDrawn())
+	}
+
+	// Reversed winding is tested but rejected as back-facing
+	r.ResetCullingStats()
+	visible.V[1], visible.V[2] = visible.V[2], visible.V[1]
+	r.DrawTriangleGouraudOpt(visible, lightDir)
+
+	if r.CullingStats.TrianglesBackface != 1 || r.CullingStats.TrianglesDrawn() != 0 {
+		t.Errorf("backface/drawn = %d/%d, want 1/0", r.CullingStats.TrianglesBackface, r.CullingStats.TrianglesDrawn())
+	}
+
+	// Behind the camera (at z = 10) is tested and culled on every path
+	r.ResetCullingStats()
+	behind := offscreen
+	for i := range behind.V {
+		behind.V[i].Position = math3d.V3(behind.V[i].Position.X-1000, behind.V[i].Position.Y, 20)
+	}
+	tex := NewCheckerTexture(4, 4, 2, RGB(255, 255, 255), RGB(0, 0, 0))
+	r.DrawTriangleGouraud(behind, lightDir)
+	r.DrawTriangleGouraudOpt(behind, lightDir)
+	r.DrawTriangleTextured(behind, tex, lightDir)
+	r.DrawTriangleTexturedGouraud(behind, tex, lightDir)
+
+	if r.CullingStats.TrianglesTested != 4 || r.CullingStats.TrianglesCulled != 4 {
+		t.Errorf("behind camera tested/culled = %d/%d, want 4/4", r.CullingStats.TrianglesTested, r.CullingStats.TrianglesCulled)
+	}
+}
+
 func TestDrawTransformedCubeGouraud(t *testing.T) {
 	r, fb := createTestRasterizer(100, 100)
 	r.ClearDepth()
@@ -471,20 +1231,20 @@ func TestRasterizerClearDepth(t *testing.T) {
 
 	// Clear and verify
 	r.ClearDepth()
-	if r.getDepth(5, 5) != math.MaxFloat64 {
-		t.Error("ClearDepth should reset to MaxFloat64")
+	if r.getDepth(5, 5) != math.MaxFloat32 {
+		t.Error("ClearDepth should reset to MaxFloat32")
 	}
 }
 
 func TestRasterizerDepthBoundsCheck(t *testing.T) {
 	r, _ := createTestRasterizer(10, 10)
 
-	// Out of bounds should return MaxFloat64 and not panic
-	if r.getDepth(-1, 0) != math.MaxFloat64 {
-		t.Error("Out of bounds getDepth should return MaxFloat64")
+	// Out of bounds should return MaxFloat32 and not panic
+	if r.getDepth(-1, 0) != math.MaxFloat32 {
+		t.Error("Out of bounds getDepth should return MaxFloat32")
 	}
-	if r.getDepth(100, 0) != math.MaxFloat64 {
-		t.Error("Out of bounds getDepth should return MaxFloat64")
+	if r.getDepth(100, 0) != math.MaxFloat32 {
+		t.Error("Out of bounds getDepth should return MaxFloat32")
 	}
 
 	// setDepth out of bounds should not panic
@@ -584,6 +1344,172 @@ func BenchmarkDrawTriangleGouraudOpt(b *testing.B) {
 	}
 }
 
+// layeredQuads returns layers of quads covering the view of a camera at
+// the origin with a 60° field of view, drawn back to front or, if
+// frontToBack, the other way round so every layer past the first is hidden.
+func layeredQuads(layers int, frontToBack bool) *mockMesh {
+	mesh := &mockMesh{}
+	for i := range layers {
+		z := -float64(layers-i) * 0.5
+		if frontToBack {
+			z = -float64(i+1) * 0.5
+		}
+		base := len(mesh.vertices)
+		for _, corner := range []math3d.Vec2{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}} {
+			mesh.vertices = append(mesh.vertices, struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{math3d.V3(corner.X*20-10, corner.Y*20-10, z), math3d.V3(0, 0, 1), corner})
+		}
+		mesh.faces = append(mesh.faces, [3]int{base, base + 3, base + 2}, [3]int{base, base + 2, base + 1})
+	}
+	return mesh
+}
+
+func TestDepthTiles(t *testing.T) {
+	tex := NewCheckerTexture(64, 64, 8, RGB(250, 200, 100), RGB(40, 60, 120))
+	lightDir := math3d.V3(0, 0, 1)
+	render := func(frontToBack bool, workers int) (*Rasterizer, *Framebuffer) {
+		// A size that isn't a whole number of tiles
+		r, fb := createTestRasterizer(61, 43)
+		r.camera.SetFOV(math.Pi / 3)
+		r.Workers = workers
+		r.ClearDepth()
+		fb.Clear(RGB(0, 0, 0))
+		r.DrawMeshTexturedOpt(layeredQuads(4, frontToBack), math3d.Identity(), tex, lightDir)
+		return r, fb
+	}
+
+	want, wantFB := render(false, 1)
+	for _, workers := range []int{1, 3} {
+		r, fb := render(true, workers)
+		for i := range wantFB.Pixels {
+			if fb.Pixels[i] != wantFB.Pixels[i] {
+				t.Fatalf("%d workers: pixel %d is %v drawn front to back, %v back to front", workers, i, fb.Pixels[i], wantFB.Pixels[i])
+			}
+		}
+		if r.TextureStats.Samples >= want.TextureStats.Samples {
+			t.Errorf("%d workers: %d texture samples front to back, %d back to front", workers, r.TextureStats.Samples, want.TextureStats.Samples)
+		}
+
+		// Every tile's far depth is exactly its farthest pixel
+		for y := range r.height {
+			for x := range r.width {
+				tile := r.tiles.tile(x, y)
+				far := float32(-math.MaxFloat32)
+				for ty := y / depthTile * depthTile; ty < min(y/depthTile*depthTile+depthTile, r.height); ty++ {
+					for tx := x / depthTile * depthTile; tx < min(x/depthTile*depthTile+depthTile, r.width); tx++ {
+						far = max(far, r.getDepth(tx, ty))
+					}
+				}
+				if r.tiles.far[tile] != far {
+					t.Fatalf("%d workers: tile %d far depth %v, want %v", workers, tile, r.tiles.far[tile], far)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkDrawMeshTexturedOpt(b *testing.B) {
+	// A large framebuffer covered by layers of screen-filling quads, drawn
+	// back to front so every layer passes the depth test
+	r, _ := createTestRasterizer(400, 400)
+	r.camera.SetFOV(math.Pi / 3)
+	mesh := layeredQuads(8, false)
+	tex := NewCheckerTexture(256, 256, 16, ColorWhite, ColorGray)
+	lightDir := math3d.V3(0, 0, 1)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		r.ClearDepth()
+		r.DrawMeshTexturedOpt(mesh, math3d.Identity(), tex, lightDir)
+	}
+}
+
+func BenchmarkDrawMeshOccluded(b *testing.B) {
+	// The same layers drawn front to back, so all but the first are hidden
+	// and the depth tiles can skip them
+	r, _ := createTestRasterizer(400, 400)
+	r.camera.SetFOV(math.Pi / 3)
+	mesh := layeredQuads(8, true)
+	lightDir := math3d.V3(0, 0, 1)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		r.ClearDepth()
+		r.DrawMeshGouraudOpt(mesh, math3d.Identity(), ColorWhite, lightDir)
+	}
+}
+
+// uvSphere returns a sphere of radius 3 with stacks rings of slices quads,
+// wound clockwise as the loaders wind them.
+func uvSphere(stacks, slices int) *mockMesh {
+	mesh := &mockMesh{}
+	for i := range stacks + 1 {
+		lat := math.Pi * (float64(i)/float64(stacks) - 0.5)
+		for j := range slices + 1 {
+			lon := 2 * math.Pi * float64(j) / float64(slices)
+			n := math3d.V3(math.Cos(lat)*math.Sin(lon), math.Sin(lat), math.Cos(lat)*math.Cos(lon))
+			mesh.vertices = append(mesh.vertices, struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{n.Scale(3), n, math3d.V2(float64(j)/float64(slices), float64(i)/float64(stacks))})
+		}
+	}
+	for i := range stacks {
+		for j := range slices {
+			a := i*(slices+1) + j
+			c := a + slices + 1
+			mesh.faces = append(mesh.faces, [3]int{a, c, a + 1}, [3]int{a + 1, c, c + 1})
+		}
+	}
+	return mesh
+}
+
+func BenchmarkDrawMeshDense(b *testing.B) {
+	// Tens of thousands of triangles a few pixels each, where keeping the
+	// depth tiles current costs more than they save
+	r, _ := createTestRasterizer(200, 200)
+	r.camera.SetFOV(math.Pi / 3)
+	mesh := uvSphere(120, 122)
+	lightDir := math3d.V3(0, 0, 1)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		r.ClearDepth()
+		r.DrawMeshGouraudOpt(mesh, math3d.Identity(), ColorWhite, lightDir)
+	}
+}
+
+func TestDrawMeshAllocs(t *testing.T) {
+	// Steady-state frames reuse the rasterizer's scratch buffers
+	mesh := uvSphere(12, 16)
+	tex := NewCheckerTexture(16, 16, 4, RGB(255, 255, 255), RGB(0, 0, 0))
+	lightDir := math3d.V3(0, 0, 1)
+	draws := []struct {
+		name string
+		draw func(r *Rasterizer)
+	}{
+		{"gouraud", func(r *Rasterizer) { r.DrawMeshGouraudOpt(mesh, math3d.Identity(), ColorWhite, lightDir) }},
+		{"textured", func(r *Rasterizer) { r.DrawMeshTexturedOpt(mesh, math3d.Identity(), tex, lightDir) }},
+	}
+	for _, d := range draws {
+		t.Run(d.name, func(t *testing.T) {
+			r, _ := createTestRasterizer(100, 100)
+			frame := func() {
+				r.ClearDepth()
+				d.draw(r)
+			}
+			frame()
+			if allocs := testing.AllocsPerRun(5, frame); allocs != 0 {
+				t.Errorf("allocs per frame = %v, want 0", allocs)
+			}
+		})
+	}
+}
+
 // BenchmarkDrawMeshGouraudOpt benchmarks the optimized mesh Gouraud renderer.
 func BenchmarkDrawMeshGouraudOpt(b *testing.B) {
 	r, _ := createTestRasterizer(200, 200)
@@ -623,6 +1549,7 @@ func BenchmarkDrawMeshGouraudOpt(b *testing.B) {
 	color := RGB(200, 100, 50)
 	lightDir := math3d.V3(0, 0, 1)
 
+	b.ReportAllocs()
 	for b.Loop() {
 		r.ClearDepth()
 		r.DrawMeshGouraudOpt(mesh, transform, color, lightDir)
@@ -656,3 +1583,248 @@ func BenchmarkGouraudComparison(b *testing.B) {
 		}
 	})
 }
+
+func TestSetFramebuffer(t *testing.T) {
+	mesh := layeredQuads(2, false)
+	tex := NewCheckerTexture(16, 16, 4, ColorWhite, ColorGray)
+	lightDir := math3d.V3(0, 0, 1)
+	draw := func(r *Rasterizer, fb *Framebuffer) {
+		fb.Clear(ColorBlack)
+		r.ClearDepth()
+		r.DrawMeshTexturedOpt(mesh, math3d.Identity(), tex, lightDir)
+	}
+
+	r, fb := createTestRasterizer(80, 60)
+	r.camera.SetFOV(math.Pi / 3)
+	r.EnablePicking(true)
+	draw(r, fb)
+
+	// Shrink, then grow back within the original size
+	for _, size := range [][2]int{{40, 30}, {64, 50}} {
+		fb.Resize(size[0], size[1])
+		r.SetFramebuffer(fb)
+		r.camera.SetAspectRatio(float64(size[0]) / float64(size[1]))
+		draw(r, fb)
+
+		fresh, freshFB := createTestRasterizer(size[0], size[1])
+		fresh.camera.SetFOV(math.Pi / 3)
+		draw(fresh, freshFB)
+		for i := range freshFB.Pixels {
+			if fb.Pixels[i] != freshFB.Pixels[i] {
+				t.Fatalf("%dx%d: pixel %d is %v, %v in a new rasterizer", size[0], size[1], i, fb.Pixels[i], freshFB.Pixels[i])
+			}
+		}
+		if got := r.PickFace(size[0]/2, size[1]/2); got < 0 {
+			t.Errorf("%dx%d: no face picked after resizing", size[0], size[1])
+		}
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		fb.Resize(50, 40)
+		r.SetFramebuffer(fb)
+		fb.Resize(80, 60)
+		r.SetFramebuffer(fb)
+	})
+	if allocs != 0 {
+		t.Errorf("resizing within the original size allocated %v times", allocs)
+	}
+}
+
+func TestTextureStats(t *testing.T) {
+	r, fb := createTestRasterizer(100, 100)
+	r.ClearDepth()
+	fb.Clear(RGB(0, 0, 0))
+
+	tex := NewCheckerTexture(8, 4, 2, RGB(255, 255, 255), RGB(100, 100, 100))
+	tex.FilterMode = FilterBilinear
+
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+			{math3d.V3(5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+			{math3d.V3(5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+			{math3d.V3(-5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+		},
+		faces: [][3]int{{0, 3, 2}, {0, 2, 1}},
+	}
+
+	r.DrawMeshTexturedOpt(mesh, math3d.Identity(), tex, math3d.V3(0, 0, 1))
+
+	st := r.TextureStats
+	if st.Samples == 0 {
+		t.Fatal("expected texture samples to be counted")
+	}
+	if st.Bilinear != st.Samples || st.Nearest != 0 {
+		t.Errorf("filter counts = %d nearest / %d bilinear, want all %d bilinear", st.Nearest, st.Bilinear, st.Samples)
+	}
+	if st.Width != 8 || st.Height != 4 || st.Filter != FilterBilinear {
+		t.Errorf("texture info = %dx%d filter %d, want 8x4 bilinear", st.Width, st.Height, st.Filter)
+	}
+
+	r.ResetTextureStats()
+	if r.TextureStats.Samples != 0 {
+		t.Error("ResetTextureStats should clear counters")
+	}
+}
+
+func TestTexturedNonFiniteUVs(t *testing.T) {
+	white := RGB(255, 255, 255)
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		r, fb := createTestRasterizer(20, 20)
+		r.ClearDepth()
+		mesh := &mockMesh{
+			vertices: []struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				{math3d.V3(-5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(bad, 0)},
+				{math3d.V3(5, -5, 0), math3d.V3(0, 0, 1), math3d.V2(1, bad)},
+				{math3d.V3(5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+				{math3d.V3(-5, 5, 0), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+			},
+			faces: [][3]int{{0, 3, 2}, {0, 2, 1}},
+		}
+
+		// A nearest, repeating texture takes the fast sampling path
+		r.DrawMeshTexturedOpt(mesh, math3d.Identity(), NewCheckerTexture(4, 4, 2, white, white), math3d.V3(0, 0, 1))
+		if fb.GetPixel(10, 10) == (Color{}) {
+			t.Errorf("UV %v: quad not drawn", bad)
+		}
+	}
+}
+
+// mockBoxMesh adds a bounding box to mockMesh.
+type mockBoxMesh struct {
+	mockMesh
+	min, max math3d.Vec3
+}
+
+func (m *mockBoxMesh) GetBounds() (min, max math3d.Vec3) { return m.min, m.max }
+
+// mockSphereMesh adds a bounding sphere to mockMesh.
+type mockSphereMesh struct {
+	mockMesh
+	center math3d.Vec3
+	radius float64
+}
+
+func (m *mockSphereMesh) BoundingSphere() (math3d.Vec3, float64) { return m.center, m.radius }
+
+func TestTryFrustumCullSphere(t *testing.T) {
+	tests := []struct {
+		name          string
+		mesh          MeshRenderer
+		transform     math3d.Mat4
+		wantCulled    bool
+		wantTested    int
+		wantSphereCul int
+	}{
+		{"no bounds", &mockMesh{}, math3d.Identity(), false, 0, 0},
+		{"sphere visible", &mockSphereMesh{radius: 1}, math3d.Identity(), false, 1, 0},
+		{"sphere off to the side", &mockSphereMesh{radius: 1}, math3d.Translate(math3d.V3(100, 0, 0)), true, 1, 1},
+		{"sphere scaled into view", &mockSphereMesh{center: math3d.V3(50, 0, 0), radius: 1}, math3d.Scale(math3d.V3(0.01, 0.01, 0.01)), false, 1, 0},
+		{"sphere scaled to reach view", &mockSphereMesh{center: math3d.V3(30, 0, 0), radius: 20}, math3d.Scale(math3d.V3(2, 2, 2)), false, 1, 0},
+		{"box off to the side", &mockBoxMesh{min: math3d.V3(-1, -1, -1), max: math3d.V3(1, 1, 1)}, math3d.Translate(math3d.V3(100, 0, 0)), true, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			camera := NewCamera()
+			camera.SetPosition(math3d.V3(0, 0, 10))
+			camera.LookAt(math3d.Zero3())
+			camera.SetFOV(math.Pi / 3)
+			r := NewRasterizer(camera, NewFramebuffer(40, 40))
+
+			if got := r.tryFrustumCull(tt.mesh, tt.transform); got != tt.wantCulled {
+				t.Errorf("tryFrustumCull() = %v, want %v", got, tt.wantCulled)
+			}
+			if s := r.CullingStats; s.MeshesTested != tt.wantTested || s.MeshesSphereCulled != tt.wantSphereCul {
+				t.Errorf("stats = %+v, want %d tested, %d sphere culled", s, tt.wantTested, tt.wantSphereCul)
+			}
+		})
+	}
+}
+
+// mockOcclusionMesh gives every vertex the same occlusion map coordinates.
+type mockOcclusionMesh struct {
+	mockMesh
+	aouv math3d.Vec2
+}
+
+func (m *mockOcclusionMesh) GetOcclusionUV(int) math3d.Vec2 { return m.aouv }
+
+func TestOcclusion(t *testing.T) {
+	// Left half unoccluded, right half fully occluded
+	ao := NewTexture(4, 1)
+	for x := range 4 {
+		if x < 2 {
+			ao.SetPixel(x, 0, RGB(255, 255, 255))
+		} else {
+			ao.SetPixel(x, 0, RGB(0, 0, 0))
+		}
+	}
+	gray := NewTexture(4, 1)
+	for x := range 4 {
+		gray.SetPixel(x, 0, RGB(128, 128, 128))
+	}
+
+	tri := mockMesh{faces: [][3]int{{0, 1, 2}}}
+	for _, p := range []math3d.Vec3{math3d.V3(-5, -5, 0), math3d.V3(5, -5, 0), math3d.V3(0, 5, 0)} {
+		tri.vertices = append(tri.vertices, struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{pos: p, normal: math3d.V3(0, 0, 1), uv: math3d.V2(0.125, 0.5)})
+	}
+	separate := &mockOcclusionMesh{mockMesh: tri, aouv: math3d.V2(0.875, 0.5)}
+
+	white := RGB(255, 255, 255)
+	// Light from behind leaves only the ambient term
+	light := math3d.V3(0, 0, -1)
+
+	tests := []struct {
+		name     string
+		mesh     MeshRenderer
+		tex      *Texture
+		strength float64
+		want     uint8 // Red channel at the center
+	}{
+		{"no map", &tri, nil, 1, 76},
+		{"unoccluded", &tri, ao, 1, 76},
+		{"half gray", &tri, gray, 1, 38},
+		{"zero strength", &tri, gray, 0, 76},
+		{"half strength", &tri, gray, 0.5, 57},
+		{"occlusion UVs", separate, ao, 1, 0},
+	}
+
+	for _, tt := range tests {
+		draws := map[string]func(r *Rasterizer){
+			"gouraud opt": func(r *Rasterizer) { r.DrawMeshGouraudOpt(tt.mesh, math3d.Identity(), white, light) },
+			"textured opt": func(r *Rasterizer) {
+				r.DrawMeshTexturedOpt(tt.mesh, math3d.Identity(), NewCheckerTexture(4, 4, 2, white, white), light)
+			},
+		}
+		for name, draw := range draws {
+			fb := NewFramebuffer(100, 100)
+			camera := NewCamera()
+			camera.SetPosition(math3d.V3(0, 0, 20))
+			camera.LookAt(math3d.Zero3())
+			camera.SetFOV(math.Pi / 3)
+			r := NewRasterizer(camera, fb)
+			r.DisableBackfaceCulling = true
+			r.SetOcclusion(tt.tex, tt.strength)
+			r.ClearDepth()
+			fb.Clear(RGB(255, 0, 255))
+			draw(r)
+
+			if got := fb.GetPixel(50, 50).R; absInt(int(got)-int(tt.want)) > 1 {
+				t.Errorf("%s, %s: red = %d, want %d", tt.name, name, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package render
+
+import (
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// Scene draws a mesh into a framebuffer: the mesh with its texture and
+// pose, the light on it, and the camera viewing it. It has no terminal or
+// command-line ties, so programs embedding trophy can render with it
+// directly and show the framebuffer however they like, e.g. with a
+// TerminalRenderer.
+type Scene struct {
+	Mesh         MeshRenderer
+	Cap          MeshRenderer // Drawn after the mesh in Palette.Cap, e.g. a section's cap; nil for none
+	Texture      *Texture     // Drawn on the mesh; nil shades it with Color
+	VertexColors []Color      // Per-vertex colors to shade the mesh with instead, e.g. a heatmap; nil for none
+	Occlusion    *Texture     // Ambient occlusion map; nil for none
+	AOStrength   float64      // How strongly Occlusion darkens, 0-1
+	Color        Color        // Base color of an untextured mesh
+	Transform    math3d.Mat4  // Model transform, e.g. the model's rotation
+	Camera       *Camera
+	LightDir     math3d.Vec3 // Direction toward the light
+	Background   Color       // Fill behind the mesh; transparent leaves the terminal's own background showing
+	Palette      Palette     // Wireframe, cap and material colors
+
+	Wireframe      bool               // Draw the mesh's edges instead of shading its faces
+	WireColor      WireframeColorMode // How Wireframe colors the edges
+	UVLayout       bool               // Draw the mesh's texture layout instead, over Texture or filled with Color
+	CullBackfaces  bool               // Skip faces turned away from the camera; most scans and STLs need both sides
+	FrontFace      FrontFace          // Winding of faces toward the camera; see Rasterizer.FrontFace
+	FlatShading    bool               // Light each triangle by its face normal
+	AntialiasLines bool               // See Rasterizer.AntialiasLines
+	LineWidth      int                // See Rasterizer.LineWidth
+	Picking        bool               // Record the triangle at each pixel for Rasterizer().PickFace
+	Workers        int                // Goroutines to rasterize with; see Rasterizer.Workers
+	Deterministic  bool               // Render identically on every run and platform; see Rasterizer.Deterministic
+
+	// CellAspect is the height over width of the terminal cells the frame
+	// is shown in, two pixels to a cell, so the camera can keep shapes
+	// true; 0 means DefaultCellAspect, for square pixels
+	CellAspect float64
+
+	rasterizer *Rasterizer
+}
+
+// sceneMargin leaves room around a mesh framed by NewScene.
+const sceneMargin = 1.45
+
+// NewScene returns a scene of mesh lit from above and to the right, with
+// the camera looking down -Z at the mesh, far enough back to frame it if
+// it reports its bounds (as *models.Mesh does).
+func NewScene(mesh MeshRenderer) *Scene {
+	camera := NewCamera()
+	camera.SetClipPlanes(0.1, 100)
+	camera.SetPosition(math3d.V3(0, 0, 1))
+	camera.LookAt(math3d.Zero3())
+	if bounded, ok := mesh.(BoundedMeshRenderer); ok {
+		// Clip planes scaled to the distance keep any size of mesh in range
+		distance := camera.FrameBounds(NewAABB(bounded.GetBounds()), sceneMargin)
+		camera.SetClipPlanes(distance/100, distance*10)
+	}
+
+	return &Scene{
+		Mesh:       mesh,
+		Color:      RGB(200, 200, 200),
+		Transform:  math3d.Identity(),
+		Camera:     camera,
+		LightDir:   math3d.V3(0.5, 1, 0.3).Normalize(),
+		Background: RGB(30, 30, 40),
+		Palette:    DefaultPalette,
+	}
+}
+
+// RenderTo draws the scene into fb, filling it edge to edge. The camera's
+// aspect ratio is set to match, allowing for CellAspect.
+func (s *Scene) RenderTo(fb *Framebuffer) {
+	r := s.rasterizer
+	switch {
+	case r == nil || r.camera != s.Camera:
+		r = NewRasterizer(s.Camera, fb)
+		s.rasterizer = r
+	case r.fb != fb || r.width != fb.Width || r.height != fb.Height:
+		r.SetFramebuffer(fb)
+	}
+	s.Camera.SetAspectRatio(ViewAspect(fb.Width, fb.Height, s.CellAspect))
+	r.BeginFrame()
+	r.DisableBackfaceCulling = !s.CullBackfaces
+	r.FrontFace = s.FrontFace
+	r.FlatShading = s.FlatShading
+	r.AntialiasLines = s.AntialiasLines
+	r.LineWidth = s.LineWidth
+	r.Palette = s.Palette
+	r.Workers = s.Workers
+	r.Deterministic = s.Deterministic
+	r.SetOcclusion(s.Occlusion, s.AOStrength)
+	r.EnablePicking(s.Picking)
+
+	fb.Clear(s.Background)
+	r.ClearDepth()
+	r.ResetCullingStats()
+	r.ResetTextureStats()
+
+	if s.UVLayout {
+		r.DrawMeshUV(s.Mesh, s.Texture, s.Color, s.Palette.Wireframe)
+		return
+	}
+	switch {
+	case s.Wireframe:
+		r.DrawMeshWireframeColored(s.Mesh, s.Transform, s.Palette.Wireframe, s.WireColor)
+	case s.VertexColors != nil:
+		r.DrawMeshVertexColorsOpt(s.Mesh, s.Transform, s.VertexColors, s.LightDir)
+	case s.Texture != nil:
+		r.DrawMeshTexturedOpt(s.Mesh, s.Transform, s.Texture, s.LightDir)
+	default:
+		r.DrawMeshGouraudOpt(s.Mesh, s.Transform, s.Color, s.LightDir)
+	}
+
+	// The cap's faces are picked after the mesh's
+	if s.Cap != nil {
+		r.FaceBase = s.Mesh.TriangleCount()
+		if s.Wireframe {
+			r.DrawMeshWireframeColored(s.Cap, s.Transform, s.Palette.Cap, s.WireColor)
+		} else {
+			r.DrawMeshGouraudOpt(s.Cap, s.Transform, s.Palette.Cap, s.LightDir)
+		}
+		r.FaceBase = 0
+	}
+
+	// Line and point primitives have no faces to shade
+	if lines, ok := s.Mesh.(LineMeshRenderer); ok {
+		color := s.Color
+		if s.Wireframe {
+			color = s.Palette.Wireframe
+		}
+		r.DrawMeshLines(lines, s.Transform, color)
+	}
+}
+
+// Rasterizer returns the rasterizer the last RenderTo drew with, for its
+// stats and face picking, or nil before the first.
+func (s *Scene) Rasterizer() *Rasterizer {
+	return s.rasterizer
+}
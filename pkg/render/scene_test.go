@@ -0,0 +1,282 @@
+package render
+
+import (
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestScene(t *testing.T) {
+	// A quad much larger than the camera's default view, so it only fits if
+	// NewScene frames its bounds
+	mesh := &mockBoxMesh{
+		mockMesh: mockMesh{
+			vertices: []struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				{math3d.V3(-50, -50, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+				{math3d.V3(50, -50, 0), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+				{math3d.V3(50, 50, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+				{math3d.V3(-50, 50, 0), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+			},
+			faces: [][3]int{{0, 3, 2}, {0, 2, 1}},
+		},
+		min: math3d.V3(-50, -50, 0),
+		max: math3d.V3(50, 50, 0),
+	}
+	red := RGB(255, 0, 0)
+
+	tests := []struct {
+		name   string
+		setup  func(s *Scene)
+		center func(c Color) bool
+	}{
+		{"shaded", func(s *Scene) { s.Color = red }, func(c Color) bool { return c.R > 0 && c.G == 0 && c.B == 0 }},
+		{"textured", func(s *Scene) { s.Texture = NewCheckerTexture(2, 2, 1, RGB(0, 0, 255), RGB(0, 0, 255)) },
+			func(c Color) bool { return c.B > 0 && c.R == 0 && c.G == 0 }},
+		{"transparent background", func(s *Scene) {
+			// Even a texture with no alpha draws opaque over no background
+			s.Background = Color{}
+			s.Texture = NewCheckerTexture(2, 2, 1, RGBA(0, 0, 255, 0), RGBA(0, 0, 255, 0))
+		}, func(c Color) bool { return c.B > 0 && c.A == 255 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scene := NewScene(mesh)
+			tt.setup(scene)
+			fb := NewFramebuffer(80, 48)
+			scene.RenderTo(fb)
+
+			if c := fb.GetPixel(40, 24); !tt.center(c) {
+				t.Errorf("center pixel %v", c)
+			}
+			if c := fb.GetPixel(0, 0); c != scene.Background {
+				t.Errorf("corner pixel %v, want background %v", c, scene.Background)
+			}
+			if drawn := scene.Rasterizer().CullingStats.TrianglesDrawn(); drawn != 2 {
+				t.Errorf("drew %d triangles, want 2", drawn)
+			}
+
+			// A new framebuffer size redraws edge to edge
+			wide := NewFramebuffer(160, 48)
+			scene.RenderTo(wide)
+			if c := wide.GetPixel(80, 24); !tt.center(c) {
+				t.Errorf("center pixel after resize %v", c)
+			}
+		})
+	}
+}
+
+func TestSceneModes(t *testing.T) {
+	mesh := tiltedQuad()
+
+	// A smaller quad just in front of the mesh, standing in for a cap
+	capMesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{math3d.V3(-0.3, -0.3, 0.1), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+			{math3d.V3(0.3, -0.3, 0.1), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+			{math3d.V3(0.3, 0.3, 0.1), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+			{math3d.V3(-0.3, 0.3, 0.1), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+		},
+		faces: [][3]int{{0, 3, 2}, {0, 2, 1}},
+	}
+	green := RGB(0, 255, 0)
+
+	tests := []struct {
+		name   string
+		setup  func(s *Scene)
+		center func(c Color) bool
+		face   int  // Picked at the center, or its other triangle; -1 to skip
+		edges  bool // Whether the center check holds anywhere, not just at the center
+	}{
+		{"vertex colors", func(s *Scene) { s.VertexColors = []Color{green, green, green, green} },
+			func(c Color) bool { return c.G > 0 && c.R == 0 && c.B == 0 }, 0, false},
+		{"cap", func(s *Scene) {
+			s.Cap = capMesh
+			s.Palette.Cap = green
+		}, func(c Color) bool { return c.G > 0 && c.R == 0 && c.B == 0 }, 2, false},
+		{"wireframe cap", func(s *Scene) {
+			s.Wireframe = true
+			s.Cap = capMesh
+			s.Palette.Wireframe = RGB(255, 0, 0)
+			s.Palette.Cap = green
+		}, func(c Color) bool { return c == green }, -1, true},
+		{"uv layout", func(s *Scene) {
+			s.UVLayout = true
+			s.Color = green
+		}, func(c Color) bool { return c.G > 0 && c.R == 0 && c.B == 0 }, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scene := NewScene(mesh)
+			scene.Picking = true
+			tt.setup(scene)
+			fb := NewFramebuffer(60, 60)
+			scene.RenderTo(fb)
+
+			if tt.edges {
+				if !slices.ContainsFunc(fb.Pixels, tt.center) {
+					t.Error("no pixel drawn in the expected color")
+				}
+			} else if c := fb.GetPixel(30, 30); !tt.center(c) {
+				t.Errorf("center pixel %v", c)
+			}
+			if tt.face >= 0 {
+				if face := scene.Rasterizer().PickFace(30, 30); face != tt.face && face != tt.face+1 {
+					t.Errorf("picked face %d, want %d or %d", face, tt.face, tt.face+1)
+				}
+			}
+		})
+	}
+}
+
+func TestSceneCellAspect(t *testing.T) {
+	// A square, seen face on
+	mesh := &mockBoxMesh{
+		mockMesh: mockMesh{
+			vertices: []struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				{math3d.V3(-1, -1, 0), math3d.V3(0, 0, 1), math3d.V2(0, 0)},
+				{math3d.V3(1, -1, 0), math3d.V3(0, 0, 1), math3d.V2(1, 0)},
+				{math3d.V3(1, 1, 0), math3d.V3(0, 0, 1), math3d.V2(1, 1)},
+				{math3d.V3(-1, 1, 0), math3d.V3(0, 0, 1), math3d.V2(0, 1)},
+			},
+			faces: [][3]int{{0, 3, 2}, {0, 2, 1}},
+		},
+		min: math3d.V3(-1, -1, 0),
+		max: math3d.V3(1, 1, 0),
+	}
+
+	// In cells cellAspect times as tall as wide, a pixel is cellAspect/2
+	// times as tall as wide, so the square should be that much wider in
+	// pixels than it is tall
+	tests := []struct {
+		cellAspect float64
+		want       float64
+	}{
+		{0, 1}, // DefaultCellAspect
+		{2, 1},
+		{2.5, 1.25},
+		{4, 2},
+	}
+	for _, tt := range tests {
+		scene := NewScene(mesh)
+		scene.CellAspect = tt.cellAspect
+		fb := NewFramebuffer(120, 120)
+		scene.RenderTo(fb)
+
+		var across, down int
+		for i := range 120 {
+			if fb.GetPixel(i, 60) != scene.Background {
+				across++
+			}
+			if fb.GetPixel(60, i) != scene.Background {
+				down++
+			}
+		}
+		if got := float64(across) / float64(down); math.Abs(got-tt.want) > 0.05 {
+			t.Errorf("cell aspect %g: square is %d by %d pixels, ratio %.2f, want %.2f", tt.cellAspect, across, down, got, tt.want)
+		}
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// tiltedQuad returns a quad whose normals tilt toward the light, so its
+// shading varies across it.
+func tiltedQuad() *mockBoxMesh {
+	return &mockBoxMesh{
+		mockMesh: mockMesh{
+			vertices: []struct {
+				pos    math3d.Vec3
+				normal math3d.Vec3
+				uv     math3d.Vec2
+			}{
+				{math3d.V3(-1, -1, 0), math3d.V3(-0.5, -0.5, 1).Normalize(), math3d.V2(0, 0)},
+				{math3d.V3(1, -1, 0), math3d.V3(0.5, -0.5, 1).Normalize(), math3d.V2(1, 0)},
+				{math3d.V3(1, 1, 0), math3d.V3(0.5, 0.5, 1).Normalize(), math3d.V2(1, 1)},
+				{math3d.V3(-1, 1, 0), math3d.V3(-0.5, 0.5, 1).Normalize(), math3d.V2(0, 1)},
+			},
+			faces: [][3]int{{0, 3, 2}, {0, 2, 1}},
+		},
+		min: math3d.V3(-1, -1, 0),
+		max: math3d.V3(1, 1, 0),
+	}
+}
+
+func TestSceneGolden(t *testing.T) {
+	mesh := tiltedQuad()
+
+	tests := []struct {
+		name  string
+		setup func(s *Scene)
+	}{
+		{"shaded", func(s *Scene) {}},
+		{"textured", func(s *Scene) { s.Texture = NewCheckerTexture(4, 4, 2, RGB(220, 60, 60), RGB(60, 60, 220)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scene := NewScene(mesh)
+			scene.Deterministic = true
+			scene.Transform = math3d.RotateY(0.4).Mul(math3d.RotateX(-0.3))
+			tt.setup(scene)
+			fb := NewFramebuffer(24, 16)
+			scene.RenderTo(fb)
+			got := fb.ANSI()
+
+			path := filepath.Join("testdata", "scene_"+tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("frame differs from %s:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestSceneParallel(t *testing.T) {
+	mesh := tiltedQuad()
+
+	// A real parallel render truncates where the deterministic one rounds,
+	// once lighting the vertices and once blending them, so it may be off
+	// by 2 but no more
+	render := func(workers int, deterministic bool) *Framebuffer {
+		scene := NewScene(mesh)
+		scene.Workers = workers
+		scene.Deterministic = deterministic
+		scene.Transform = math3d.RotateY(0.4).Mul(math3d.RotateX(-0.3))
+		fb := NewFramebuffer(48, 32)
+		scene.RenderTo(fb)
+		return fb
+	}
+	want := render(0, true)
+	got := render(4, false)
+	diff := func(a, b uint8) int { return max(int(a)-int(b), int(b)-int(a)) }
+	for i, w := range want.Pixels {
+		g := got.Pixels[i]
+		if diff(g.R, w.R) > 2 || diff(g.G, w.G) > 2 || diff(g.B, w.B) > 2 || g.A != w.A {
+			t.Fatalf("pixel %d is %v in parallel, %v serially", i, g, w)
+		}
+	}
+}
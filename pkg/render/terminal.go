@@ -1,23 +1,102 @@
 package render
 
 import (
+	"fmt"
 	"image/color"
+	"math"
+	"strings"
 
 	uv "github.com/charmbracelet/ultraviolet"
 )
 
 // TerminalRenderer converts a Framebuffer to Ultraviolet cells.
-// It uses half-block characters (▀) to achieve 2x vertical resolution.
+// It uses half-block characters (▀) to achieve 2x vertical resolution;
+// SetGlyph picks another character for fonts that draw ▀ poorly.
 type TerminalRenderer struct {
 	term   *uv.Terminal
-	width  int // Terminal columns
-	height int // Terminal rows
+	screen uv.Screen // Cell target; the terminal's screen outside of tests
+	width  int       // Terminal columns
+	height int       // Terminal rows
+
+	prev  []cellColors // Previous frame's colors per cell, used by RenderDiff
+	tone  *[256]uint8  // Per-channel display curve from SetTone, nil for none
+	glyph Glyph        // How each cell shows its two pixels
+}
+
+// Glyph is the character a TerminalRenderer draws each cell with, and so
+// how the cell shows its top and bottom pixels.
+type Glyph int
+
+const (
+	GlyphUpperHalf Glyph = iota // ▀ in the top pixel's color over the bottom's
+	GlyphLowerHalf              // ▄ in the bottom pixel's color under the top's, for fonts with a seam below ▀
+	GlyphFullBlock              // █ in the two pixels' average, halving the vertical resolution but drawing no edge at all
+)
+
+// glyphNames maps ParseGlyph names to glyphs.
+var glyphNames = map[string]Glyph{
+	"upper": GlyphUpperHalf,
+	"lower": GlyphLowerHalf,
+	"full":  GlyphFullBlock,
+}
+
+// ParseGlyph parses a glyph name: upper, lower, or full.
+func ParseGlyph(name string) (Glyph, error) {
+	g, ok := glyphNames[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown glyph %q (use upper, lower, or full)", name)
+	}
+	return g, nil
+}
+
+// cell returns the character and colors that show a cell's top and
+// bottom pixels with g.
+func (g Glyph) cell(top, bot color.RGBA) (content string, fg, bg color.RGBA) {
+	switch g {
+	case GlyphLowerHalf:
+		return "▄", bot, top
+	case GlyphFullBlock:
+		c := averageColor(top, bot)
+		if c.A == 0 {
+			// A block would show in the default foreground
+			return " ", c, c
+		}
+		return "█", c, c
+	default:
+		return "▀", top, bot
+	}
+}
+
+// averageColor mixes a and b evenly, or returns the other if one is
+// transparent.
+func averageColor(a, b color.RGBA) color.RGBA {
+	switch {
+	case a.A == 0:
+		return b
+	case b.A == 0:
+		return a
+	}
+	return color.RGBA{
+		uint8((int(a.R) + int(b.R) + 1) / 2),
+		uint8((int(a.G) + int(b.G) + 1) / 2),
+		uint8((int(a.B) + int(b.B) + 1) / 2),
+		uint8((int(a.A) + int(b.A) + 1) / 2),
+	}
+}
+
+// cellColors is the top/bottom pixel pair drawn into one half-block cell.
+// The zero value (set=false) never matches a drawn cell, so it marks the
+// cell for redraw.
+type cellColors struct {
+	top, bot color.RGBA
+	set      bool
 }
 
 // NewTerminalRenderer creates a renderer for the given terminal.
 func NewTerminalRenderer(term *uv.Terminal, width, height int) *TerminalRenderer {
 	return &TerminalRenderer{
 		term:   term,
+		screen: term.Screen(),
 		width:  width,
 		height: height,
 	}
@@ -26,28 +105,160 @@ func NewTerminalRenderer(term *uv.Terminal, width, height int) *TerminalRenderer
 // Render converts the framebuffer to terminal cells and displays them.
 // The framebuffer height should be 2x the terminal height.
 func (r *TerminalRenderer) Render(fb *Framebuffer) {
-	// Each terminal row represents 2 framebuffer rows
-	// We use ▀ (upper half block) with fg=top color and bg=bottom color
+	r.Invalidate()
+	r.RenderDiff(fb)
+}
 
-	for row := 0; row < r.height; row++ {
-		topY := row * 2
-		botY := topY + 1
+// RenderDiff is like Render but only updates cells whose colors changed
+// since the previous Render or RenderDiff call.
+func (r *TerminalRenderer) RenderDiff(fb *Framebuffer) {
+	// Each terminal row represents 2 framebuffer rows, drawn with the glyph
+	// set by SetGlyph: by default ▀ (upper half block) with fg=top color
+	// and bg=bottom color
+	if len(r.prev) != r.width*r.height {
+		r.prev = resized(r.prev, r.width*r.height)
+		r.Invalidate()
+	}
 
+	for row := 0; row < r.height; row++ {
 		for col := 0; col < r.width && col < fb.Width; col++ {
-			topColor := fb.GetPixel(col, topY)
-			botColor := fb.GetPixel(col, botY)
+			top, bot := fb.cellPixels(col, row)
+			cc := cellColors{
+				top: visibleColor(top),
+				bot: visibleColor(bot),
+				set: true,
+			}
+			idx := row*r.width + col
+			if r.prev[idx] == cc {
+				continue
+			}
+			r.prev[idx] = cc
 
+			content, fg, bg := r.glyph.cell(r.applyTone(cc.top), r.applyTone(cc.bot))
 			cell := &uv.Cell{
-				Content: "▀",
+				Content: content,
 				Width:   1,
 				Style: uv.Style{
-					Fg: rgbaToColor(topColor),
-					Bg: rgbaToColor(botColor),
+					Fg: rgbaToColor(fg),
+					Bg: rgbaToColor(bg),
 				},
 			}
-			r.term.SetCell(col, row, cell)
+			r.screen.SetCell(col, row, cell)
+		}
+	}
+}
+
+// Resize changes the terminal size the renderer draws to. The next
+// RenderDiff redraws every cell.
+func (r *TerminalRenderer) Resize(width, height int) {
+	r.width, r.height = width, height
+	r.prev = resized(r.prev, width*height)
+	r.Invalidate()
+}
+
+// RenderString returns the frame Render would draw as text: one line per
+// terminal row of SetGlyph's glyphs colored with 24-bit escape codes,
+// through the SetTone curve. It leaves the terminal untouched, so frames can be
+// captured or compared in tests; term may be nil if this is all the
+// renderer is used for. Framebuffer.ASCII gives a plain text version.
+func (r *TerminalRenderer) RenderString(fb *Framebuffer) string {
+	var sb strings.Builder
+	writeANSI(&sb, fb, min(r.width, fb.Width), r.height, r.applyTone, r.glyph)
+	return sb.String()
+}
+
+// writeANSI writes cols by rows cells of fb to sb, each showing its top
+// and bottom pixels with glyph, with each color mapped through tone if
+// it's non-nil. Every line ends by resetting its colors.
+func writeANSI(sb *strings.Builder, fb *Framebuffer, cols, rows int, tone func(color.RGBA) color.RGBA, glyph Glyph) {
+	for row := range rows {
+		var prev cellColors // Foreground and background last written
+		for col := range cols {
+			top, bot := fb.cellPixels(col, row)
+			top, bot = visibleColor(top), visibleColor(bot)
+			if tone != nil {
+				top, bot = tone(top), tone(bot)
+			}
+			content, fg, bg := glyph.cell(top, bot)
+
+			// Only switch colors where they change along the line
+			if fg != prev.top || !prev.set {
+				writeSGR(sb, 38, fg)
+			}
+			if bg != prev.bot || !prev.set {
+				writeSGR(sb, 48, bg)
+			}
+			prev = cellColors{top: fg, bot: bg, set: true}
+			sb.WriteString(content)
 		}
+		sb.WriteString("\x1b[0m\n")
+	}
+}
+
+// writeSGR writes the escape code setting the foreground (layer 38) or
+// background (48) to c, or to the default if c is transparent.
+func writeSGR(sb *strings.Builder, layer int, c color.RGBA) {
+	if c.A == 0 {
+		fmt.Fprintf(sb, "\x1b[%dm", layer+1)
+		return
+	}
+	fmt.Fprintf(sb, "\x1b[%d;2;%d;%d;%dm", layer, c.R, c.G, c.B)
+}
+
+// SetTone sets the display curve applied as framebuffer colors become
+// cells: each channel c, scaled to [0, 1], is shown as brightness*c^(1/gamma).
+// Gamma above 1 lifts dark tones and brightness scales everything; 1, 1
+// shows colors unchanged. Rendering itself is unaffected.
+func (r *TerminalRenderer) SetTone(gamma, brightness float64) {
+	r.Invalidate()
+	if gamma == 1 && brightness == 1 {
+		r.tone = nil
+		return
+	}
+	r.tone = new([256]uint8)
+	for i := range r.tone {
+		v := brightness * math.Pow(float64(i)/255, 1/gamma)
+		r.tone[i] = uint8(math.Round(255 * math.Min(math.Max(v, 0), 1)))
+	}
+}
+
+// applyTone maps c's color channels through the SetTone curve.
+func (r *TerminalRenderer) applyTone(c color.RGBA) color.RGBA {
+	if r.tone == nil || c.A == 0 {
+		return c
 	}
+	return color.RGBA{r.tone[c.R], r.tone[c.G], r.tone[c.B], c.A}
+}
+
+// SetGlyph sets the character each cell is drawn with, for terminals or
+// fonts that show the default ▀ with gaps or seams.
+func (r *TerminalRenderer) SetGlyph(g Glyph) {
+	r.Invalidate()
+	r.glyph = g
+}
+
+// Invalidate forces the next RenderDiff to redraw every cell.
+// Call this after anything else writes to the terminal buffer.
+func (r *TerminalRenderer) Invalidate() {
+	clear(r.prev)
+}
+
+// InvalidateRow forces the next RenderDiff to redraw one terminal row,
+// e.g. after an overlay has been drawn over it.
+func (r *TerminalRenderer) InvalidateRow(row int) {
+	if row < 0 || row >= r.height || len(r.prev) != r.width*r.height {
+		return
+	}
+	clear(r.prev[row*r.width : (row+1)*r.width])
+}
+
+// visibleColor collapses all fully transparent colors to one value,
+// since they render identically.
+func visibleColor(c color.RGBA) color.RGBA {
+	if c.A == 0 {
+		return color.RGBA{}
+	}
+	return c
 }
 
 // rgbaToColor converts color.RGBA to Go's color.Color interface.
@@ -60,7 +271,7 @@ func rgbaToColor(c color.RGBA) color.Color {
 
 // Flush sends the rendered content to the terminal.
 func (r *TerminalRenderer) Flush() error {
-	return r.term.Display()
+	return r.term.Screen().Display(nil)
 }
 
 // FramebufferSize returns the recommended framebuffer size for the terminal.
@@ -69,6 +280,22 @@ func (r *TerminalRenderer) FramebufferSize() (width, height int) {
 	return r.width, r.height * 2
 }
 
+// DefaultCellAspect is the height of a typical terminal cell over its
+// width, at which a half-block pixel is square.
+const DefaultCellAspect = 2.0
+
+// ViewAspect returns the camera aspect ratio that shows a width by height
+// framebuffer undistorted in terminal cells cellAspect times as tall as
+// they are wide, each holding two pixels. A cellAspect of 0 means
+// DefaultCellAspect.
+func ViewAspect(width, height int, cellAspect float64) float64 {
+	if cellAspect <= 0 {
+		cellAspect = DefaultCellAspect
+	}
+	// Each pixel is 2/cellAspect times as wide as it is tall
+	return float64(width) / float64(height) * 2 / cellAspect
+}
+
 // Color is an alias for color.RGBA for convenience.
 type Color = color.RGBA
 
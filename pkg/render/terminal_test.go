@@ -0,0 +1,235 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	uv "github.com/charmbracelet/ultraviolet"
+)
+
+// newTestTerminalRenderer returns a renderer that draws into an in-memory screen.
+func newTestTerminalRenderer(width, height int) (*TerminalRenderer, uv.ScreenBuffer) {
+	scr := uv.NewScreenBuffer(width, height)
+	return &TerminalRenderer{screen: scr, width: width, height: height}, scr
+}
+
+func TestRenderDiff(t *testing.T) {
+	r, term := newTestTerminalRenderer(4, 2)
+	fb := NewFramebuffer(r.FramebufferSize())
+	fb.Clear(ColorBlue)
+
+	r.RenderDiff(fb)
+	if got := term.CellAt(0, 0).Content; got != "▀" {
+		t.Fatalf("first frame should draw every cell, got %q", got)
+	}
+
+	// Mark cells so we can tell which ones RenderDiff rewrites
+	marker := &uv.Cell{Content: "x", Width: 1}
+	term.SetCell(0, 0, marker)
+	term.SetCell(2, 1, marker)
+
+	// Change only the bottom pixel of cell (2, 1)
+	fb.SetPixel(2, 3, ColorRed)
+	r.RenderDiff(fb)
+
+	if got := term.CellAt(0, 0).Content; got != "x" {
+		t.Errorf("unchanged cell was rewritten: got %q", got)
+	}
+	if got := term.CellAt(2, 1).Content; got != "▀" {
+		t.Errorf("changed cell was not rewritten: got %q", got)
+	}
+
+	// Invalidate forces a full redraw
+	r.Invalidate()
+	r.RenderDiff(fb)
+	if got := term.CellAt(0, 0).Content; got != "▀" {
+		t.Errorf("Invalidate should redraw every cell, got %q", got)
+	}
+}
+
+func TestRenderRedrawsEveryCell(t *testing.T) {
+	r, term := newTestTerminalRenderer(3, 2)
+	fb := NewFramebuffer(r.FramebufferSize())
+	fb.Clear(ColorGreen)
+
+	r.Render(fb)
+	term.SetCell(1, 1, &uv.Cell{Content: "x", Width: 1})
+	r.Render(fb)
+
+	if got := term.CellAt(1, 1).Content; got != "▀" {
+		t.Errorf("Render should rewrite unchanged cells, got %q", got)
+	}
+}
+
+func TestTerminalRendererResize(t *testing.T) {
+	r, term := newTestTerminalRenderer(4, 3)
+	fb := NewFramebuffer(r.FramebufferSize())
+	fb.Clear(ColorGreen)
+	r.RenderDiff(fb)
+
+	// Growing or shrinking redraws every cell, even ones whose colors match
+	for _, size := range [][2]int{{2, 2}, {4, 3}} {
+		r.Resize(size[0], size[1])
+		fb.Resize(r.FramebufferSize())
+		fb.Clear(ColorGreen)
+		term.SetCell(1, 1, &uv.Cell{Content: "x", Width: 1})
+		r.RenderDiff(fb)
+		if got := term.CellAt(1, 1).Content; got != "▀" {
+			t.Errorf("%dx%d: cell not redrawn after Resize, got %q", size[0], size[1], got)
+		}
+	}
+}
+
+func TestInvalidateRow(t *testing.T) {
+	r, scr := newTestTerminalRenderer(3, 3)
+	fb := NewFramebuffer(r.FramebufferSize())
+	fb.Clear(ColorGreen)
+	r.RenderDiff(fb)
+
+	marker := &uv.Cell{Content: "x", Width: 1}
+	for row := range 3 {
+		scr.SetCell(0, row, marker)
+	}
+
+	r.InvalidateRow(1)
+	r.RenderDiff(fb)
+
+	for row, want := range []string{"x", "▀", "x"} {
+		if got := scr.CellAt(0, row).Content; got != want {
+			t.Errorf("row %d: got %q, want %q", row, got, want)
+		}
+	}
+}
+
+func TestSetTone(t *testing.T) {
+	tests := []struct {
+		name              string
+		gamma, brightness float64
+		in, want          uint8
+	}{
+		{"identity", 1, 1, 100, 100},
+		{"brighter", 1, 2, 100, 200},
+		{"clamped", 1, 2, 200, 255},
+		{"dimmer", 1, 0.5, 200, 100},
+		{"gamma lifts midtones", 2, 1, 64, 128},
+		{"gamma keeps black", 2, 1, 0, 0},
+		{"gamma keeps white", 2, 1, 255, 255},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, scr := newTestTerminalRenderer(1, 1)
+			fb := NewFramebuffer(r.FramebufferSize())
+			fb.Clear(RGB(tt.in, tt.in, tt.in))
+			r.SetTone(tt.gamma, tt.brightness)
+			r.RenderDiff(fb)
+
+			got, ok := scr.CellAt(0, 0).Style.Fg.(Color)
+			if !ok || absInt(int(got.R)-int(tt.want)) > 1 || got.G != got.R || got.B != got.R {
+				t.Errorf("displayed %v, want gray %d", scr.CellAt(0, 0).Style.Fg, tt.want)
+			}
+		})
+	}
+
+	// Changing the tone redraws cells whose framebuffer colors didn't change
+	r, scr := newTestTerminalRenderer(1, 1)
+	fb := NewFramebuffer(r.FramebufferSize())
+	fb.Clear(RGB(100, 100, 100))
+	r.RenderDiff(fb)
+	r.SetTone(1, 2)
+	r.RenderDiff(fb)
+	if got := scr.CellAt(0, 0).Style.Fg.(Color); got.R != 200 {
+		t.Errorf("after SetTone, displayed %v, want 200", got)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	r, scr := newTestTerminalRenderer(3, 2)
+	fb := NewFramebuffer(r.FramebufferSize())
+	fb.Clear(RGB(100, 100, 100))
+	fb.SetPixel(2, 3, ColorRed)
+	r.SetTone(1, 2)
+
+	gray, red := "\x1b[38;2;200;200;200m", "\x1b[48;2;255;0;0m"
+	want := gray + "\x1b[48;2;200;200;200m▀▀▀\x1b[0m\n" +
+		gray + "\x1b[48;2;200;200;200m▀▀" + red + "▀\x1b[0m\n"
+	if got := r.RenderString(fb); got != want {
+		t.Errorf("RenderString() = %q, want %q", got, want)
+	}
+	if got := scr.CellAt(0, 0); got != nil && got.Content != "" && got.Content != " " {
+		t.Errorf("RenderString drew %q on the terminal", got.Content)
+	}
+
+	// A framebuffer narrower than the terminal is cropped to it, not padded
+	narrow := NewFramebuffer(1, 4)
+	if got := strings.Count(r.RenderString(narrow), "▀"); got != 2 {
+		t.Errorf("narrow frame has %d cells, want 2", got)
+	}
+}
+
+func TestSetGlyph(t *testing.T) {
+	red, blue := "\x1b[38;2;255;0;0m", "\x1b[48;2;0;0;255m"
+	tests := []struct {
+		name    string
+		glyph   Glyph
+		content string
+		fg, bg  Color
+		want    string
+	}{
+		{"upper", GlyphUpperHalf, "▀", ColorRed, ColorBlue, red + blue + "▀"},
+		{"lower", GlyphLowerHalf, "▄", ColorBlue, ColorRed, "\x1b[38;2;0;0;255m\x1b[48;2;255;0;0m▄"},
+		{"full", GlyphFullBlock, "█", RGB(128, 0, 128), RGB(128, 0, 128), "\x1b[38;2;128;0;128m\x1b[48;2;128;0;128m█"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := ParseGlyph(tt.name)
+			if err != nil || g != tt.glyph {
+				t.Fatalf("ParseGlyph(%q) = %v, %v; want %v", tt.name, g, err, tt.glyph)
+			}
+
+			// Red over blue
+			r, scr := newTestTerminalRenderer(1, 1)
+			r.SetGlyph(g)
+			fb := NewFramebuffer(r.FramebufferSize())
+			fb.SetPixel(0, 0, ColorRed)
+			fb.SetPixel(0, 1, ColorBlue)
+
+			r.Render(fb)
+			cell := scr.CellAt(0, 0)
+			if cell.Content != tt.content || cell.Style.Fg != tt.fg || cell.Style.Bg != tt.bg {
+				t.Errorf("cell = %q fg %v bg %v; want %q fg %v bg %v", cell.Content, cell.Style.Fg, cell.Style.Bg, tt.content, tt.fg, tt.bg)
+			}
+			if got := r.RenderString(fb); got != tt.want+"\x1b[0m\n" {
+				t.Errorf("RenderString() = %q, want %q", got, tt.want+"\x1b[0m\n")
+			}
+		})
+	}
+
+	if _, err := ParseGlyph("braille"); err == nil {
+		t.Error("ParseGlyph accepted an unknown glyph")
+	}
+
+	// A full block over nothing would show the default foreground color
+	if content, _, _ := GlyphFullBlock.cell(Color{}, Color{}); content != " " {
+		t.Errorf("transparent full block drawn as %q, want a space", content)
+	}
+}
+
+func TestRenderOddHeight(t *testing.T) {
+	// A framebuffer a pixel short of the terminal's two rows per cell
+	r, scr := newTestTerminalRenderer(2, 2)
+	fb := NewFramebuffer(2, 3)
+	fb.Clear(ColorBlue)
+	fb.SetPixel(1, 2, ColorRed)
+
+	r.Render(fb)
+	for col, want := range []Color{ColorBlue, ColorRed} {
+		cell := scr.CellAt(col, 1)
+		if cell.Style.Fg != want || cell.Style.Bg != want {
+			t.Errorf("bottom cell %d is fg %v bg %v, want %v filling it", col, cell.Style.Fg, cell.Style.Bg, want)
+		}
+	}
+	if got := r.RenderString(fb); strings.Contains(got, "\x1b[49m") {
+		t.Errorf("RenderString() = %q, leaves the bottom half-row empty", got)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	_ "image/png"  // Register PNG decoder
 	"math"
 	"os"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/webp" // Register WebP decoder
 )
 
 // WrapMode determines how texture coordinates outside [0,1] are handled.
@@ -16,6 +20,7 @@ type WrapMode int
 const (
 	WrapRepeat WrapMode = iota // Tile the texture
 	WrapClamp                  // Clamp to edge
+	WrapMirror                 // Tile the texture, flipping every other copy
 )
 
 // FilterMode determines how texture sampling is performed.
@@ -26,7 +31,25 @@ const (
 	FilterBilinear                   // Bilinear interpolation (smooth)
 )
 
-// Texture holds a 2D image for texture mapping.
+// filterNames maps ParseFilter names to filter modes.
+var filterNames = map[string]FilterMode{
+	"nearest":  FilterNearest,
+	"bilinear": FilterBilinear,
+}
+
+// ParseFilter parses a filter mode name: nearest or bilinear.
+func ParseFilter(name string) (FilterMode, error) {
+	f, ok := filterNames[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown filter %q (use nearest or bilinear)", name)
+	}
+	return f, nil
+}
+
+// Texture holds a 2D image for texture mapping. Sampling only reads it,
+// so any number of goroutines may sample one texture at once, as the
+// rasterizer's workers do, provided nothing changes its fields or pixels
+// meanwhile.
 type Texture struct {
 	Width      int
 	Height     int
@@ -34,9 +57,11 @@ type Texture struct {
 	WrapU      WrapMode   // Horizontal wrap mode
 	WrapV      WrapMode   // Vertical wrap mode
 	FilterMode FilterMode // Sampling filter mode
+	IsSRGB     bool       // Pixels are sRGB encoded, so bilinear blends them in linear light
 }
 
-// NewTexture creates an empty texture with the given dimensions.
+// NewTexture creates an empty texture with the given dimensions, filtered
+// with FilterNearest so procedural patterns keep their hard edges.
 func NewTexture(width, height int) *Texture {
 	return &Texture{
 		Width:      width,
@@ -48,7 +73,8 @@ func NewTexture(width, height int) *Texture {
 	}
 }
 
-// LoadTexture loads a texture from an image file.
+// LoadTexture loads a texture from an image file, filtered with
+// FilterBilinear.
 func LoadTexture(path string) (*Texture, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -66,6 +92,7 @@ func LoadTexture(path string) (*Texture, error) {
 	height := bounds.Dy()
 
 	tex := NewTexture(width, height)
+	tex.FilterMode = FilterBilinear
 
 	for y := range height {
 		for x := range width {
@@ -84,13 +111,15 @@ func LoadTexture(path string) (*Texture, error) {
 	return tex, nil
 }
 
-// TextureFromImage creates a texture from an image.Image.
+// TextureFromImage creates a texture from an image.Image, filtered with
+// FilterBilinear.
 func TextureFromImage(img image.Image) *Texture {
 	bounds := img.Bounds()
 	width := bounds.Dx()
 	height := bounds.Dy()
 
 	tex := NewTexture(width, height)
+	tex.FilterMode = FilterBilinear
 
 	for y := range height {
 		for x := range width {
@@ -109,6 +138,53 @@ func TextureFromImage(img image.Image) *Texture {
 	return tex
 }
 
+// Resize returns a copy of the texture scaled down with a box filter so its
+// larger side is at most maxDim, keeping the aspect ratio. Returns t itself
+// if it already fits or maxDim <= 0.
+func (t *Texture) Resize(maxDim int) *Texture {
+	if maxDim <= 0 || (t.Width <= maxDim && t.Height <= maxDim) {
+		return t
+	}
+
+	scale := float64(maxDim) / float64(max(t.Width, t.Height))
+	w := max(int(math.Round(float64(t.Width)*scale)), 1)
+	h := max(int(math.Round(float64(t.Height)*scale)), 1)
+
+	out := NewTexture(w, h)
+	out.WrapU, out.WrapV, out.FilterMode, out.IsSRGB = t.WrapU, t.WrapV, t.FilterMode, t.IsSRGB
+
+	for y := range h {
+		// Source rows covered by this destination row, at least one
+		y0 := y * t.Height / h
+		y1 := max((y+1)*t.Height/h, y0+1)
+		for x := range w {
+			x0 := x * t.Width / w
+			x1 := max((x+1)*t.Width/w, x0+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := t.Pixels[sy*t.Width:]
+				for sx := x0; sx < x1; sx++ {
+					c := row[sx]
+					r += int(c.R)
+					g += int(c.G)
+					b += int(c.B)
+					a += int(c.A)
+					n++
+				}
+			}
+			out.Pixels[y*w+x] = Color{
+				R: uint8((r + n/2) / n),
+				G: uint8((g + n/2) / n),
+				B: uint8((b + n/2) / n),
+				A: uint8((a + n/2) / n),
+			}
+		}
+	}
+
+	return out
+}
+
 // NewCheckerTexture creates a procedural checkerboard texture.
 func NewCheckerTexture(width, height, checkSize int, c1, c2 Color) *Texture {
 	tex := NewTexture(width, height)
@@ -126,6 +202,66 @@ func NewCheckerTexture(width, height, checkSize int, c1, c2 Color) *Texture {
 	return tex
 }
 
+// gridDigits are 3x5 bitmaps of 0-9 for NewGridTexture, a row per byte
+// with the leftmost pixel in bit 2.
+var gridDigits = [10][5]uint8{
+	{7, 5, 5, 5, 7}, {2, 6, 2, 2, 7}, {7, 1, 7, 4, 7}, {7, 1, 3, 1, 7}, {5, 5, 7, 1, 1},
+	{7, 4, 7, 1, 7}, {7, 4, 7, 5, 7}, {7, 1, 1, 1, 1}, {7, 5, 7, 5, 7}, {7, 5, 7, 1, 7},
+}
+
+// NewGridTexture creates a UV inspection texture: a cells×cells grid of
+// squares, size pixels across in all, each shaded from the grid's corner
+// colors and labeled with its number, counted from the top left. Mirrored
+// or rotated UVs show as mirrored or rotated numbers. Cells is clamped to
+// 1-size, so every cell is at least a pixel.
+func NewGridTexture(size, cells int) *Texture {
+	size = max(size, 1)
+	cells = max(1, min(cells, size))
+	tex := NewTexture(size, size)
+	cell := size / cells
+	corners := [4]Color{RGB(220, 60, 60), RGB(60, 160, 220), RGB(240, 200, 60), RGB(80, 200, 100)}
+	for y := range size {
+		for x := range size {
+			cx, cy := min(x/cell, cells-1), min(y/cell, cells-1)
+			u, v := (float64(cx)+0.5)/float64(cells), (float64(cy)+0.5)/float64(cells)
+			c := lerpColor(lerpColor(corners[0], corners[1], u), lerpColor(corners[2], corners[3], u), v)
+			if (cx+cy)%2 == 1 {
+				c = MultiplyColor(c, 0.7)
+			}
+			if x%cell == 0 || y%cell == 0 {
+				c = RGB(30, 30, 30)
+			}
+			tex.SetPixel(x, y, c)
+		}
+	}
+
+	// Number each cell in white, digits scaled to fit a third of its width
+	scale := max(cell/12, 1)
+	for cy := range cells {
+		for cx := range cells {
+			label := strconv.Itoa(cy*cells + cx + 1)
+			width := (len(label)*4 - 1) * scale
+			left := cx*cell + (cell-width)/2
+			top := cy*cell + (cell-5*scale)/2
+			for i, d := range label {
+				for row, bits := range gridDigits[d-'0'] {
+					for col := range 3 {
+						if bits&(4>>col) == 0 {
+							continue
+						}
+						for sy := range scale {
+							for sx := range scale {
+								tex.SetPixel(left+(i*4+col)*scale+sx, top+row*scale+sy, RGB(255, 255, 255))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return tex
+}
+
 // NewGradientTexture creates a horizontal gradient texture.
 func NewGradientTexture(width, height int, left, right Color) *Texture {
 	tex := NewTexture(width, height)
@@ -178,24 +314,59 @@ func (t *Texture) wrapCoord(coord float64, mode WrapMode) float64 {
 		coord = coord - math.Floor(coord) // fmod to [0,1)
 	case WrapClamp:
 		coord = math.Max(0, math.Min(1, coord))
+	case WrapMirror:
+		coord -= 2 * math.Floor(coord/2) // To [0,2), then fold back
+		if coord > 1 {
+			coord = 2 - coord
+		}
 	}
 	return coord
 }
 
-// sampleNearest returns the nearest pixel.
+// sampleNearest returns the pixel whose center is nearest.
 func (t *Texture) sampleNearest(u, v float64) Color {
-	x := int(u * float64(t.Width))
-	y := int(v * float64(t.Height))
+	return t.GetPixel(nearestTexel(u, t.Width), nearestTexel(v, t.Height))
+}
 
-	// Clamp to valid range
-	if x >= t.Width {
-		x = t.Width - 1
-	}
-	if y >= t.Height {
-		y = t.Height - 1
+// nearestTexel returns the texel, along an axis size texels long, whose
+// center is nearest coord, a wrapped coordinate in [0, 1]. Texel i spans
+// [i, i+1) in pixel coordinates, so that's the one coord falls in. A coord
+// of 1 lands one past the edge, and a NaN one converts to an arbitrary int,
+// so both ends are clamped. Sample and the fast sampler share this so they
+// always pick the same texel.
+func nearestTexel(coord float64, size int) int {
+	return max(0, min(int(coord*float64(size)), size-1))
+}
+
+// nearestSampler is Sample specialized for the default modes,
+// FilterNearest and WrapRepeat on both axes, for the textured rasterizer's
+// inner loop: the mode switches and GetPixel's bounds checks are gone.
+type nearestSampler struct {
+	pixels        []Color
+	width, height int
+}
+
+// nearestSampler returns a fast sampler for t, or false if t's modes
+// need Sample.
+func (t *Texture) nearestSampler() (nearestSampler, bool) {
+	if t.FilterMode != FilterNearest || t.WrapU != WrapRepeat || t.WrapV != WrapRepeat ||
+		t.Width <= 0 || t.Height <= 0 || len(t.Pixels) < t.Width*t.Height {
+		return nearestSampler{}, false
 	}
+	return nearestSampler{
+		pixels: t.Pixels,
+		width:  t.Width,
+		height: t.Height,
+	}, true
+}
 
-	return t.GetPixel(x, y)
+// sample returns the texel Texture.Sample(u, v) would, and its row.
+func (s *nearestSampler) sample(u, v float64) (Color, int) {
+	u -= math.Floor(u)
+	v = 1 - (v - math.Floor(v))
+	x := nearestTexel(u, s.width)
+	y := nearestTexel(v, s.height)
+	return s.pixels[y*s.width+x], y
 }
 
 // sampleBilinear returns bilinearly interpolated color.
@@ -225,6 +396,10 @@ func (t *Texture) sampleBilinear(u, v float64) Color {
 	c01 := t.GetPixel(x0, y1)
 	c11 := t.GetPixel(x1, y1)
 
+	if t.IsSRGB {
+		return bilerpSRGB(c00, c10, c01, c11, tx, ty)
+	}
+
 	// Bilinear interpolation
 	top := lerpColor(c00, c10, tx)
 	bot := lerpColor(c01, c11, tx)
@@ -245,6 +420,14 @@ func (t *Texture) wrapPixelCoord(x, size int, mode WrapMode) int {
 		} else if x >= size {
 			x = size - 1
 		}
+	case WrapMirror:
+		x %= 2 * size
+		if x < 0 {
+			x += 2 * size
+		}
+		if x >= size {
+			x = 2*size - 1 - x
+		}
 	}
 	return x
 }
@@ -259,6 +442,64 @@ func lerpColor(a, b Color, t float64) Color {
 	}
 }
 
+// srgbToLinear maps each sRGB channel value to linear light in [0, 1].
+var srgbToLinear = func() (lut [256]float64) {
+	for i := range lut {
+		c := float64(i) / 255
+		if c <= 0.04045 {
+			lut[i] = c / 12.92
+		} else {
+			lut[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return lut
+}()
+
+// linearSteps is how finely linearToSRGB quantizes linear light. The
+// darkest sRGB steps are about 1/3300 apart in linear light, so 4096 keeps
+// them distinct.
+const linearSteps = 4096
+
+// linearToSRGB maps linear light, quantized to linearSteps, back to sRGB.
+var linearToSRGB = func() (lut [linearSteps + 1]uint8) {
+	for i := range lut {
+		l := float64(i) / linearSteps
+		var c float64
+		if l <= 0.0031308 {
+			c = l * 12.92
+		} else {
+			c = 1.055*math.Pow(l, 1/2.4) - 0.055
+		}
+		lut[i] = uint8(math.Round(c * 255))
+	}
+	return lut
+}()
+
+// encodeSRGB converts a linear light value in [0, 1] to sRGB.
+func encodeSRGB(l float64) uint8 {
+	return linearToSRGB[int(l*linearSteps+0.5)]
+}
+
+// bilerpSRGB blends four sRGB colors as sampleBilinear does, but in linear
+// light, so midpoints between light and dark aren't too dark. Alpha isn't
+// gamma encoded and blends as is.
+func bilerpSRGB(c00, c10, c01, c11 Color, tx, ty float64) Color {
+	w00 := (1 - tx) * (1 - ty)
+	w10 := tx * (1 - ty)
+	w01 := (1 - tx) * ty
+	w11 := tx * ty
+	blend := func(a, b, c, d uint8) float64 {
+		return srgbToLinear[a]*w00 + srgbToLinear[b]*w10 + srgbToLinear[c]*w01 + srgbToLinear[d]*w11
+	}
+	alpha := float64(c00.A)*w00 + float64(c10.A)*w10 + float64(c01.A)*w01 + float64(c11.A)*w11
+	return Color{
+		R: encodeSRGB(blend(c00.R, c10.R, c01.R, c11.R)),
+		G: encodeSRGB(blend(c00.G, c10.G, c01.G, c11.G)),
+		B: encodeSRGB(blend(c00.B, c10.B, c01.B, c11.B)),
+		A: uint8(alpha + 0.5),
+	}
+}
+
 // MultiplyColor multiplies a color by a scalar (for lighting).
 func MultiplyColor(c Color, intensity float64) Color {
 	return Color{
@@ -1,6 +1,9 @@
 package render
 
 import (
+	"image"
+	"math"
+	"sync"
 	"testing"
 )
 
@@ -14,6 +17,32 @@ func TestNewTexture(t *testing.T) {
 	}
 }
 
+func TestTextureFilterDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		tex  *Texture
+		want FilterMode
+	}{
+		{"image", TextureFromImage(image.NewRGBA(image.Rect(0, 0, 4, 4))), FilterBilinear},
+		{"checker", NewCheckerTexture(4, 4, 2, ColorWhite, ColorBlack), FilterNearest},
+		{"grid", NewGridTexture(16, 2), FilterNearest},
+	}
+	for _, tt := range tests {
+		if tt.tex.FilterMode != tt.want {
+			t.Errorf("%s: filter %v, want %v", tt.name, tt.tex.FilterMode, tt.want)
+		}
+	}
+
+	for name, want := range map[string]FilterMode{"nearest": FilterNearest, "Bilinear": FilterBilinear} {
+		if f, err := ParseFilter(name); err != nil || f != want {
+			t.Errorf("ParseFilter(%q) = %v, %v; want %v", name, f, err, want)
+		}
+	}
+	if _, err := ParseFilter("trilinear"); err == nil {
+		t.Error("ParseFilter accepted an unknown filter")
+	}
+}
+
 func TestCheckerTexture(t *testing.T) {
 	white := RGB(255, 255, 255)
 	black := RGB(0, 0, 0)
@@ -32,6 +61,48 @@ func TestCheckerTexture(t *testing.T) {
 	}
 }
 
+func TestGridTexture(t *testing.T) {
+	tex := NewGridTexture(64, 4)
+	if tex.Width != 64 || tex.Height != 64 {
+		t.Fatalf("size %dx%d, want 64x64", tex.Width, tex.Height)
+	}
+
+	white := RGB(255, 255, 255)
+	// Cells are 16 pixels; the "1" in the first is centered, its top row
+	// a single pixel in the middle column
+	if c := tex.GetPixel(7, 5); c != white {
+		t.Errorf("label pixel (7,5) = %v, want white", c)
+	}
+	if c := tex.GetPixel(6, 5); c == white {
+		t.Errorf("pixel beside the label (6,5) is white")
+	}
+	if c := tex.GetPixel(16, 8); c != RGB(30, 30, 30) {
+		t.Errorf("grid line (16,8) = %v, want dark", c)
+	}
+	if tex.GetPixel(2, 2) == tex.GetPixel(50, 50) {
+		t.Errorf("opposite corner cells share color %v", tex.GetPixel(2, 2))
+	}
+}
+
+func TestGridTextureClamp(t *testing.T) {
+	tests := []struct {
+		size, cells int
+		want        int // Texture width
+	}{
+		{8, 20, 8},
+		{8, 8, 8},
+		{8, 0, 8},
+		{8, -3, 8},
+		{0, 4, 1},
+	}
+	for _, tt := range tests {
+		tex := NewGridTexture(tt.size, tt.cells)
+		if tex.Width != tt.want || tex.Height != tt.want {
+			t.Errorf("NewGridTexture(%d, %d) is %dx%d, want %dx%d", tt.size, tt.cells, tex.Width, tex.Height, tt.want, tt.want)
+		}
+	}
+}
+
 func TestTextureSampleNearest(t *testing.T) {
 	tex := NewTexture(2, 2)
 	tex.SetPixel(0, 0, RGB(255, 0, 0))   // Red at top-left
@@ -50,6 +121,12 @@ func TestTextureSampleNearest(t *testing.T) {
 		{0.99, 0.99, RGB(0, 255, 0), "top-right (green)"},
 		{0.01, 0.01, RGB(0, 0, 255), "bottom-left (blue)"},
 		{0.99, 0.01, RGB(255, 255, 0), "bottom-right (yellow)"},
+		{0.25, 0.75, RGB(255, 0, 0), "top-left center"},
+		{0.75, 0.75, RGB(0, 255, 0), "top-right center"},
+		{0.25, 0.25, RGB(0, 0, 255), "bottom-left center"},
+		{0.75, 0.25, RGB(255, 255, 0), "bottom-right center"},
+		{0.49, 0.51, RGB(255, 0, 0), "top-left near the middle"},
+		{0.51, 0.49, RGB(255, 255, 0), "bottom-right near the middle"},
 	}
 
 	for _, tt := range tests {
@@ -58,6 +135,25 @@ func TestTextureSampleNearest(t *testing.T) {
 			t.Errorf("Sample(%v, %v) = %v, want %v (%s)", tt.u, tt.v, c, tt.expected, tt.name)
 		}
 	}
+
+	// At texel centers, nearest and bilinear agree
+	for _, tt := range tests[4:8] {
+		tex.FilterMode = FilterBilinear
+		want := tex.Sample(tt.u, tt.v)
+		tex.FilterMode = FilterNearest
+		if c := tex.Sample(tt.u, tt.v); c != want {
+			t.Errorf("%s: nearest %v, bilinear %v", tt.name, c, want)
+		}
+	}
+
+	// Clamped edges stay on the edge texels
+	tex.WrapU, tex.WrapV = WrapClamp, WrapClamp
+	if c := tex.Sample(1, 0); c != RGB(255, 255, 0) {
+		t.Errorf("Sample(1, 0) clamped = %v, want yellow", c)
+	}
+	if c := tex.Sample(-0.5, 1.5); c != RGB(255, 0, 0) {
+		t.Errorf("Sample(-0.5, 1.5) clamped = %v, want red", c)
+	}
 }
 
 func TestTextureWrapRepeat(t *testing.T) {
@@ -75,6 +171,48 @@ func TestTextureWrapRepeat(t *testing.T) {
 	}
 }
 
+func TestNearestSampler(t *testing.T) {
+	tex := NewGridTexture(37, 5) // Odd size, so texel edges don't fall on round UVs
+	fast, ok := tex.nearestSampler()
+	if !ok {
+		t.Fatal("default texture has no fast sampler")
+	}
+	for u := -1.5; u <= 1.5; u += 0.013 {
+		for v := -1.5; v <= 1.5; v += 0.017 {
+			if got, _ := fast.sample(u, v); got != tex.Sample(u, v) {
+				t.Fatalf("sample(%v, %v) = %v, Sample = %v", u, v, got, tex.Sample(u, v))
+			}
+		}
+	}
+
+	// Edges, and coordinates no texel holds, pick the same texel both ways
+	edges := []float64{0, 1, -1, 2, math.Nextafter(1, 0), -1e-300, math.NaN(), math.Inf(1), math.Inf(-1)}
+	for _, u := range edges {
+		for _, v := range edges {
+			if got, _ := fast.sample(u, v); got != tex.Sample(u, v) {
+				t.Errorf("sample(%v, %v) = %v, Sample = %v", u, v, got, tex.Sample(u, v))
+			}
+		}
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *Texture)
+	}{
+		{"bilinear", func(t *Texture) { t.FilterMode = FilterBilinear }},
+		{"clamped", func(t *Texture) { t.WrapV = WrapClamp }},
+		{"mirrored", func(t *Texture) { t.WrapU = WrapMirror }},
+		{"empty", func(t *Texture) { *t = Texture{} }},
+	}
+	for _, tt := range tests {
+		other := NewTexture(4, 4)
+		tt.setup(other)
+		if _, ok := other.nearestSampler(); ok {
+			t.Errorf("%s texture has a fast sampler", tt.name)
+		}
+	}
+}
+
 func TestTextureWrapClamp(t *testing.T) {
 	tex := NewTexture(2, 2)
 	tex.SetPixel(0, 0, RGB(255, 0, 0)) // Red top-left
@@ -98,6 +236,81 @@ func TestTextureWrapClamp(t *testing.T) {
 	}
 }
 
+func TestTextureWrapMirror(t *testing.T) {
+	tex := NewTexture(4, 1)
+	coords := []struct {
+		coord, want float64
+	}{
+		{-1.3, 0.7},
+		{-0.3, 0.3},
+		{0.7, 0.7},
+		{1, 1},
+		{1.3, 0.7},
+		{2, 0},
+		{2.4, 0.4},
+	}
+	for _, tt := range coords {
+		if got := tex.wrapCoord(tt.coord, WrapMirror); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("wrapCoord(%v) = %v, want %v", tt.coord, got, tt.want)
+		}
+	}
+
+	// Texels 0-3, then 3-0 mirrored, on either side
+	pixels := []struct {
+		x, want int
+	}{
+		{-5, 3}, {-4, 3}, {-1, 0}, {0, 0}, {3, 3}, {4, 3}, {7, 0}, {8, 0}, {9, 1},
+	}
+	for _, tt := range pixels {
+		if got := tex.wrapPixelCoord(tt.x, tex.Width, WrapMirror); got != tt.want {
+			t.Errorf("wrapPixelCoord(%d) = %d, want %d", tt.x, got, tt.want)
+		}
+	}
+
+	// Red then green: repeating past the right edge shows red, mirroring green
+	tex = NewTexture(2, 1)
+	tex.SetPixel(0, 0, ColorRed)
+	tex.SetPixel(1, 0, ColorGreen)
+	tex.WrapU = WrapMirror
+	if c := tex.Sample(1.25, 0.5); c != ColorGreen {
+		t.Errorf("Sample(1.25, 0.5) = %v, want green", c)
+	}
+	if c := tex.Sample(-0.25, 0.5); c != ColorRed {
+		t.Errorf("Sample(-0.25, 0.5) = %v, want red", c)
+	}
+}
+
+func TestBilinearSRGB(t *testing.T) {
+	tex := NewTexture(2, 1)
+	tex.SetPixel(0, 0, RGB(0, 0, 0))
+	tex.SetPixel(1, 0, RGB(255, 255, 255))
+	tex.FilterMode = FilterBilinear
+	tex.WrapU = WrapClamp
+
+	tests := []struct {
+		name   string
+		isSRGB bool
+		want   uint8
+	}{
+		{"blended as stored", false, 127},
+		{"blended in linear light", true, 188}, // Half of white's light
+	}
+	for _, tt := range tests {
+		tex.IsSRGB = tt.isSRGB
+		if c := tex.Sample(0.5, 0.5); c.R != tt.want || c.A != 255 {
+			t.Errorf("%s: midpoint %v, want gray %d", tt.name, c, tt.want)
+		}
+	}
+
+	// Every value survives the trip to linear light and back
+	for i := range 256 {
+		c := Color{R: uint8(i), G: uint8(255 - i), B: uint8(i / 2), A: uint8(i)}
+		if got := bilerpSRGB(c, c, c, c, 0.3, 0.7); got != c {
+			t.Fatalf("bilerpSRGB of %v alone = %v", c, got)
+		}
+	}
+}
+
 func TestMultiplyColor(t *testing.T) {
 	c := RGB(200, 100, 50)
 
@@ -151,3 +364,121 @@ func TestLerpColor(t *testing.T) {
 		t.Errorf("lerpColor(1.0) = %v, want white", end)
 	}
 }
+
+func TestTextureResize(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxDim        int
+		wantW, wantH  int
+	}{
+		{"fits", 64, 32, 512, 64, 32},
+		{"no limit", 1024, 1024, 0, 1024, 1024},
+		{"square", 1024, 1024, 256, 256, 256},
+		{"wide", 1000, 250, 100, 100, 25},
+		{"tall", 30, 3000, 100, 1, 100},
+		{"odd", 7, 5, 3, 3, 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tex := NewTexture(tc.width, tc.height)
+			got := tex.Resize(tc.maxDim)
+			if got.Width != tc.wantW || got.Height != tc.wantH {
+				t.Errorf("Resize(%d) = %dx%d, want %dx%d", tc.maxDim, got.Width, got.Height, tc.wantW, tc.wantH)
+			}
+			if len(got.Pixels) != got.Width*got.Height {
+				t.Errorf("pixel count %d, want %d", len(got.Pixels), got.Width*got.Height)
+			}
+		})
+	}
+}
+
+func TestTextureResizeAverages(t *testing.T) {
+	// 4x4 checkerboard of 2x2 blocks averages to one pixel per block
+	tex := NewCheckerTexture(4, 4, 2, RGB(200, 0, 0), RGB(0, 0, 100))
+	tex.WrapU = WrapClamp
+	got := tex.Resize(2)
+
+	if got.GetPixel(0, 0) != RGB(200, 0, 0) || got.GetPixel(1, 0) != RGB(0, 0, 100) {
+		t.Errorf("block averages wrong: %v %v", got.GetPixel(0, 0), got.GetPixel(1, 0))
+	}
+	if got.WrapU != WrapClamp {
+		t.Error("Resize should keep the wrap mode")
+	}
+
+	// A 1-pixel stripe pattern averages to its midpoint
+	stripes := NewCheckerTexture(4, 1, 1, RGB(0, 0, 0), RGB(200, 200, 200))
+	if c := stripes.Resize(2).GetPixel(0, 0); c != RGB(100, 100, 100) {
+		t.Errorf("stripe average = %v, want (100,100,100)", c)
+	}
+}
+
+func TestTextureSampleConcurrent(t *testing.T) {
+	// Run with -race: sampling from many goroutines must only read
+	for _, filter := range []FilterMode{FilterNearest, FilterBilinear} {
+		for _, wrap := range []WrapMode{WrapRepeat, WrapClamp} {
+			tex := NewGradientTexture(32, 32, RGB(10, 20, 30), RGB(240, 120, 60))
+			tex.FilterMode, tex.WrapU, tex.WrapV = filter, wrap, wrap
+
+			const samples = 500
+			uv := func(i int) (float64, float64) {
+				return float64(i)/samples*3 - 1, float64(i%37) / 37
+			}
+			want := make([]Color, samples)
+			for i := range want {
+				want[i] = tex.Sample(uv(i))
+			}
+
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Go(func() {
+					for i := range samples {
+						if got := tex.Sample(uv(i)); got != want[i] {
+							t.Errorf("filter %v, wrap %v: sample %d is %v, serially %v", filter, wrap, i, got, want[i])
+							return
+						}
+					}
+				})
+			}
+			wg.Wait()
+		}
+	}
+}
+
+func BenchmarkTextureSample(b *testing.B) {
+	tex := NewCheckerTexture(256, 256, 16, ColorWhite, ColorBlack)
+	// Sweep across the texture like a triangle row does
+	const steps = 1024
+	b.Run("Sample", func(b *testing.B) {
+		var c Color
+		for i := range b.N {
+			u := float64(i%steps) / steps * 3
+			c = tex.Sample(u, 0.4)
+		}
+		_ = c
+	})
+	b.Run("nearestSampler", func(b *testing.B) {
+		fast, _ := tex.nearestSampler()
+		var c Color
+		for i := range b.N {
+			u := float64(i%steps) / steps * 3
+			c, _ = fast.sample(u, 0.4)
+		}
+		_ = c
+	})
+}
+
+func BenchmarkTextureSampleParallel(b *testing.B) {
+	tex := NewCheckerTexture(256, 256, 16, ColorWhite, ColorBlack)
+	tex.FilterMode = FilterBilinear
+	const steps = 1024
+	b.RunParallel(func(pb *testing.PB) {
+		var c Color
+		for i := 0; pb.Next(); i++ {
+			u := float64(i%steps) / steps * 3
+			c = tex.Sample(u, 0.4)
+		}
+		_ = c
+	})
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"math"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+// uvOverlap tints pixels that more than one face covers in DrawMeshUV.
+var uvOverlap = RGB(255, 40, 40)
+
+// uvSquare returns the framebuffer square DrawMeshUV maps UV 0-1 onto:
+// the largest that fits, centered, as its left and top pixel and size.
+func (r *Rasterizer) uvSquare() (x, y, size float64) {
+	size = float64(min(r.width, r.height))
+	return (float64(r.width) - size) / 2, (float64(r.height) - size) / 2, size
+}
+
+// DrawMeshUV draws the mesh's texture layout instead of the mesh: each face
+// at its UV coordinates, with V up as in Texture.Sample. Faces are filled
+// from tex, or with fill if tex is nil, and pixels covered by more than one
+// face are tinted red so overlapping UVs stand out. Edges are drawn in edge.
+func (r *Rasterizer) DrawMeshUV(mesh MeshRenderer, tex *Texture, fill, edge Color) {
+	if cap(r.coverage) < r.width*r.height {
+		r.coverage = make([]uint8, r.width*r.height)
+	}
+	coverage := r.coverage[:r.width*r.height]
+	clear(coverage)
+
+	// UV 0 and 1 land on the centers of the square's edge pixels, so the
+	// layout's border edges are drawn
+	left, top, size := r.uvSquare()
+	toScreen := func(uv math3d.Vec2) (float64, float64) {
+		return left + 0.5 + uv.X*(size-1), top + 0.5 + (1-uv.Y)*(size-1)
+	}
+
+	n := mesh.TriangleCount()
+	corners := make([][3]math3d.Vec2, n)
+	for i := range n {
+		for j, idx := range mesh.GetFace(i) {
+			_, _, corners[i][j] = mesh.GetVertex(idx)
+		}
+	}
+
+	for _, uv := range corners {
+		x0, y0 := toScreen(uv[0])
+		x1, y1 := toScreen(uv[1])
+		x2, y2 := toScreen(uv[2])
+		area := (x1-x0)*(y2-y0) - (x2-x0)*(y1-y0)
+		if area == 0 {
+			continue
+		}
+
+		minX := max(int(math.Floor(min3(x0, x1, x2))), 0)
+		maxX := min(int(math.Ceil(max3(x0, x1, x2))), r.width-1)
+		minY := max(int(math.Floor(min3(y0, y1, y2))), 0)
+		maxY := min(int(math.Ceil(max3(y0, y1, y2))), r.height-1)
+		for y := minY; y <= maxY; y++ {
+			py := float64(y) + 0.5
+			for x := minX; x <= maxX; x++ {
+				px := float64(x) + 0.5
+				// Barycentric weights, positive inside whichever way the face winds
+				w0 := ((x1-px)*(y2-py) - (x2-px)*(y1-py)) / area
+				w1 := ((x2-px)*(y0-py) - (x0-px)*(y2-py)) / area
+				w2 := 1 - w0 - w1
+				if w0 < 0 || w1 < 0 || w2 < 0 {
+					continue
+				}
+
+				idx := y*r.width + x
+				if coverage[idx] < math.MaxUint8 {
+					coverage[idx]++
+				}
+				if coverage[idx] > 1 {
+					continue
+				}
+				color := fill
+				if tex != nil {
+					u := w0*uv[0].X + w1*uv[1].X + w2*uv[2].X
+					v := w0*uv[0].Y + w1*uv[1].Y + w2*uv[2].Y
+					color = r.sampleTexture(tex, u, v)
+				}
+				r.fb.Pixels[idx] = color
+			}
+		}
+	}
+
+	for i, c := range coverage {
+		if c > 1 {
+			r.fb.Pixels[i] = lerpColor(r.fb.Pixels[i], uvOverlap, 0.6)
+		}
+	}
+
+	for _, uv := range corners {
+		for j := range 3 {
+			ax, ay := toScreen(uv[j])
+			bx, by := toScreen(uv[(j+1)%3])
+			r.fb.DrawLine(int(ax), int(ay), int(bx), int(by), edge)
+		}
+	}
+}
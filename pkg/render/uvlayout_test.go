@@ -0,0 +1,60 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/taigrr/trophy/pkg/math3d"
+)
+
+func TestDrawMeshUV(t *testing.T) {
+	// Two faces far apart in space whose UVs both cover the lower left half
+	// of the texture, plus one covering the upper right half alone
+	mesh := &mockMesh{
+		vertices: []struct {
+			pos    math3d.Vec3
+			normal math3d.Vec3
+			uv     math3d.Vec2
+		}{
+			{pos: math3d.V3(0, 0, 0), uv: math3d.V2(0, 0)},
+			{pos: math3d.V3(1, 0, 0), uv: math3d.V2(1, 0)},
+			{pos: math3d.V3(0, 1, 0), uv: math3d.V2(0, 1)},
+			{pos: math3d.V3(5, 0, 0), uv: math3d.V2(0, 0)},
+			{pos: math3d.V3(6, 0, 0), uv: math3d.V2(1, 0)},
+			{pos: math3d.V3(5, 1, 0), uv: math3d.V2(0, 1)},
+			{pos: math3d.V3(9, 9, 9), uv: math3d.V2(1, 1)},
+		},
+		faces: [][3]int{{0, 1, 2}, {3, 5, 4}, {1, 6, 2}},
+	}
+	fill, edge, bg := RGB(100, 100, 100), RGB(255, 255, 255), RGB(0, 0, 0)
+
+	tests := []struct {
+		name string
+		tex  *Texture
+		want Color // Inside the upper right face
+	}{
+		{"fill", nil, fill},
+		{"textured", NewCheckerTexture(4, 4, 4, RGB(0, 0, 200), RGB(0, 0, 200)), RGB(0, 0, 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wider than tall, so the UV square is centered horizontally
+			r, fb := createTestRasterizer(140, 100)
+			fb.Clear(bg)
+			r.DrawMeshUV(mesh, tt.tex, fill, edge)
+
+			if got := fb.GetPixel(95, 25); got != tt.want {
+				t.Errorf("upper right face: got %v, want %v", got, tt.want)
+			}
+			overlap := fb.GetPixel(45, 75)
+			if overlap.R <= overlap.G || overlap.R <= overlap.B {
+				t.Errorf("overlapping faces: got %v, want a red tint", overlap)
+			}
+			if got := fb.GetPixel(10, 50); got != bg {
+				t.Errorf("left of the UV square: got %v, want background", got)
+			}
+			if got := fb.GetPixel(70, 99); got != edge {
+				t.Errorf("bottom edge: got %v, want edge color", got)
+			}
+		})
+	}
+}
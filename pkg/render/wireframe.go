@@ -1,20 +1,24 @@
 package render
 
 import (
+	"math"
+
 	"github.com/taigrr/trophy/pkg/math3d"
 )
 
 // Wireframe renders 3D wireframe objects.
 type Wireframe struct {
-	camera *Camera
-	fb     *Framebuffer
+	camera  *Camera
+	fb      *Framebuffer
+	Palette Palette // Colors for DrawAxes
 }
 
 // NewWireframe creates a new wireframe renderer.
 func NewWireframe(camera *Camera, fb *Framebuffer) *Wireframe {
 	return &Wireframe{
-		camera: camera,
-		fb:     fb,
+		camera:  camera,
+		fb:      fb,
+		Palette: DefaultPalette,
 	}
 }
 
@@ -120,9 +124,9 @@ func (w *Wireframe) DrawTransformedCube(transform math3d.Mat4, size float64, col
 // DrawAxes draws the coordinate axes at the origin.
 func (w *Wireframe) DrawAxes(length float64) {
 	origin := math3d.Zero3()
-	w.DrawLine3D(origin, math3d.V3(length, 0, 0), ColorRed)   // X axis
-	w.DrawLine3D(origin, math3d.V3(0, length, 0), ColorGreen) // Y axis
-	w.DrawLine3D(origin, math3d.V3(0, 0, length), ColorBlue)  // Z axis
+	w.DrawLine3D(origin, math3d.V3(length, 0, 0), w.Palette.AxisX)
+	w.DrawLine3D(origin, math3d.V3(0, length, 0), w.Palette.AxisY)
+	w.DrawLine3D(origin, math3d.V3(0, 0, length), w.Palette.AxisZ)
 }
 
 // DrawGrid draws a grid on the XZ plane at y=0.
@@ -155,3 +159,96 @@ func (w *Wireframe) DrawPoint(pos math3d.Vec3, size float64, color Color) {
 		color,
 	)
 }
+
+// WireframeColorMode selects how DrawMeshWireframeColored colors edges.
+type WireframeColorMode int
+
+const (
+	WireframeSolid      WireframeColorMode = iota // One color for every edge
+	WireframeByMaterial                           // Each material its own palette color
+	WireframeByNormal                             // Face normal direction, XYZ as RGB
+)
+
+// Palette holds the colors of debug overlays: the x-ray wireframe, the
+// coordinate axes, markers, and per-material wireframe edges.
+type Palette struct {
+	Wireframe           Color
+	AxisX, AxisY, AxisZ Color
+	Marker              Color   // Points picked on the model, e.g. for measuring
+	Cap                 Color   // Cross-section caps where a model is cut open
+	Materials           []Color // Cycled through by material index
+}
+
+// DefaultPalette has bright, saturated colors that read on dark backgrounds.
+var DefaultPalette = Palette{
+	Wireframe: RGB(0, 255, 128),
+	AxisX:     ColorRed,
+	AxisY:     ColorGreen,
+	AxisZ:     ColorBlue,
+	Marker:    RGB(255, 64, 64),
+	Cap:       RGB(230, 170, 60),
+	Materials: []Color{
+		RGB(230, 25, 75),
+		RGB(60, 180, 75),
+		RGB(255, 225, 25),
+		RGB(67, 99, 216),
+		RGB(245, 130, 49),
+		RGB(145, 30, 180),
+		RGB(66, 212, 244),
+		RGB(240, 50, 230),
+		RGB(191, 239, 69),
+		RGB(250, 190, 212),
+	},
+}
+
+// ColorBlindPalette uses the Okabe-Ito colors, which stay distinct under the
+// common forms of color blindness. The axes avoid pairing red with green.
+var ColorBlindPalette = Palette{
+	Wireframe: RGB(86, 180, 233),
+	AxisX:     RGB(213, 94, 0),
+	AxisY:     RGB(240, 228, 66),
+	AxisZ:     RGB(0, 114, 178),
+	Marker:    RGB(230, 159, 0),
+	Cap:       RGB(204, 121, 167),
+	Materials: []Color{
+		RGB(230, 159, 0),
+		RGB(86, 180, 233),
+		RGB(0, 158, 115),
+		RGB(240, 228, 66),
+		RGB(0, 114, 178),
+		RGB(213, 94, 0),
+		RGB(204, 121, 167),
+		RGB(255, 255, 255),
+	},
+}
+
+// MaterialColor returns the palette color for material index i, or
+// fallback for faces without a material or when the palette has none.
+func (p Palette) MaterialColor(i int, fallback Color) Color {
+	if i < 0 || len(p.Materials) == 0 {
+		return fallback
+	}
+	return p.Materials[i%len(p.Materials)]
+}
+
+// MaterialColor returns DefaultPalette's color for material index i, or
+// fallback for faces without a material.
+func MaterialColor(i int, fallback Color) Color {
+	return DefaultPalette.MaterialColor(i, fallback)
+}
+
+// NormalColor maps a direction to a color, X, Y, and Z to red, green,
+// and blue, so faces pointing the same way share a color.
+func NormalColor(n math3d.Vec3) Color {
+	n = n.Normalize()
+	return RGB(uint8((n.X*0.5+0.5)*255), uint8((n.Y*0.5+0.5)*255), uint8((n.Z*0.5+0.5)*255))
+}
+
+// HeatColor maps t in 0-1 along a blue, cyan, green, yellow, red ramp, for
+// showing a scalar over a mesh. t outside 0-1 is clamped.
+func HeatColor(t float64) Color {
+	ramp := [...]Color{RGB(0, 0, 255), RGB(0, 255, 255), RGB(0, 255, 0), RGB(255, 255, 0), RGB(255, 0, 0)}
+	t = math.Max(0, math.Min(1, t)) * float64(len(ramp)-1)
+	i := min(int(t), len(ramp)-2)
+	return lerpColor(ramp[i], ramp[i+1], t-float64(i))
+}
@@ -0,0 +1,270 @@
+// Package teaview wraps trophy's renderer in a Bubble Tea component, so a
+// TUI can show a 3D model alongside its other views.
+package teaview
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/taigrr/trophy/pkg/math3d"
+	"github.com/taigrr/trophy/pkg/models"
+	"github.com/taigrr/trophy/pkg/render"
+)
+
+// Defaults for a new Model
+const (
+	defaultCols = 40
+	defaultRows = 20
+	defaultSpin = 0.8 // Radians per second
+)
+
+// Control limits
+const (
+	frameInterval = time.Second / 30
+	dragSpeed     = 0.05 // Radians per cell dragged
+	keyStep       = 0.15 // Radians per arrow key press
+	zoomStep      = 1.1
+	minZoom       = 0.2
+	maxZoom       = 5.0
+	maxPitch      = math.Pi / 2
+)
+
+// lastID numbers Models, so each only acts on its own ticks.
+var lastID atomic.Int64
+
+// tickMsg advances the spin of the Model with the same id. Resuming the
+// spin starts a new generation of ticks, so one still on its way from
+// before the pause is dropped rather than running alongside.
+type tickMsg struct {
+	id   int64
+	gen  int
+	time time.Time
+}
+
+// Model is a Bubble Tea component showing a mesh, spinning if asked to.
+// With controls on, dragging with the left mouse button or the arrow keys
+// turn it, the mouse wheel or +/- zoom, and space pauses the spin.
+type Model struct {
+	scene  *render.Scene
+	fb     *render.Framebuffer
+	center math3d.Vec3
+
+	cols, rows int
+	yaw, pitch float64
+	zoom       float64
+	spin       float64 // Radians per second
+	paused     bool
+	controls   bool
+
+	dragging     bool
+	dragX, dragY int
+	lastTick     time.Time
+
+	id   int64
+	gen  int
+	view string
+}
+
+// Option configures a Model made by New.
+type Option func(*Model)
+
+// WithSize sets the component's size in terminal cells.
+func WithSize(cols, rows int) Option {
+	return func(m *Model) { m.cols, m.rows = cols, rows }
+}
+
+// WithBackground sets the color behind the model. A transparent color
+// leaves the terminal's own background showing.
+func WithBackground(c render.Color) Option {
+	return func(m *Model) { m.scene.Background = c }
+}
+
+// WithTexture draws texture on the model.
+func WithTexture(texture *render.Texture) Option {
+	return func(m *Model) { m.scene.Texture = texture }
+}
+
+// WithSpin sets how fast the model turns, in radians per second; 0 holds
+// it still.
+func WithSpin(radPerSec float64) Option {
+	return func(m *Model) { m.spin = radPerSec }
+}
+
+// WithControls sets whether the component handles mouse and key messages.
+// Leave it off if the surrounding program uses those keys itself.
+func WithControls(on bool) Option {
+	return func(m *Model) { m.controls = on }
+}
+
+// New returns a component showing mesh, framed to fit and spinning about
+// its vertical axis, with controls on.
+func New(mesh *models.Mesh, opts ...Option) Model {
+	m := Model{
+		scene:    render.NewScene(mesh),
+		center:   mesh.Center(),
+		cols:     defaultCols,
+		rows:     defaultRows,
+		zoom:     1,
+		spin:     defaultSpin,
+		controls: true,
+		id:       lastID.Add(1),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m.render()
+	return m
+}
+
+// Init starts the spin, if there is one.
+func (m Model) Init() tea.Cmd {
+	if m.spin == 0 {
+		return nil
+	}
+	return m.tick()
+}
+
+// tick schedules the next spin frame.
+func (m Model) tick() tea.Cmd {
+	id, gen := m.id, m.gen
+	return tea.Tick(frameInterval, func(t time.Time) tea.Msg { return tickMsg{id: id, gen: gen, time: t} })
+}
+
+// Update turns the model for its ticks and, with controls on, for mouse
+// and key input. The ticks stop while the spin is paused. It never quits;
+// that's left to the program around it.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tickMsg:
+		if msg.id != m.id || msg.gen != m.gen || m.paused || m.spin == 0 {
+			return m, nil
+		}
+		if !m.lastTick.IsZero() {
+			m.yaw += m.spin * msg.time.Sub(m.lastTick).Seconds()
+		}
+		m.lastTick = msg.time
+		m.render()
+		return m, m.tick()
+
+	case tea.MouseWheelMsg:
+		if !m.controls {
+			return m, nil
+		}
+		switch msg.Button {
+		case tea.MouseWheelUp:
+			m.Zoom(zoomStep)
+		case tea.MouseWheelDown:
+			m.Zoom(1 / zoomStep)
+		default:
+			return m, nil
+		}
+		m.render()
+
+	case tea.MouseClickMsg:
+		if m.controls && msg.Button == tea.MouseLeft {
+			m.dragging, m.dragX, m.dragY = true, msg.X, msg.Y
+		}
+
+	case tea.MouseReleaseMsg:
+		m.dragging = false
+
+	case tea.MouseMotionMsg:
+		if !m.controls || !m.dragging {
+			return m, nil
+		}
+		// Cells are about twice as tall as wide
+		m.Rotate(float64(msg.X-m.dragX)*dragSpeed, float64(msg.Y-m.dragY)*dragSpeed*2)
+		m.dragX, m.dragY = msg.X, msg.Y
+		m.render()
+
+	case tea.KeyPressMsg:
+		if !m.controls {
+			return m, nil
+		}
+		switch msg.String() {
+		case "left":
+			m.Rotate(-keyStep, 0)
+		case "right":
+			m.Rotate(keyStep, 0)
+		case "up":
+			m.Rotate(0, -keyStep)
+		case "down":
+			m.Rotate(0, keyStep)
+		case "+", "=":
+			m.Zoom(zoomStep)
+		case "-":
+			m.Zoom(1 / zoomStep)
+		case "space":
+			m.paused = !m.paused
+			m.lastTick = time.Time{}
+			if m.paused || m.spin == 0 {
+				return m, nil
+			}
+			m.gen++
+			return m, m.tick()
+		default:
+			return m, nil
+		}
+		m.render()
+	}
+	return m, nil
+}
+
+// View returns the last frame drawn, as 24-bit color text.
+func (m Model) View() string {
+	return m.view
+}
+
+// SetSize resizes the component to cols by rows terminal cells.
+func (m *Model) SetSize(cols, rows int) {
+	m.cols, m.rows = cols, rows
+	m.render()
+}
+
+// Size returns the component's size in terminal cells.
+func (m Model) Size() (cols, rows int) {
+	return m.cols, m.rows
+}
+
+// Rotate turns the model by yaw about its vertical axis and pitch about
+// its horizontal one, in radians. Pitch stops at straight up or down.
+func (m *Model) Rotate(yaw, pitch float64) {
+	m.yaw += yaw
+	m.pitch = max(-maxPitch, min(m.pitch+pitch, maxPitch))
+}
+
+// Zoom scales the model by factor, within limits.
+func (m *Model) Zoom(factor float64) {
+	m.zoom = max(minZoom, min(m.zoom*factor, maxZoom))
+}
+
+// Scene returns the scene the component draws, to adjust its light,
+// color, and other settings. Changes show from the next frame.
+func (m Model) Scene() *render.Scene {
+	return m.scene
+}
+
+// render draws the model in its current pose, caching the frame for View.
+func (m *Model) render() {
+	if m.cols <= 0 || m.rows <= 0 {
+		m.view = ""
+		return
+	}
+	switch {
+	case m.fb == nil:
+		m.fb = render.NewFramebuffer(m.cols, m.rows*2)
+	case m.fb.Width != m.cols || m.fb.Height != m.rows*2:
+		m.fb.Resize(m.cols, m.rows*2)
+	}
+
+	// Turn and scale about the mesh's center, which the camera is framed on
+	m.scene.Transform = math3d.Translate(m.center).
+		Mul(math3d.ScaleUniform(m.zoom)).
+		Mul(math3d.RotateX(m.pitch)).
+		Mul(math3d.RotateY(m.yaw)).
+		Mul(math3d.Translate(m.center.Scale(-1)))
+	m.scene.RenderTo(m.fb)
+	m.view = m.fb.ANSI()
+}
@@ -0,0 +1,94 @@
+package teaview
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/taigrr/trophy/pkg/math3d"
+	"github.com/taigrr/trophy/pkg/models"
+)
+
+// testCube returns a unit cube.
+func testCube() *models.Mesh {
+	mesh := models.NewMesh("cube")
+	for _, p := range []math3d.Vec3{
+		{X: -1, Y: -1, Z: -1}, {X: 1, Y: -1, Z: -1}, {X: 1, Y: 1, Z: -1}, {X: -1, Y: 1, Z: -1},
+		{X: -1, Y: -1, Z: 1}, {X: 1, Y: -1, Z: 1}, {X: 1, Y: 1, Z: 1}, {X: -1, Y: 1, Z: 1},
+	} {
+		mesh.Vertices = append(mesh.Vertices, models.MeshVertex{Position: p, Normal: p.Normalize()})
+	}
+	for _, f := range [][3]int{
+		{0, 2, 1}, {0, 3, 2}, {4, 5, 6}, {4, 6, 7},
+		{0, 1, 5}, {0, 5, 4}, {3, 6, 2}, {3, 7, 6},
+		{0, 4, 7}, {0, 7, 3}, {1, 2, 6}, {1, 6, 5},
+	} {
+		mesh.Faces = append(mesh.Faces, models.Face{V: f})
+	}
+	mesh.CalculateBounds()
+	return mesh
+}
+
+func TestView(t *testing.T) {
+	m := New(testCube(), WithSize(30, 12))
+	lines := strings.Split(strings.TrimSuffix(m.View(), "\n"), "\n")
+	if len(lines) != 12 {
+		t.Fatalf("view has %d lines, want 12", len(lines))
+	}
+	if n := strings.Count(lines[0], "▀"); n != 30 {
+		t.Errorf("line has %d cells, want 30", n)
+	}
+
+	m.SetSize(20, 5)
+	if n := strings.Count(m.View(), "\n"); n != 5 {
+		t.Errorf("resized view has %d lines, want 5", n)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	start := time.Now()
+	space := tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}
+	tests := []struct {
+		name     string
+		opts     []Option
+		msgs     []tea.Msg
+		changed  bool
+		wantTick bool
+	}{
+		{"first tick", nil, []tea.Msg{tickMsg{time: start}}, false, true},
+		{"spin", nil, []tea.Msg{tickMsg{time: start}, tickMsg{time: start.Add(time.Second)}}, true, true},
+		{"held still", []Option{WithSpin(0)}, []tea.Msg{tickMsg{time: start}, tickMsg{time: start.Add(time.Second)}}, false, false},
+		{"other model's tick", nil, []tea.Msg{tickMsg{id: -1, time: start}, tickMsg{id: -1, time: start.Add(time.Second)}}, false, false},
+		{"paused", nil, []tea.Msg{tickMsg{time: start}, space, tickMsg{time: start.Add(time.Second)}}, false, false},
+		{"resumed", nil, []tea.Msg{space, space}, false, true},
+		{"tick from before pause", nil, []tea.Msg{space, space, tickMsg{time: start}}, false, false},
+		{"arrow key", []Option{WithSpin(0)}, []tea.Msg{tea.KeyPressMsg{Code: tea.KeyRight}}, true, false},
+		{"zoom", []Option{WithSpin(0)}, []tea.Msg{tea.MouseWheelMsg{Button: tea.MouseWheelUp}}, true, false},
+		{"drag", []Option{WithSpin(0)}, []tea.Msg{
+			tea.MouseClickMsg{X: 5, Y: 5, Button: tea.MouseLeft},
+			tea.MouseMotionMsg{X: 10, Y: 5, Button: tea.MouseLeft},
+		}, true, false},
+		{"controls off", []Option{WithSpin(0), WithControls(false)}, []tea.Msg{tea.KeyPressMsg{Code: tea.KeyRight}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(testCube(), append([]Option{WithSize(30, 12)}, tt.opts...)...)
+			before := m.View()
+			var cmd tea.Cmd
+			for _, msg := range tt.msgs {
+				if tick, ok := msg.(tickMsg); ok && tick.id == 0 {
+					tick.id = m.id
+					msg = tick
+				}
+				m, cmd = m.Update(msg)
+			}
+			if changed := m.View() != before; changed != tt.changed {
+				t.Errorf("view changed = %v, want %v", changed, tt.changed)
+			}
+			if (cmd != nil) != tt.wantTick {
+				t.Errorf("next tick scheduled = %v, want %v", cmd != nil, tt.wantTick)
+			}
+		})
+	}
+}